@@ -0,0 +1,112 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+)
+
+// blank assignment to verify that Tenant implements conversion.Convertible
+var _ conversion.Convertible = &Tenant{}
+
+// ConvertTo converts this Tenant to the Hub version (v1beta1).
+func (src *Tenant) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*capabilitiesv1beta1.Tenant)
+	if !ok {
+		return fmt.Errorf("%T is not a *v1beta1.Tenant", dstRaw)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+
+	// Spec
+	dst.Spec.Username = src.Spec.Username
+	dst.Spec.Email = src.Spec.Email
+	dst.Spec.OrganizationName = src.Spec.OrganizationName
+	dst.Spec.SystemMasterUrl = src.Spec.SystemMasterUrl
+	dst.Spec.TenantSecretRef = src.Spec.TenantSecretRef
+	dst.Spec.PasswordCredentialsRef = src.Spec.PasswordCredentialsRef
+	dst.Spec.MasterCredentialsRef = src.Spec.MasterCredentialsRef
+	dst.Spec.Suspended = src.Spec.Suspended
+	if src.Spec.AccessTokenRotationInterval != nil {
+		interval := *src.Spec.AccessTokenRotationInterval
+		dst.Spec.AccessTokenRotationInterval = &interval
+	}
+
+	// Status
+	dst.Status.TenantId = src.Status.TenantId
+	dst.Status.AdminId = src.Status.AdminId
+	dst.Status.ProviderAccountHost = src.Status.ProviderAccountHost
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	if src.Status.AccessTokenID != nil {
+		accessTokenID := *src.Status.AccessTokenID
+		dst.Status.AccessTokenID = &accessTokenID
+	}
+	dst.Status.AccessTokenRotationTime = src.Status.AccessTokenRotationTime.DeepCopy()
+	dst.Status.AccessTokenRotationRequest = src.Status.AccessTokenRotationRequest
+	if src.Status.PreviousAccessTokenIDs != nil {
+		dst.Status.PreviousAccessTokenIDs = append([]int64{}, src.Status.PreviousAccessTokenIDs...)
+	}
+	dst.Status.Conditions = src.Status.Conditions.Copy()
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (dst *Tenant) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*capabilitiesv1beta1.Tenant)
+	if !ok {
+		return fmt.Errorf("%T is not a *v1beta1.Tenant", srcRaw)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+
+	// Spec
+	dst.Spec.Username = src.Spec.Username
+	dst.Spec.Email = src.Spec.Email
+	dst.Spec.OrganizationName = src.Spec.OrganizationName
+	dst.Spec.SystemMasterUrl = src.Spec.SystemMasterUrl
+	dst.Spec.TenantSecretRef = src.Spec.TenantSecretRef
+	dst.Spec.PasswordCredentialsRef = src.Spec.PasswordCredentialsRef
+	dst.Spec.MasterCredentialsRef = src.Spec.MasterCredentialsRef
+	dst.Spec.Suspended = src.Spec.Suspended
+	if src.Spec.AccessTokenRotationInterval != nil {
+		interval := *src.Spec.AccessTokenRotationInterval
+		dst.Spec.AccessTokenRotationInterval = &interval
+	}
+
+	// Status
+	dst.Status.TenantId = src.Status.TenantId
+	dst.Status.AdminId = src.Status.AdminId
+	dst.Status.ProviderAccountHost = src.Status.ProviderAccountHost
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	if src.Status.AccessTokenID != nil {
+		accessTokenID := *src.Status.AccessTokenID
+		dst.Status.AccessTokenID = &accessTokenID
+	}
+	dst.Status.AccessTokenRotationTime = src.Status.AccessTokenRotationTime.DeepCopy()
+	dst.Status.AccessTokenRotationRequest = src.Status.AccessTokenRotationRequest
+	if src.Status.PreviousAccessTokenIDs != nil {
+		dst.Status.PreviousAccessTokenIDs = append([]int64{}, src.Status.PreviousAccessTokenIDs...)
+	}
+	dst.Status.Conditions = src.Status.Conditions.Copy()
+
+	return nil
+}
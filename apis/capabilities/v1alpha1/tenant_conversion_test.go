@@ -0,0 +1,68 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/common"
+)
+
+func TestTenantConversionRoundTrip(t *testing.T) {
+	accessTokenID := int64(5)
+	rotationTime := metav1.NewTime(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC))
+	src := &Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ecorp-tenant",
+			Namespace:   "operator-test",
+			Annotations: map[string]string{"a": "b"},
+			Finalizers:  []string{"tenant.capabilities.3scale.net/finalizer"},
+		},
+		Spec: TenantSpec{
+			Username:                    "admin",
+			Email:                       "admin@example.com",
+			OrganizationName:            "ECorp",
+			SystemMasterUrl:             "https://master.example.com",
+			TenantSecretRef:             corev1.SecretReference{Name: "ecorp-tenant-secret", Namespace: "operator-test"},
+			PasswordCredentialsRef:      corev1.SecretReference{Name: "ecorp-admin-secret"},
+			MasterCredentialsRef:        corev1.SecretReference{Name: "system-seed"},
+			Suspended:                   true,
+			AccessTokenRotationInterval: &metav1.Duration{Duration: 2160 * time.Hour},
+		},
+		Status: TenantStatus{
+			TenantId:                   2,
+			AdminId:                    3,
+			ProviderAccountHost:        "https://ecorp-admin.example.com",
+			ObservedGeneration:         4,
+			AccessTokenID:              &accessTokenID,
+			AccessTokenRotationTime:    &rotationTime,
+			AccessTokenRotationRequest: "2026-01-01",
+			PreviousAccessTokenIDs:     []int64{5, 6},
+			Conditions: common.Conditions{
+				{Type: "Ready", Status: corev1.ConditionTrue},
+			},
+		},
+	}
+
+	hub := &capabilitiesv1beta1.Tenant{}
+	if err := src.ConvertTo(hub); err != nil {
+		t.Fatalf("unexpected error converting to hub: %v", err)
+	}
+
+	if hub.Spec.OrganizationName != src.Spec.OrganizationName || !hub.Spec.Suspended {
+		t.Errorf("unexpected hub spec: %v", hub.Spec)
+	}
+
+	dst := &Tenant{}
+	if err := dst.ConvertFrom(hub); err != nil {
+		t.Fatalf("unexpected error converting from hub: %v", err)
+	}
+
+	if diff := cmp.Diff(src, dst); diff != "" {
+		t.Errorf("round trip conversion mismatch (-want +got):\n%s", diff)
+	}
+}
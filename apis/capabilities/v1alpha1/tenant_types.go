@@ -17,8 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"fmt"
-	"strings"
+	"github.com/3scale/3scale-operator/pkg/common"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -39,6 +38,17 @@ type TenantSpec struct {
 	TenantSecretRef        v1.SecretReference `json:"tenantSecretRef"`
 	PasswordCredentialsRef v1.SecretReference `json:"passwordCredentialsRef"`
 	MasterCredentialsRef   v1.SecretReference `json:"masterCredentialsRef"`
+
+	// Suspended desired state of the tenant account.
+	// Suspended tenants cannot use the admin portal nor the APIs.
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+
+	// AccessTokenRotationInterval enables periodic rotation of the tenant access token.
+	// A new admin access token replaces the one in the tenant secret when the interval has elapsed since the last rotation.
+	// For example: 2160h (90 days)
+	// +optional
+	AccessTokenRotationInterval *metav1.Duration `json:"accessTokenRotationInterval,omitempty"`
 }
 
 // TenantStatus defines the observed state of Tenant
@@ -48,12 +58,46 @@ type TenantStatus struct {
 
 	TenantId int64 `json:"tenantId"`
 	AdminId  int64 `json:"adminId"`
+
+	// 3scale control plane host
+	// +optional
+	ProviderAccountHost string `json:"providerAccountHost,omitempty"`
+
+	// ObservedGeneration reflects the generation of the most recently observed Tenant Spec.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// AccessTokenID is the ID of the admin access token created by the operator and stored in the tenant secret
+	// +optional
+	AccessTokenID *int64 `json:"accessTokenID,omitempty"`
+
+	// AccessTokenRotationTime is the time of the latest tenant access token rotation
+	// +optional
+	AccessTokenRotationTime *metav1.Time `json:"accessTokenRotationTime,omitempty"`
+
+	// AccessTokenRotationRequest is the value of the rotate access token annotation handled on the latest rotation
+	// +optional
+	AccessTokenRotationRequest string `json:"accessTokenRotationRequest,omitempty"`
+
+	// PreviousAccessTokenIDs are the IDs of the access tokens replaced by rotations and not revoked yet.
+	// Revocations are retried on every reconciliation
+	// +optional
+	PreviousAccessTokenIDs []int64 `json:"previousAccessTokenIDs,omitempty"`
+
+	// Current state of the tenant resource.
+	// Conditions represent the latest available observations of an object's state
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions common.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,2,rep,name=conditions"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 
-// Tenant is the Schema for the tenants API
+// Tenant is the Schema for the tenants API.
+// Deprecated: use capabilities.3scale.net/v1beta1 Tenant instead.
+// v1alpha1 is still served and converted to the v1beta1 storage version by the conversion webhook.
 // +kubebuilder:resource:path=tenants,scope=Namespaced
 // +operator-sdk:csv:customresourcedefinitions:displayName="Tenant"
 type Tenant struct {
@@ -64,21 +108,6 @@ type Tenant struct {
 	Status TenantStatus `json:"status,omitempty"`
 }
 
-// SetDefaults sets the default vaules for the tenant spec and returns true if the spec was changed
-func (t *Tenant) SetDefaults() bool {
-	changed := false
-	ts := &t.Spec
-	if ts.TenantSecretRef.Name == "" {
-		ts.TenantSecretRef.Name = fmt.Sprintf("%s-%s", strings.ToLower(t.Name), strings.ToLower(t.Spec.OrganizationName))
-		changed = true
-	}
-	if ts.TenantSecretRef.Namespace == "" {
-		ts.TenantSecretRef.Namespace = t.Namespace
-		changed = true
-	}
-	return changed
-}
-
 // +kubebuilder:object:root=true
 
 // TenantList contains a list of Tenant
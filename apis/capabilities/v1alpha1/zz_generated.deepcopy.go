@@ -21,6 +21,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/3scale/3scale-operator/pkg/common"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -29,8 +31,8 @@ func (in *Tenant) DeepCopyInto(out *Tenant) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tenant.
@@ -89,6 +91,11 @@ func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
 	out.TenantSecretRef = in.TenantSecretRef
 	out.PasswordCredentialsRef = in.PasswordCredentialsRef
 	out.MasterCredentialsRef = in.MasterCredentialsRef
+	if in.AccessTokenRotationInterval != nil {
+		in, out := &in.AccessTokenRotationInterval, &out.AccessTokenRotationInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSpec.
@@ -104,6 +111,27 @@ func (in *TenantSpec) DeepCopy() *TenantSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
 	*out = *in
+	if in.AccessTokenID != nil {
+		in, out := &in.AccessTokenID, &out.AccessTokenID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.AccessTokenRotationTime != nil {
+		in, out := &in.AccessTokenRotationTime, &out.AccessTokenRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PreviousAccessTokenIDs != nil {
+		in, out := &in.PreviousAccessTokenIDs, &out.PreviousAccessTokenIDs
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(common.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantStatus.
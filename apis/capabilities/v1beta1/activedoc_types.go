@@ -71,6 +71,10 @@ type ActiveDocOpenAPIRefSpec struct {
 	// +kubebuilder:validation:Pattern=`^https?:\/\/.*$`
 	// +optional
 	URL *string `json:"url,omitempty"`
+
+	// URLOptions options to fetch the OpenAPI Document from the remote URL
+	// +optional
+	URLOptions *OpenAPIURLOptionsSpec `json:"urlOptions,omitempty"`
 }
 
 // ActiveDocSpec defines the desired state of ActiveDoc
@@ -82,6 +86,17 @@ type ActiveDocSpec struct {
 	// +optional
 	ProviderAccountRef *corev1.LocalObjectReference `json:"providerAccountRef,omitempty"`
 
+	// ProviderAccountNamespace is the namespace of the ProviderAccountRef secret.
+	// Defaults to the namespace of the custom resource. Secrets in other namespaces must allow
+	// the namespace of the custom resource in the capabilities.3scale.net/allowed-namespaces annotation
+	// +optional
+	ProviderAccountNamespace *string `json:"providerAccountNamespace,omitempty"`
+
+	// TenantRef references the Tenant custom resource holding account provider credentials in its tenant secret.
+	// The Tenant must be in the same namespace. ProviderAccountRef and TenantRef are mutually exclusive
+	// +optional
+	TenantRef *corev1.LocalObjectReference `json:"tenantRef,omitempty"`
+
 	// Name is human readable name for the activedoc
 	Name string `json:"name"`
 
@@ -214,6 +229,16 @@ func (a *ActiveDoc) SetDefaults(logger logr.Logger) bool {
 
 func (a *ActiveDoc) Validate() field.ErrorList {
 	errors := field.ErrorList{}
+
+	errors = append(errors, validateProviderAccountReference(a.Spec.ProviderAccountRef, a.Spec.TenantRef)...)
+
+	specFldPath := field.NewPath("spec")
+	urlOptionsFldPath := specFldPath.Child("activeDocOpenAPIRef").Child("urlOptions")
+
+	if a.Spec.ActiveDocOpenAPIRef.URLOptions != nil && a.Spec.ActiveDocOpenAPIRef.URL == nil {
+		errors = append(errors, field.Invalid(urlOptionsFldPath, a.Spec.ActiveDocOpenAPIRef.URLOptions, "only supported for the url OpenAPI document source"))
+	}
+
 	return errors
 }
 
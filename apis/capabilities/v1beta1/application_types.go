@@ -0,0 +1,237 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/3scale/3scale-operator/pkg/common"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+const (
+	ApplicationKind = "Application"
+
+	// ApplicationInvalidConditionType represents that the combination of configuration
+	// in the spec is not supported. This is not a transient error, but
+	// indicates a state that must be fixed before progress can be made.
+	ApplicationInvalidConditionType common.ConditionType = "Invalid"
+
+	// ApplicationOrphanConditionType represents that the configuration in the spec
+	// contains reference to non existing resource.
+	// This is (should be) a transient error, but
+	// indicates a state that must be fixed before progress can be made.
+	// Example: the ApplicationSpec references non existing product resource
+	ApplicationOrphanConditionType common.ConditionType = "Orphan"
+
+	// ApplicationReadyConditionType indicates the application has been successfully synchronized.
+	// Steady state
+	ApplicationReadyConditionType common.ConditionType = "Ready"
+
+	// ApplicationFailedConditionType indicates that an error occurred during synchronization.
+	// The operator will retry.
+	ApplicationFailedConditionType common.ConditionType = "Failed"
+
+	// ApplicationUserKeySecretField indicates the secret field name with application's user key
+	ApplicationUserKeySecretField = "user_key"
+
+	// ApplicationAppIDSecretField indicates the secret field name with application's app id
+	ApplicationAppIDSecretField = "app_id"
+
+	// ApplicationAppKeySecretField indicates the secret field name with application's app key
+	ApplicationAppKeySecretField = "app_key"
+)
+
+// ApplicationSpec defines the desired state of Application
+type ApplicationSpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// Name is human readable name for the application
+	Name string `json:"name"`
+
+	// Description is a human readable text of the application
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// DeveloperAccountRef is the reference to the developer account owning the application
+	DeveloperAccountRef corev1.LocalObjectReference `json:"developerAccountRef"`
+
+	// ProductRef is the reference to the product the application subscribes to
+	ProductRef corev1.LocalObjectReference `json:"productRef"`
+
+	// ApplicationPlanSystemName is the system name of the product's application plan
+	ApplicationPlanSystemName string `json:"applicationPlanSystemName"`
+
+	// Suspended defines the desired state. Defaults to "false", ie, live
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+
+	// CredentialsSecretRef references the secret where the application credentials are written.
+	// Defaults to "<application resource name>-credentials"
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+
+	// ProviderAccountRef references account provider credentials
+	// +optional
+	ProviderAccountRef *corev1.LocalObjectReference `json:"providerAccountRef,omitempty"`
+
+	// ProviderAccountNamespace is the namespace of the ProviderAccountRef secret.
+	// Defaults to the namespace of the custom resource. Secrets in other namespaces must allow
+	// the namespace of the custom resource in the capabilities.3scale.net/allowed-namespaces annotation
+	// +optional
+	ProviderAccountNamespace *string `json:"providerAccountNamespace,omitempty"`
+
+	// TenantRef references the Tenant custom resource holding account provider credentials in its tenant secret.
+	// The Tenant must be in the same namespace. ProviderAccountRef and TenantRef are mutually exclusive
+	// +optional
+	TenantRef *corev1.LocalObjectReference `json:"tenantRef,omitempty"`
+}
+
+// ApplicationStatus defines the observed state of Application
+type ApplicationStatus struct {
+	// +optional
+	ID *int64 `json:"applicationID,omitempty"`
+
+	// +optional
+	AccountID *int64 `json:"accountID,omitempty"`
+
+	// +optional
+	ApplicationState *string `json:"applicationState,omitempty"`
+
+	// 3scale control plane host
+	// +optional
+	ProviderAccountHost string `json:"providerAccountHost,omitempty"`
+
+	// ObservedGeneration reflects the generation of the most recently observed Application Spec.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Current state of the 3scale application.
+	// Conditions represent the latest available observations of an object's state
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions common.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,2,rep,name=conditions"`
+}
+
+func (a *ApplicationStatus) Equals(other *ApplicationStatus, logger logr.Logger) bool {
+	if !reflect.DeepEqual(a.ID, other.ID) {
+		diff := cmp.Diff(a.ID, other.ID)
+		logger.V(1).Info("ID not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(a.AccountID, other.AccountID) {
+		diff := cmp.Diff(a.AccountID, other.AccountID)
+		logger.V(1).Info("AccountID not equal", "difference", diff)
+		return false
+	}
+
+	if a.ProviderAccountHost != other.ProviderAccountHost {
+		diff := cmp.Diff(a.ProviderAccountHost, other.ProviderAccountHost)
+		logger.V(1).Info("ProviderAccountHost not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(a.ApplicationState, other.ApplicationState) {
+		diff := cmp.Diff(a.ApplicationState, other.ApplicationState)
+		logger.V(1).Info("ApplicationState not equal", "difference", diff)
+		return false
+	}
+
+	if a.ObservedGeneration != other.ObservedGeneration {
+		diff := cmp.Diff(a.ObservedGeneration, other.ObservedGeneration)
+		logger.V(1).Info("ObservedGeneration not equal", "difference", diff)
+		return false
+	}
+
+	// Marshalling sorts by condition type
+	currentMarshaledJSON, _ := a.Conditions.MarshalJSON()
+	otherMarshaledJSON, _ := other.Conditions.MarshalJSON()
+	if string(currentMarshaledJSON) != string(otherMarshaledJSON) {
+		diff := cmp.Diff(string(currentMarshaledJSON), string(otherMarshaledJSON))
+		logger.V(1).Info("Conditions not equal", "difference", diff)
+		return false
+	}
+
+	return true
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Application is the Schema for the applications API
+type Application struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApplicationSpec   `json:"spec,omitempty"`
+	Status ApplicationStatus `json:"status,omitempty"`
+}
+
+func (a *Application) IsOrphan() bool {
+	return a.Status.Conditions.IsTrueFor(ApplicationOrphanConditionType)
+}
+
+// CredentialsSecretName returns the name of the secret with the application credentials
+func (a *Application) CredentialsSecretName() string {
+	if a.Spec.CredentialsSecretRef != nil && a.Spec.CredentialsSecretRef.Name != "" {
+		return a.Spec.CredentialsSecretRef.Name
+	}
+
+	return fmt.Sprintf("%s-credentials", a.Name)
+}
+
+func (a *Application) Validate() field.ErrorList {
+	errors := field.ErrorList{}
+
+	errors = append(errors, validateProviderAccountReference(a.Spec.ProviderAccountRef, a.Spec.TenantRef)...)
+
+	specFldPath := field.NewPath("spec")
+	if a.Spec.Name == "" {
+		errors = append(errors, field.Required(specFldPath.Child("name"), "application name required"))
+	}
+
+	if a.Spec.ApplicationPlanSystemName == "" {
+		errors = append(errors, field.Required(specFldPath.Child("applicationPlanSystemName"), "application plan system name required"))
+	}
+
+	return errors
+}
+
+// +kubebuilder:object:root=true
+
+// ApplicationList contains a list of Application
+type ApplicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Application `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Application{}, &ApplicationList{})
+}
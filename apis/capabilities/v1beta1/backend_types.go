@@ -48,6 +48,23 @@ const (
 	// BackendFailedConditionType indicates that an error occurred during synchronization.
 	// The operator will retry.
 	BackendFailedConditionType common.ConditionType = "Failed"
+
+	// BackendDeletingConditionType indicates the backend custom resource is being deleted
+	// and the 3scale backend is being removed.
+	BackendDeletingConditionType common.ConditionType = "Deleting"
+
+	// BackendDeletionBlockedConditionType indicates the 3scale backend cannot be removed
+	// because it is still used by some product.
+	// The operator will retry.
+	BackendDeletionBlockedConditionType common.ConditionType = "DeletionBlocked"
+
+	// BackendDriftedConditionType indicates that 3scale backend objects modified outside the operator
+	// have been corrected in the last synchronization of an unchanged spec.
+	BackendDriftedConditionType common.ConditionType = "Drifted"
+
+	// BackendDryRunConditionType indicates that the backend is in dry-run mode.
+	// The changes to 3scale objects are computed but not applied.
+	BackendDryRunConditionType common.ConditionType = "DryRun"
 )
 
 var (
@@ -102,6 +119,17 @@ type BackendSpec struct {
 	// ProviderAccountRef references account provider credentials
 	// +optional
 	ProviderAccountRef *corev1.LocalObjectReference `json:"providerAccountRef,omitempty"`
+
+	// ProviderAccountNamespace is the namespace of the ProviderAccountRef secret.
+	// Defaults to the namespace of the custom resource. Secrets in other namespaces must allow
+	// the namespace of the custom resource in the capabilities.3scale.net/allowed-namespaces annotation
+	// +optional
+	ProviderAccountNamespace *string `json:"providerAccountNamespace,omitempty"`
+
+	// TenantRef references the Tenant custom resource holding account provider credentials in its tenant secret.
+	// The Tenant must be in the same namespace. ProviderAccountRef and TenantRef are mutually exclusive
+	// +optional
+	TenantRef *corev1.LocalObjectReference `json:"tenantRef,omitempty"`
 }
 
 // BackendStatus defines the observed state of Backend
@@ -204,6 +232,8 @@ func (backend *Backend) SetDefaults(logger logr.Logger) bool {
 func (backend *Backend) Validate() field.ErrorList {
 	errors := field.ErrorList{}
 
+	errors = append(errors, validateProviderAccountReference(backend.Spec.ProviderAccountRef, backend.Spec.TenantRef)...)
+
 	// check hits metric exists
 	specFldPath := field.NewPath("spec")
 	metricsFldPath := specFldPath.Child("metrics")
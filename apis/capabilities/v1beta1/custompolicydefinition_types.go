@@ -25,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -78,6 +79,17 @@ type CustomPolicyDefinitionSpec struct {
 	// +optional
 	ProviderAccountRef *corev1.LocalObjectReference `json:"providerAccountRef,omitempty"`
 
+	// ProviderAccountNamespace is the namespace of the ProviderAccountRef secret.
+	// Defaults to the namespace of the custom resource. Secrets in other namespaces must allow
+	// the namespace of the custom resource in the capabilities.3scale.net/allowed-namespaces annotation
+	// +optional
+	ProviderAccountNamespace *string `json:"providerAccountNamespace,omitempty"`
+
+	// TenantRef references the Tenant custom resource holding account provider credentials in its tenant secret.
+	// The Tenant must be in the same namespace. ProviderAccountRef and TenantRef are mutually exclusive
+	// +optional
+	TenantRef *corev1.LocalObjectReference `json:"tenantRef,omitempty"`
+
 	// Name is the name of the custom policy
 	Name string `json:"name"`
 
@@ -159,6 +171,14 @@ type CustomPolicyDefinition struct {
 	Status CustomPolicyDefinitionStatus `json:"status,omitempty"`
 }
 
+func (c *CustomPolicyDefinition) Validate() field.ErrorList {
+	errors := field.ErrorList{}
+
+	errors = append(errors, validateProviderAccountReference(c.Spec.ProviderAccountRef, c.Spec.TenantRef)...)
+
+	return errors
+}
+
 // +kubebuilder:object:root=true
 
 // CustomPolicyDefinitionList contains a list of CustomPolicyDefinition
@@ -0,0 +1,29 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DeletionPolicyAnnotation defines what happens to the 3scale object
+	// when the custom resource is deleted.
+	// Valid values: "delete" (default) and "orphan"
+	DeletionPolicyAnnotation = "capabilities.3scale.net/deletion-policy"
+
+	// DeletionPolicyDelete removes the 3scale object when the custom resource is deleted
+	DeletionPolicyDelete = "delete"
+
+	// DeletionPolicyOrphan keeps the 3scale object when the custom resource is deleted
+	DeletionPolicyOrphan = "orphan"
+)
+
+// IsDeletionPolicyOrphan returns true when the object has been annotated
+// to keep the 3scale object on custom resource deletion
+func IsDeletionPolicyOrphan(obj metav1.Object) bool {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		return false
+	}
+
+	return annotations[DeletionPolicyAnnotation] == DeletionPolicyOrphan
+}
@@ -0,0 +1,30 @@
+package v1beta1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsDeletionPolicyOrphan(t *testing.T) {
+	cases := []struct {
+		testName    string
+		annotations map[string]string
+		expected    bool
+	}{
+		{"nil annotations", nil, false},
+		{"no deletion policy annotation", map[string]string{"a": "b"}, false},
+		{"delete policy", map[string]string{DeletionPolicyAnnotation: DeletionPolicyDelete}, false},
+		{"unknown policy", map[string]string{DeletionPolicyAnnotation: "unknown"}, false},
+		{"orphan policy", map[string]string{DeletionPolicyAnnotation: DeletionPolicyOrphan}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			product := &Product{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if IsDeletionPolicyOrphan(product) != tc.expected {
+				subT.Errorf("expected orphan deletion policy: %t", tc.expected)
+			}
+		})
+	}
+}
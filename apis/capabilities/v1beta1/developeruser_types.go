@@ -88,6 +88,17 @@ type DeveloperUserSpec struct {
 	// ProviderAccountRef references account provider credentials
 	// +optional
 	ProviderAccountRef *corev1.LocalObjectReference `json:"providerAccountRef,omitempty"`
+
+	// ProviderAccountNamespace is the namespace of the ProviderAccountRef secret.
+	// Defaults to the namespace of the custom resource. Secrets in other namespaces must allow
+	// the namespace of the custom resource in the capabilities.3scale.net/allowed-namespaces annotation
+	// +optional
+	ProviderAccountNamespace *string `json:"providerAccountNamespace,omitempty"`
+
+	// TenantRef references the Tenant custom resource holding account provider credentials in its tenant secret.
+	// The Tenant must be in the same namespace. ProviderAccountRef and TenantRef are mutually exclusive
+	// +optional
+	TenantRef *corev1.LocalObjectReference `json:"tenantRef,omitempty"`
 }
 
 // DeveloperUserStatus defines the observed state of DeveloperUser
@@ -184,6 +195,8 @@ func (s *DeveloperUser) IsAdmin() bool {
 func (a *DeveloperUser) Validate() field.ErrorList {
 	errors := field.ErrorList{}
 
+	errors = append(errors, validateProviderAccountReference(a.Spec.ProviderAccountRef, a.Spec.TenantRef)...)
+
 	// Email validation
 	emailFldPath := field.NewPath("spec").Child("email")
 	if !helper.IsEmailValid(a.Spec.Email) {
@@ -0,0 +1,24 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DryRunAnnotation enables the dry-run mode of the custom resource.
+	// In dry-run mode, the changes required to synchronize the 3scale objects
+	// are computed and reported, but not applied.
+	// Valid values: "true" and "false" (default)
+	DryRunAnnotation = "capabilities.3scale.net/dry-run"
+)
+
+// IsDryRun returns true when the object has been annotated
+// to compute the changes without applying them
+func IsDryRun(obj metav1.Object) bool {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		return false
+	}
+
+	return annotations[DryRunAnnotation] == "true"
+}
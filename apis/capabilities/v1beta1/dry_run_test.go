@@ -0,0 +1,30 @@
+package v1beta1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsDryRun(t *testing.T) {
+	cases := []struct {
+		testName    string
+		annotations map[string]string
+		expected    bool
+	}{
+		{"nil annotations", nil, false},
+		{"no dry-run annotation", map[string]string{"a": "b"}, false},
+		{"dry-run disabled", map[string]string{DryRunAnnotation: "false"}, false},
+		{"unknown value", map[string]string{DryRunAnnotation: "yes"}, false},
+		{"dry-run enabled", map[string]string{DryRunAnnotation: "true"}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			backend := &Backend{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if IsDryRun(backend) != tc.expected {
+				subT.Errorf("expected dry-run: %t", tc.expected)
+			}
+		})
+	}
+}
@@ -46,18 +46,116 @@ const (
 	// OpenAPIFailedConditionType indicates that an error occurred during reconcilliation.
 	// The operator will retry.
 	OpenAPIFailedConditionType common.ConditionType = "Failed"
+
+	// OpenAPIBackendSplitModeServers generates one backend for each OpenAPI document server
+	OpenAPIBackendSplitModeServers = "servers"
+
+	// OpenAPIBackendSplitModeTags generates one backend for each OpenAPI document tag
+	// with a private base URL in the x-3scale-backends extension
+	OpenAPIBackendSplitModeTags = "tags"
 )
 
+// OpenAPIURLOptionsSpec defines the options to fetch the OpenAPI Document from the remote URL
+type OpenAPIURLOptionsSpec struct {
+	// CredentialsRef refers to the secret object that contains the credentials to fetch the OpenAPI Document.
+	// The token field is sent as bearer token. Otherwise, the username and password fields are sent as basic authentication.
+	// +optional
+	CredentialsRef *corev1.LocalObjectReference `json:"credentialsRef,omitempty"`
+
+	// Headers are extra HTTP headers sent in the request
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// CABundleRef refers to the configmap key that contains the PEM encoded CA bundle
+	// used to verify the remote server certificate
+	// +optional
+	CABundleRef *corev1.ConfigMapKeySelector `json:"caBundleRef,omitempty"`
+}
+
+// OpenAPIExternalRefSourceSpec defines a configmap or secret object whose keys are available as files
+// to resolve the external references of the OpenAPI Document
+type OpenAPIExternalRefSourceSpec struct {
+	// ConfigMapRef refers to the configmap object that contains the referenced files
+	// +optional
+	ConfigMapRef *corev1.LocalObjectReference `json:"configMapRef,omitempty"`
+
+	// SecretRef refers to the secret object that contains the referenced files
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+
+	// MountPath directory, relative to the OpenAPI Document directory, where the keys are available as files.
+	// Defaults to the OpenAPI Document directory
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+}
+
+// OpenAPIActiveDocSpec defines the desired state of the ActiveDoc generated from the OpenAPI Document
+type OpenAPIActiveDocSpec struct {
+	// Published switch to publish the activedoc
+	// +optional
+	Published *bool `json:"published,omitempty"`
+
+	// SkipSwaggerValidations switch to skip OpenAPI validation
+	// +optional
+	SkipSwaggerValidations *bool `json:"skipSwaggerValidations,omitempty"`
+}
+
 // OpenAPIRefSpec Reference to the OpenAPI Specification
 type OpenAPIRefSpec struct {
 	// SecretRef refers to the secret object that contains the OpenAPI Document
 	// +optional
 	SecretRef *corev1.ObjectReference `json:"secretRef,omitempty"`
 
+	// ConfigMapRef refers to the configmap object that contains the OpenAPI Document
+	// +optional
+	ConfigMapRef *corev1.ObjectReference `json:"configMapRef,omitempty"`
+
+	// DocumentKey key of the secret or configmap object that contains the OpenAPI Document.
+	// Required when the object has more than one key. The other keys are available as files
+	// to resolve the external references of the OpenAPI Document
+	// +optional
+	DocumentKey *string `json:"documentKey,omitempty"`
+
 	// URL Remote URL from where to fetch the OpenAPI Document
 	// +kubebuilder:validation:Pattern=`^https?:\/\/.*$`
 	// +optional
 	URL *string `json:"url,omitempty"`
+
+	// URLOptions options to fetch the OpenAPI Document from the remote URL
+	// +optional
+	URLOptions *OpenAPIURLOptionsSpec `json:"urlOptions,omitempty"`
+
+	// ExternalRefSources configmap or secret objects whose keys are available as files
+	// to resolve the external references of the OpenAPI Document
+	// +optional
+	ExternalRefSources []OpenAPIExternalRefSourceSpec `json:"externalRefSources,omitempty"`
+}
+
+// OpenAPIOIDCSpec defines the desired configuration of OpenID Connect Authentication
+// for OpenAPI documents with oauth2 or openIdConnect security schemes
+type OpenAPIOIDCSpec struct {
+	// IssuerType is the type of the OIDC issuer
+	// +kubebuilder:validation:Enum=keycloak;rest
+	IssuerType string `json:"issuerType"`
+
+	// IssuerEndpointRef refers to the secret that contains the OIDC issuer endpoint.
+	// The secret must be in the same namespace as the OpenAPI custom resource.
+	// The issuer endpoint, including the client credentials, is read from the issuerEndpoint secret field.
+	IssuerEndpointRef corev1.LocalObjectReference `json:"issuerEndpointRef"`
+
+	// AuthenticationFlow specifies OAuth2.0 authorization grant type.
+	// Overrides the authorization grant types derived from the oauth2 security scheme flows
+	// +optional
+	AuthenticationFlow *OIDCAuthenticationFlowSpec `json:"authenticationFlow,omitempty"`
+
+	// JwtClaimWithClientID is the JSON Web Token (JWT) Claim with ClientID that contains the clientID. Defaults to 'azp'.
+	// +optional
+	JwtClaimWithClientID *string `json:"jwtClaimWithClientID,omitempty"`
+
+	// JwtClaimWithClientIDType sets to process the ClientID Token Claim value as a string or as a liquid template.
+	// +kubebuilder:validation:Enum=plain;liquid
+	// +optional
+	JwtClaimWithClientIDType *string `json:"jwtClaimWithClientIDType,omitempty"`
 }
 
 // OpenAPISpec defines the desired state of OpenAPI
@@ -68,10 +166,27 @@ type OpenAPISpec struct {
 	// OpenAPIRef Reference to the OpenAPI Specification
 	OpenAPIRef OpenAPIRefSpec `json:"openapiRef"`
 
+	// RefreshInterval sets the period to fetch the OpenAPI document again from the URL source.
+	// The product and the backend are only updated when the OpenAPI document content changes.
+	// For example: 1h
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+
 	// ProviderAccountRef references account provider credentials
 	// +optional
 	ProviderAccountRef *corev1.LocalObjectReference `json:"providerAccountRef,omitempty"`
 
+	// ProviderAccountNamespace is the namespace of the ProviderAccountRef secret.
+	// Defaults to the namespace of the custom resource. Secrets in other namespaces must allow
+	// the namespace of the custom resource in the capabilities.3scale.net/allowed-namespaces annotation
+	// +optional
+	ProviderAccountNamespace *string `json:"providerAccountNamespace,omitempty"`
+
+	// TenantRef references the Tenant custom resource holding account provider credentials in its tenant secret.
+	// The Tenant must be in the same namespace. ProviderAccountRef and TenantRef are mutually exclusive
+	// +optional
+	TenantRef *corev1.LocalObjectReference `json:"tenantRef,omitempty"`
+
 	// ProductionPublicBaseURL Custom public production URL
 	// +kubebuilder:validation:Pattern=`^https?:\/\/.*$`
 	// +optional
@@ -90,6 +205,12 @@ type OpenAPISpec struct {
 	// +optional
 	PrivateBaseURL *string `json:"privateBaseURL,omitempty"`
 
+	// BackendSplitMode generates multiple backends from the OpenAPI document.
+	// Valid values: servers, tags. Defaults to a single backend from the first server.
+	// +kubebuilder:validation:Enum=servers;tags
+	// +optional
+	BackendSplitMode *string `json:"backendSplitMode,omitempty"`
+
 	// PrefixMatching Use prefix matching instead of strict matching on mapping rules derived from openapi operations
 	// +optional
 	PrefixMatching *bool `json:"prefixMatching,omitempty"`
@@ -101,6 +222,21 @@ type OpenAPISpec struct {
 	// PrivateAPISecretToken Custom secret token sent by the API gateway to the private API
 	// +optional
 	PrivateAPISecretToken *string `json:"privateAPISecretToken,omitempty"`
+
+	// SecuritySchemeName selects the OpenAPI security scheme used for the product authentication
+	// when the OpenAPI document declares multiple global security requirements.
+	// Defaults to the first global security requirement with a supported security scheme type.
+	// +optional
+	SecuritySchemeName *string `json:"securitySchemeName,omitempty"`
+
+	// OIDC OpenID Connect authentication configuration.
+	// Required when the OpenAPI document security scheme type is oauth2 or openIdConnect
+	// +optional
+	OIDC *OpenAPIOIDCSpec `json:"oidc,omitempty"`
+
+	// ActiveDoc enables the ActiveDoc generated from the OpenAPI Document and linked to the product
+	// +optional
+	ActiveDoc *OpenAPIActiveDocSpec `json:"activeDoc,omitempty"`
 }
 
 // OpenAPIStatus defines the observed state of OpenAPI
@@ -112,6 +248,10 @@ type OpenAPIStatus struct {
 	// +optional
 	ProviderAccountHost string `json:"providerAccountHost,omitempty"`
 
+	// OpenAPIDigest is the digest of the latest OpenAPI document content imported
+	// +optional
+	OpenAPIDigest string `json:"openapiDigest,omitempty"`
+
 	// ProductResourceName references the managed 3scale product
 	// +optional
 	ProductResourceName *corev1.LocalObjectReference `json:"productResourceName,omitempty"`
@@ -120,10 +260,18 @@ type OpenAPIStatus struct {
 	// +optional
 	BackendResourceNames []corev1.LocalObjectReference `json:"backendResourceNames,omitempty"`
 
+	// ActiveDocResourceName references the managed activedoc
+	// +optional
+	ActiveDocResourceName *corev1.LocalObjectReference `json:"activeDocResourceName,omitempty"`
+
 	// ObservedGeneration reflects the generation of the most recently observed Backend Spec.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// Warnings contains the OpenAPI document features that could not be imported into 3scale
+	// +optional
+	Warnings []string `json:"warnings,omitempty"`
+
 	// Current state of the openapi resource.
 	// Conditions represent the latest available observations of an object's state
 	// +optional
@@ -139,6 +287,12 @@ func (o *OpenAPIStatus) Equals(other *OpenAPIStatus, logger logr.Logger) bool {
 		return false
 	}
 
+	if o.OpenAPIDigest != other.OpenAPIDigest {
+		diff := cmp.Diff(o.OpenAPIDigest, other.OpenAPIDigest)
+		logger.V(1).Info("OpenAPIDigest not equal", "difference", diff)
+		return false
+	}
+
 	if o.ProductResourceName != other.ProductResourceName {
 		diff := cmp.Diff(o.ProductResourceName, other.ProductResourceName)
 		logger.V(1).Info("ProductResourceName not equal", "difference", diff)
@@ -151,12 +305,24 @@ func (o *OpenAPIStatus) Equals(other *OpenAPIStatus, logger logr.Logger) bool {
 		return false
 	}
 
+	if !reflect.DeepEqual(o.ActiveDocResourceName, other.ActiveDocResourceName) {
+		diff := cmp.Diff(o.ActiveDocResourceName, other.ActiveDocResourceName)
+		logger.V(1).Info("ActiveDocResourceName not equal", "difference", diff)
+		return false
+	}
+
 	if o.ObservedGeneration != other.ObservedGeneration {
 		diff := cmp.Diff(o.ObservedGeneration, other.ObservedGeneration)
 		logger.V(1).Info("ObservedGeneration not equal", "difference", diff)
 		return false
 	}
 
+	if !reflect.DeepEqual(o.Warnings, other.Warnings) {
+		diff := cmp.Diff(o.Warnings, other.Warnings)
+		logger.V(1).Info("Warnings not equal", "difference", diff)
+		return false
+	}
+
 	// Marshalling sorts by condition type
 	currentMarshaledJSON, _ := o.Conditions.MarshalJSON()
 	otherMarshaledJSON, _ := other.Conditions.MarshalJSON()
@@ -192,14 +358,63 @@ func (o *OpenAPI) SetDefaults(logger logr.Logger) bool {
 		updated = true
 	}
 
+	if o.Spec.OpenAPIRef.ConfigMapRef != nil && o.Spec.OpenAPIRef.ConfigMapRef.Namespace == "" {
+		o.Spec.OpenAPIRef.ConfigMapRef.Namespace = o.GetNamespace()
+		updated = true
+	}
+
 	return updated
 }
 
 func (o *OpenAPI) Validate() field.ErrorList {
 	errors := field.ErrorList{}
+
+	errors = append(errors, validateProviderAccountReference(o.Spec.ProviderAccountRef, o.Spec.TenantRef)...)
+
+	specFldPath := field.NewPath("spec")
+	refreshIntervalFldPath := specFldPath.Child("refreshInterval")
+	openapiRefFldPath := specFldPath.Child("openapiRef")
+	urlOptionsFldPath := openapiRefFldPath.Child("urlOptions")
+	documentKeyFldPath := openapiRefFldPath.Child("documentKey")
+	externalRefSourcesFldPath := openapiRefFldPath.Child("externalRefSources")
+	privateBaseURLFldPath := specFldPath.Child("privateBaseURL")
+
+	if o.Spec.OpenAPIRef.URLOptions != nil && o.Spec.OpenAPIRef.URL == nil {
+		errors = append(errors, field.Invalid(urlOptionsFldPath, o.Spec.OpenAPIRef.URLOptions, "only supported for the url OpenAPI document source"))
+	}
+
+	if o.Spec.OpenAPIRef.DocumentKey != nil && o.Spec.OpenAPIRef.SecretRef == nil && o.Spec.OpenAPIRef.ConfigMapRef == nil {
+		errors = append(errors, field.Invalid(documentKeyFldPath, o.Spec.OpenAPIRef.DocumentKey, "only supported for the secret and configmap OpenAPI document sources"))
+	}
+
+	for idx, source := range o.Spec.OpenAPIRef.ExternalRefSources {
+		if (source.ConfigMapRef == nil) == (source.SecretRef == nil) {
+			errors = append(errors, field.Invalid(externalRefSourcesFldPath.Index(idx), source, "one of configMapRef or secretRef is required"))
+		}
+	}
+
+	if o.Spec.PrivateBaseURL != nil && o.Spec.BackendSplitMode != nil && *o.Spec.BackendSplitMode == OpenAPIBackendSplitModeServers {
+		errors = append(errors, field.Invalid(privateBaseURLFldPath, o.Spec.PrivateBaseURL, "not supported when backends are generated from the servers"))
+	}
+
+	if o.Spec.RefreshInterval != nil {
+		if o.Spec.OpenAPIRef.URL == nil {
+			errors = append(errors, field.Invalid(refreshIntervalFldPath, o.Spec.RefreshInterval.Duration.String(), "only supported for the url OpenAPI document source"))
+		}
+
+		if o.Spec.RefreshInterval.Duration <= 0 {
+			errors = append(errors, field.Invalid(refreshIntervalFldPath, o.Spec.RefreshInterval.Duration.String(), "must be positive"))
+		}
+	}
+
 	return errors
 }
 
+// RefreshRequired returns true when the OpenAPI document must be fetched again periodically
+func (o *OpenAPI) RefreshRequired() bool {
+	return o.Spec.OpenAPIRef.URL != nil && o.Spec.RefreshInterval != nil
+}
+
 // +kubebuilder:object:root=true
 
 // OpenAPIList contains a list of OpenAPI
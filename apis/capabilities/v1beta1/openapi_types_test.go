@@ -0,0 +1,118 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateOpenAPIRefreshInterval(t *testing.T) {
+	openapiURL := "https://example.com/openapi.yaml"
+
+	cases := []struct {
+		testName        string
+		openapiRef      OpenAPIRefSpec
+		refreshInterval *metav1.Duration
+		expectedErrors  int
+		refreshRequired bool
+	}{
+		{"url without refresh", OpenAPIRefSpec{URL: &openapiURL}, nil, 0, false},
+		{"url with refresh", OpenAPIRefSpec{URL: &openapiURL}, &metav1.Duration{Duration: time.Hour}, 0, true},
+		{"url with negative refresh", OpenAPIRefSpec{URL: &openapiURL}, &metav1.Duration{Duration: -time.Hour}, 1, true},
+		{"secret with refresh", OpenAPIRefSpec{SecretRef: &corev1.ObjectReference{Name: "openapi"}}, &metav1.Duration{Duration: time.Hour}, 1, false},
+		{"url with options", OpenAPIRefSpec{URL: &openapiURL, URLOptions: &OpenAPIURLOptionsSpec{}}, nil, 0, false},
+		{"secret with url options", OpenAPIRefSpec{SecretRef: &corev1.ObjectReference{Name: "openapi"}, URLOptions: &OpenAPIURLOptionsSpec{}}, nil, 1, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			openapi := OpenAPI{
+				Spec: OpenAPISpec{
+					OpenAPIRef:      tc.openapiRef,
+					RefreshInterval: tc.refreshInterval,
+				},
+			}
+
+			errors := openapi.Validate()
+			if len(errors) != tc.expectedErrors {
+				subT.Errorf("expected %d errors, got %d: %v", tc.expectedErrors, len(errors), errors)
+			}
+
+			if openapi.RefreshRequired() != tc.refreshRequired {
+				subT.Errorf("expected refresh required %t", tc.refreshRequired)
+			}
+		})
+	}
+}
+
+func TestValidateOpenAPIRefSources(t *testing.T) {
+	openapiURL := "https://example.com/openapi.yaml"
+	documentKey := "openapi.yaml"
+	configMapRef := &corev1.ObjectReference{Name: "openapi"}
+
+	cases := []struct {
+		testName       string
+		openapiRef     OpenAPIRefSpec
+		expectedErrors int
+	}{
+		{"configmap with document key", OpenAPIRefSpec{ConfigMapRef: configMapRef, DocumentKey: &documentKey}, 0},
+		{"url with document key", OpenAPIRefSpec{URL: &openapiURL, DocumentKey: &documentKey}, 1},
+		{"external ref sources", OpenAPIRefSpec{ConfigMapRef: configMapRef, ExternalRefSources: []OpenAPIExternalRefSourceSpec{
+			{ConfigMapRef: &corev1.LocalObjectReference{Name: "schemas"}, MountPath: "schemas"},
+			{SecretRef: &corev1.LocalObjectReference{Name: "paths"}},
+		}}, 0},
+		{"external ref source without object", OpenAPIRefSpec{URL: &openapiURL, ExternalRefSources: []OpenAPIExternalRefSourceSpec{
+			{MountPath: "schemas"},
+		}}, 1},
+		{"external ref source with both objects", OpenAPIRefSpec{URL: &openapiURL, ExternalRefSources: []OpenAPIExternalRefSourceSpec{
+			{ConfigMapRef: &corev1.LocalObjectReference{Name: "schemas"}, SecretRef: &corev1.LocalObjectReference{Name: "paths"}},
+		}}, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			openapi := OpenAPI{Spec: OpenAPISpec{OpenAPIRef: tc.openapiRef}}
+
+			errors := openapi.Validate()
+			if len(errors) != tc.expectedErrors {
+				subT.Errorf("expected %d errors, got %d: %v", tc.expectedErrors, len(errors), errors)
+			}
+		})
+	}
+}
+
+func TestValidateOpenAPIBackendSplitMode(t *testing.T) {
+	openapiURL := "https://example.com/openapi.yaml"
+	privateBaseURL := "https://backend.example.com"
+	serversMode := OpenAPIBackendSplitModeServers
+	tagsMode := OpenAPIBackendSplitModeTags
+
+	cases := []struct {
+		testName         string
+		backendSplitMode *string
+		privateBaseURL   *string
+		expectedErrors   int
+	}{
+		{"single backend with private base URL", nil, &privateBaseURL, 0},
+		{"servers", &serversMode, nil, 0},
+		{"servers with private base URL", &serversMode, &privateBaseURL, 1},
+		{"tags with private base URL", &tagsMode, &privateBaseURL, 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			openapi := OpenAPI{Spec: OpenAPISpec{
+				OpenAPIRef:       OpenAPIRefSpec{URL: &openapiURL},
+				BackendSplitMode: tc.backendSplitMode,
+				PrivateBaseURL:   tc.privateBaseURL,
+			}}
+
+			errors := openapi.Validate()
+			if len(errors) != tc.expectedErrors {
+				subT.Errorf("expected %d errors, got %d: %v", tc.expectedErrors, len(errors), errors)
+			}
+		})
+	}
+}
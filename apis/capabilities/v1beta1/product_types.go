@@ -58,6 +58,18 @@ const (
 	// ProductFailedConditionType indicates that an error occurred during synchronization.
 	// The operator will retry.
 	ProductFailedConditionType common.ConditionType = "Failed"
+
+	// ProductDeletingConditionType indicates the product custom resource has been deleted
+	// and the 3scale product is being removed.
+	ProductDeletingConditionType common.ConditionType = "Deleting"
+
+	// ProductDriftedConditionType indicates that 3scale product objects modified outside the operator
+	// have been corrected in the last synchronization of an unchanged spec.
+	ProductDriftedConditionType common.ConditionType = "Drifted"
+
+	// ProductDryRunConditionType indicates that the product is in dry-run mode.
+	// The changes to 3scale objects are computed but not applied.
+	ProductDryRunConditionType common.ConditionType = "DryRun"
 )
 
 var (
@@ -65,7 +77,7 @@ var (
 	// Needs to exist in the policy chain
 	apicastPolicy = PolicyConfig{
 		Name:    "apicast",
-		Version: "builtin",
+		Version: BuiltinPolicyVersion,
 		Configuration: runtime.RawExtension{
 			Raw: []byte(`{}`),
 		},
@@ -73,6 +85,32 @@ var (
 	}
 )
 
+const (
+	// BuiltinPolicyVersion is the version of the APIcast built-in policies
+	BuiltinPolicyVersion = "builtin"
+)
+
+var (
+	// builtinPolicies are the names of the APIcast built-in policies
+	builtinPolicies = map[string]bool{
+		"apicast": true, "3scale_batcher": true, "3scale_referrer": true, "caching": true,
+		"camel": true, "conditional": true, "content_caching": true, "cors": true,
+		"custom_metrics": true, "default_credentials": true, "echo": true, "edge_limiting": true,
+		"grpc": true, "headers": true, "http_proxy": true, "ip_check": true,
+		"jwt_claim_check": true, "keycloak_role_check": true, "liquid_context_debug": true, "logging": true,
+		"maintenance_mode": true, "nginx_filters": true, "oauth_mtls": true, "on_failed": true,
+		"payload_limits": true, "rate_limit_headers": true, "request_unbuffered": true, "retry": true,
+		"rewrite_url_captures": true, "routing": true, "soap": true, "statuscode_overwrite": true,
+		"tls": true, "tls_validation": true, "token_introspection": true, "upstream": true,
+		"upstream_connection": true, "upstream_mtls": true, "url_rewriting": true, "websocket": true,
+	}
+)
+
+// IsBuiltinPolicy returns true when the policy is one of the APIcast built-in policies
+func IsBuiltinPolicy(name, version string) bool {
+	return version == BuiltinPolicyVersion && builtinPolicies[name]
+}
+
 var (
 	//
 	productSystemNameRegexp = regexp.MustCompile("[^a-zA-Z0-9]+")
@@ -918,9 +956,25 @@ type ProductSpec struct {
 	// +optional
 	ProviderAccountRef *corev1.LocalObjectReference `json:"providerAccountRef,omitempty"`
 
+	// ProviderAccountNamespace is the namespace of the ProviderAccountRef secret.
+	// Defaults to the namespace of the custom resource. Secrets in other namespaces must allow
+	// the namespace of the custom resource in the capabilities.3scale.net/allowed-namespaces annotation
+	// +optional
+	ProviderAccountNamespace *string `json:"providerAccountNamespace,omitempty"`
+
+	// TenantRef references the Tenant custom resource holding account provider credentials in its tenant secret.
+	// The Tenant must be in the same namespace. ProviderAccountRef and TenantRef are mutually exclusive
+	// +optional
+	TenantRef *corev1.LocalObjectReference `json:"tenantRef,omitempty"`
+
 	// Policies holds the product's policy chain
 	// +optional
 	Policies []PolicyConfig `json:"policies,omitempty"`
+
+	// DeployToStaging deploys the proxy configuration to the staging environment
+	// after every successful synchronization. Defaults to "false"
+	// +optional
+	DeployToStaging bool `json:"deployToStaging,omitempty"`
 }
 
 func (s *ProductSpec) DeploymentOption() *string {
@@ -1069,6 +1123,11 @@ type ProductStatus struct {
 	// +optional
 	State *string `json:"state,omitempty"`
 
+	// StagingConfigVersion is the proxy configuration version deployed to the staging environment.
+	// Only available when DeployToStaging is enabled
+	// +optional
+	StagingConfigVersion *int64 `json:"stagingConfigVersion,omitempty"`
+
 	// 3scale control plane host
 	// +optional
 	ProviderAccountHost string `json:"providerAccountHost,omitempty"`
@@ -1098,6 +1157,12 @@ func (p *ProductStatus) Equals(other *ProductStatus, logger logr.Logger) bool {
 		return false
 	}
 
+	if !reflect.DeepEqual(p.StagingConfigVersion, other.StagingConfigVersion) {
+		diff := cmp.Diff(p.StagingConfigVersion, other.StagingConfigVersion)
+		logger.V(1).Info("StagingConfigVersion not equal", "difference", diff)
+		return false
+	}
+
 	if p.ProviderAccountHost != other.ProviderAccountHost {
 		diff := cmp.Diff(p.ProviderAccountHost, other.ProviderAccountHost)
 		logger.V(1).Info("ProviderAccountHost not equal", "difference", diff)
@@ -1195,6 +1260,9 @@ func (product *Product) SetDefaults(logger logr.Logger) bool {
 
 func (product *Product) Validate() field.ErrorList {
 	errors := field.ErrorList{}
+
+	errors = append(errors, validateProviderAccountReference(product.Spec.ProviderAccountRef, product.Spec.TenantRef)...)
+
 	specFldPath := field.NewPath("spec")
 	metricsFldPath := specFldPath.Child("metrics")
 	mappingRulesFldPath := specFldPath.Child("mappingRules")
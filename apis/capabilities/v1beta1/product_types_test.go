@@ -260,3 +260,24 @@ func TestValidateProductHappyPath(t *testing.T) {
 		t.Errorf("product validation fails: %s", errors.ToAggregate().Error())
 	}
 }
+
+func TestIsBuiltinPolicy(t *testing.T) {
+	cases := []struct {
+		name     string
+		version  string
+		expected bool
+	}{
+		{"apicast", BuiltinPolicyVersion, true},
+		{"cors", BuiltinPolicyVersion, true},
+		{"cors", "0.1", false},
+		{"my_policy", BuiltinPolicyVersion, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name+"-"+tc.version, func(subT *testing.T) {
+			if got := IsBuiltinPolicy(tc.name, tc.version); got != tc.expected {
+				subT.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// validateProviderAccountReference checks the provider account credentials
+// are referenced either by secret or by tenant, but not both
+func validateProviderAccountReference(providerAccountRef, tenantRef *corev1.LocalObjectReference) field.ErrorList {
+	errors := field.ErrorList{}
+
+	if providerAccountRef != nil && tenantRef != nil {
+		tenantRefFldPath := field.NewPath("spec").Child("tenantRef")
+		errors = append(errors, field.Forbidden(tenantRefFldPath, "providerAccountRef and tenantRef are mutually exclusive"))
+	}
+
+	return errors
+}
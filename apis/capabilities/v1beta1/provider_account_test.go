@@ -0,0 +1,34 @@
+package v1beta1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDeveloperAccountValidateProviderAccountReference(t *testing.T) {
+	providerAccountRef := &corev1.LocalObjectReference{Name: "mysecret"}
+	tenantRef := &corev1.LocalObjectReference{Name: "mytenant"}
+
+	cases := []struct {
+		testName           string
+		providerAccountRef *corev1.LocalObjectReference
+		tenantRef          *corev1.LocalObjectReference
+		expectedErrors     int
+	}{
+		{"no references", nil, nil, 0},
+		{"provider account reference", providerAccountRef, nil, 0},
+		{"tenant reference", nil, tenantRef, 0},
+		{"both references", providerAccountRef, tenantRef, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			account := &DeveloperAccount{Spec: DeveloperAccountSpec{ProviderAccountRef: tc.providerAccountRef, TenantRef: tc.tenantRef}}
+			errors := account.Validate()
+			if len(errors) != tc.expectedErrors {
+				subT.Errorf("expected %d errors, got %v", tc.expectedErrors, errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	"github.com/3scale/3scale-operator/pkg/common"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+const (
+	ProxyConfigPromoteKind = "ProxyConfigPromote"
+
+	// ProxyConfigPromoteInvalidConditionType represents that the combination of configuration
+	// in the spec is not supported. This is not a transient error, but
+	// indicates a state that must be fixed before progress can be made.
+	ProxyConfigPromoteInvalidConditionType common.ConditionType = "Invalid"
+
+	// ProxyConfigPromoteOrphanConditionType represents that the configuration in the spec
+	// contains reference to non existing resource.
+	// This is (should be) a transient error, but
+	// indicates a state that must be fixed before progress can be made.
+	// Example: the ProxyConfigPromoteSpec references non existing product resource
+	ProxyConfigPromoteOrphanConditionType common.ConditionType = "Orphan"
+
+	// ProxyConfigPromoteReadyConditionType indicates the proxy configuration has been successfully promoted.
+	// Steady state
+	ProxyConfigPromoteReadyConditionType common.ConditionType = "Ready"
+
+	// ProxyConfigPromoteFailedConditionType indicates that an error occurred during promotion.
+	// The operator will retry.
+	ProxyConfigPromoteFailedConditionType common.ConditionType = "Failed"
+)
+
+// ProxyConfigPromoteSpec defines the desired state of ProxyConfigPromote
+type ProxyConfigPromoteSpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// ProductRef is the reference to the product whose proxy configuration is promoted
+	ProductRef corev1.LocalObjectReference `json:"productRef"`
+
+	// Production defines whether the staging configuration is promoted to production.
+	// Defaults to "false", ie, only deployed to staging
+	// +optional
+	Production bool `json:"production,omitempty"`
+}
+
+// ProxyConfigPromoteStatus defines the observed state of ProxyConfigPromote
+type ProxyConfigPromoteStatus struct {
+	// ProductID is the 3scale internal ID of the referenced product
+	// +optional
+	ProductID *int64 `json:"productID,omitempty"`
+
+	// LatestStagingVersion is the latest proxy configuration version deployed to staging
+	// +optional
+	LatestStagingVersion *int64 `json:"latestStagingVersion,omitempty"`
+
+	// LatestProductionVersion is the latest proxy configuration version promoted to production
+	// +optional
+	LatestProductionVersion *int64 `json:"latestProductionVersion,omitempty"`
+
+	// 3scale control plane host
+	// +optional
+	ProviderAccountHost string `json:"providerAccountHost,omitempty"`
+
+	// ObservedGeneration reflects the generation of the most recently observed ProxyConfigPromote Spec.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Current state of the proxy configuration promotion.
+	// Conditions represent the latest available observations of an object's state
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions common.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,2,rep,name=conditions"`
+}
+
+func (p *ProxyConfigPromoteStatus) Equals(other *ProxyConfigPromoteStatus, logger logr.Logger) bool {
+	if !reflect.DeepEqual(p.ProductID, other.ProductID) {
+		diff := cmp.Diff(p.ProductID, other.ProductID)
+		logger.V(1).Info("ProductID not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(p.LatestStagingVersion, other.LatestStagingVersion) {
+		diff := cmp.Diff(p.LatestStagingVersion, other.LatestStagingVersion)
+		logger.V(1).Info("LatestStagingVersion not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(p.LatestProductionVersion, other.LatestProductionVersion) {
+		diff := cmp.Diff(p.LatestProductionVersion, other.LatestProductionVersion)
+		logger.V(1).Info("LatestProductionVersion not equal", "difference", diff)
+		return false
+	}
+
+	if p.ProviderAccountHost != other.ProviderAccountHost {
+		diff := cmp.Diff(p.ProviderAccountHost, other.ProviderAccountHost)
+		logger.V(1).Info("ProviderAccountHost not equal", "difference", diff)
+		return false
+	}
+
+	if p.ObservedGeneration != other.ObservedGeneration {
+		diff := cmp.Diff(p.ObservedGeneration, other.ObservedGeneration)
+		logger.V(1).Info("ObservedGeneration not equal", "difference", diff)
+		return false
+	}
+
+	// Marshalling sorts by condition type
+	currentMarshaledJSON, _ := p.Conditions.MarshalJSON()
+	otherMarshaledJSON, _ := other.Conditions.MarshalJSON()
+	if string(currentMarshaledJSON) != string(otherMarshaledJSON) {
+		diff := cmp.Diff(string(currentMarshaledJSON), string(otherMarshaledJSON))
+		logger.V(1).Info("Conditions not equal", "difference", diff)
+		return false
+	}
+
+	return true
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// ProxyConfigPromote is the Schema for the proxyconfigpromotes API
+type ProxyConfigPromote struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProxyConfigPromoteSpec   `json:"spec,omitempty"`
+	Status ProxyConfigPromoteStatus `json:"status,omitempty"`
+}
+
+func (p *ProxyConfigPromote) Validate() field.ErrorList {
+	errors := field.ErrorList{}
+
+	if p.Spec.ProductRef.Name == "" {
+		productRefFldPath := field.NewPath("spec").Child("productRef")
+		errors = append(errors, field.Required(productRefFldPath.Child("name"), "product reference name required"))
+	}
+
+	return errors
+}
+
+// +kubebuilder:object:root=true
+
+// ProxyConfigPromoteList contains a list of ProxyConfigPromote
+type ProxyConfigPromoteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProxyConfigPromote `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProxyConfigPromote{}, &ProxyConfigPromoteList{})
+}
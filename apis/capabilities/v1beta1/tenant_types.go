@@ -0,0 +1,287 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/3scale/3scale-operator/pkg/common"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// TenantInvalidConditionType represents that the combination of configuration
+	// in the spec is not supported. This is not a transient error, but
+	// indicates a state that must be fixed before progress can be made.
+	TenantInvalidConditionType common.ConditionType = "Invalid"
+
+	// TenantReadyConditionType indicates the tenant has been successfully synchronized.
+	// Steady state
+	TenantReadyConditionType common.ConditionType = "Ready"
+
+	// TenantFailedConditionType indicates that an error occurred during synchronization.
+	// The operator will retry.
+	TenantFailedConditionType common.ConditionType = "Failed"
+
+	// TenantRotateAccessTokenAnnotation requests a tenant access token rotation.
+	// The rotation takes place every time the annotation value changes. For instance, using timestamps as values.
+	TenantRotateAccessTokenAnnotation = "tenant.capabilities.3scale.net/rotate-access-token"
+)
+
+// TenantSpec defines the desired state of Tenant
+type TenantSpec struct {
+	Username               string             `json:"username"`
+	Email                  string             `json:"email"`
+	OrganizationName       string             `json:"organizationName"`
+	SystemMasterUrl        string             `json:"systemMasterUrl"`
+	TenantSecretRef        v1.SecretReference `json:"tenantSecretRef"`
+	PasswordCredentialsRef v1.SecretReference `json:"passwordCredentialsRef"`
+	MasterCredentialsRef   v1.SecretReference `json:"masterCredentialsRef"`
+
+	// Suspended desired state of the tenant account.
+	// Suspended tenants cannot use the admin portal nor the APIs.
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+
+	// AccessTokenRotationInterval enables periodic rotation of the tenant access token.
+	// A new admin access token replaces the one in the tenant secret when the interval has elapsed since the last rotation.
+	// For example: 2160h (90 days)
+	// +optional
+	AccessTokenRotationInterval *metav1.Duration `json:"accessTokenRotationInterval,omitempty"`
+}
+
+// TenantStatus defines the observed state of Tenant
+type TenantStatus struct {
+	TenantId int64 `json:"tenantId"`
+	AdminId  int64 `json:"adminId"`
+
+	// 3scale control plane host
+	// +optional
+	ProviderAccountHost string `json:"providerAccountHost,omitempty"`
+
+	// ObservedGeneration reflects the generation of the most recently observed Tenant Spec.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// AccessTokenID is the ID of the admin access token created by the operator and stored in the tenant secret
+	// +optional
+	AccessTokenID *int64 `json:"accessTokenID,omitempty"`
+
+	// AccessTokenRotationTime is the time of the latest tenant access token rotation
+	// +optional
+	AccessTokenRotationTime *metav1.Time `json:"accessTokenRotationTime,omitempty"`
+
+	// AccessTokenRotationRequest is the value of the rotate access token annotation handled on the latest rotation
+	// +optional
+	AccessTokenRotationRequest string `json:"accessTokenRotationRequest,omitempty"`
+
+	// PreviousAccessTokenIDs are the IDs of the access tokens replaced by rotations and not revoked yet.
+	// Revocations are retried on every reconciliation
+	// +optional
+	PreviousAccessTokenIDs []int64 `json:"previousAccessTokenIDs,omitempty"`
+
+	// Current state of the tenant resource.
+	// Conditions represent the latest available observations of an object's state
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions common.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,2,rep,name=conditions"`
+}
+
+func (t *TenantStatus) Equals(other *TenantStatus, logger logr.Logger) bool {
+	if t.TenantId != other.TenantId {
+		diff := cmp.Diff(t.TenantId, other.TenantId)
+		logger.V(1).Info("TenantId not equal", "difference", diff)
+		return false
+	}
+
+	if t.AdminId != other.AdminId {
+		diff := cmp.Diff(t.AdminId, other.AdminId)
+		logger.V(1).Info("AdminId not equal", "difference", diff)
+		return false
+	}
+
+	if t.ProviderAccountHost != other.ProviderAccountHost {
+		diff := cmp.Diff(t.ProviderAccountHost, other.ProviderAccountHost)
+		logger.V(1).Info("ProviderAccountHost not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(t.AccessTokenID, other.AccessTokenID) {
+		diff := cmp.Diff(t.AccessTokenID, other.AccessTokenID)
+		logger.V(1).Info("AccessTokenID not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(t.AccessTokenRotationTime, other.AccessTokenRotationTime) {
+		diff := cmp.Diff(t.AccessTokenRotationTime, other.AccessTokenRotationTime)
+		logger.V(1).Info("AccessTokenRotationTime not equal", "difference", diff)
+		return false
+	}
+
+	if t.AccessTokenRotationRequest != other.AccessTokenRotationRequest {
+		diff := cmp.Diff(t.AccessTokenRotationRequest, other.AccessTokenRotationRequest)
+		logger.V(1).Info("AccessTokenRotationRequest not equal", "difference", diff)
+		return false
+	}
+
+	if !reflect.DeepEqual(t.PreviousAccessTokenIDs, other.PreviousAccessTokenIDs) {
+		diff := cmp.Diff(t.PreviousAccessTokenIDs, other.PreviousAccessTokenIDs)
+		logger.V(1).Info("PreviousAccessTokenIDs not equal", "difference", diff)
+		return false
+	}
+
+	if t.ObservedGeneration != other.ObservedGeneration {
+		diff := cmp.Diff(t.ObservedGeneration, other.ObservedGeneration)
+		logger.V(1).Info("ObservedGeneration not equal", "difference", diff)
+		return false
+	}
+
+	// Marshalling sorts by condition type
+	currentMarshaledJSON, _ := t.Conditions.MarshalJSON()
+	otherMarshaledJSON, _ := other.Conditions.MarshalJSON()
+	if string(currentMarshaledJSON) != string(otherMarshaledJSON) {
+		diff := cmp.Diff(string(currentMarshaledJSON), string(otherMarshaledJSON))
+		logger.V(1).Info("Conditions not equal", "difference", diff)
+		return false
+	}
+
+	return true
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+
+// Tenant is the Schema for the tenants API
+// +kubebuilder:resource:path=tenants,scope=Namespaced
+// +operator-sdk:csv:customresourcedefinitions:displayName="Tenant"
+type Tenant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TenantSpec   `json:"spec,omitempty"`
+	Status TenantStatus `json:"status,omitempty"`
+}
+
+// SetDefaults sets the default vaules for the tenant spec and returns true if the spec was changed
+func (t *Tenant) SetDefaults() bool {
+	changed := false
+	ts := &t.Spec
+	if ts.TenantSecretRef.Name == "" {
+		ts.TenantSecretRef.Name = fmt.Sprintf("%s-%s", strings.ToLower(t.Name), strings.ToLower(t.Spec.OrganizationName))
+		changed = true
+	}
+	if ts.TenantSecretRef.Namespace == "" {
+		ts.TenantSecretRef.Namespace = t.Namespace
+		changed = true
+	}
+	return changed
+}
+
+func (t *Tenant) Validate() field.ErrorList {
+	errors := field.ErrorList{}
+
+	specFldPath := field.NewPath("spec")
+	if t.Spec.Username == "" {
+		errors = append(errors, field.Required(specFldPath.Child("username"), "admin username required"))
+	}
+
+	if t.Spec.Email == "" {
+		errors = append(errors, field.Required(specFldPath.Child("email"), "admin email required"))
+	}
+
+	if t.Spec.OrganizationName == "" {
+		errors = append(errors, field.Required(specFldPath.Child("organizationName"), "organization name required"))
+	}
+
+	masterURLFldPath := specFldPath.Child("systemMasterUrl")
+	if t.Spec.SystemMasterUrl == "" {
+		errors = append(errors, field.Required(masterURLFldPath, "system master URL required"))
+	} else if _, err := url.ParseRequestURI(t.Spec.SystemMasterUrl); err != nil {
+		errors = append(errors, field.Invalid(masterURLFldPath, t.Spec.SystemMasterUrl, err.Error()))
+	}
+
+	if t.Spec.MasterCredentialsRef.Name == "" {
+		errors = append(errors, field.Required(specFldPath.Child("masterCredentialsRef").Child("name"), "master credentials secret name required"))
+	}
+
+	if t.Spec.PasswordCredentialsRef.Name == "" {
+		errors = append(errors, field.Required(specFldPath.Child("passwordCredentialsRef").Child("name"), "admin password secret name required"))
+	}
+
+	if t.Spec.AccessTokenRotationInterval != nil && t.Spec.AccessTokenRotationInterval.Duration <= 0 {
+		errors = append(errors, field.Invalid(specFldPath.Child("accessTokenRotationInterval"), t.Spec.AccessTokenRotationInterval.Duration.String(), "must be positive"))
+	}
+
+	return errors
+}
+
+// AccessTokenRotationRequested returns true when the rotate access token annotation
+// has a value not handled yet
+func (t *Tenant) AccessTokenRotationRequested() bool {
+	request := t.GetAnnotations()[TenantRotateAccessTokenAnnotation]
+	return request != "" && request != t.Status.AccessTokenRotationRequest
+}
+
+// NextAccessTokenRotation returns the time left until the next periodic access token rotation.
+// Returns zero or negative values when the rotation is due.
+// Returns false when periodic rotation is not enabled
+func (t *Tenant) NextAccessTokenRotation(now time.Time) (time.Duration, bool) {
+	if t.Spec.AccessTokenRotationInterval == nil {
+		return 0, false
+	}
+
+	if t.Status.AccessTokenRotationTime == nil {
+		return 0, true
+	}
+
+	return t.Status.AccessTokenRotationTime.Add(t.Spec.AccessTokenRotationInterval.Duration).Sub(now), true
+}
+
+// AccessTokenRotationRequired returns true when the tenant access token has to be rotated,
+// either requested by annotation or because the rotation interval has elapsed
+func (t *Tenant) AccessTokenRotationRequired(now time.Time) bool {
+	if t.AccessTokenRotationRequested() {
+		return true
+	}
+
+	next, enabled := t.NextAccessTokenRotation(now)
+	return enabled && next <= 0
+}
+
+// +kubebuilder:object:root=true
+
+// TenantList contains a list of Tenant
+type TenantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Tenant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Tenant{}, &TenantList{})
+}
@@ -0,0 +1,66 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTenantAccessTokenRotationRequired(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	lastRotation := metav1.NewTime(now.Add(-48 * time.Hour))
+
+	cases := []struct {
+		testName    string
+		annotations map[string]string
+		interval    *metav1.Duration
+		status      TenantStatus
+		expected    bool
+	}{
+		{"rotation not configured", nil, nil, TenantStatus{}, false},
+		{"new annotation request",
+			map[string]string{TenantRotateAccessTokenAnnotation: "1"}, nil, TenantStatus{}, true},
+		{"annotation request already handled",
+			map[string]string{TenantRotateAccessTokenAnnotation: "1"}, nil,
+			TenantStatus{AccessTokenRotationRequest: "1"}, false},
+		{"interval never rotated", nil, &metav1.Duration{Duration: time.Hour}, TenantStatus{}, true},
+		{"interval elapsed", nil, &metav1.Duration{Duration: 24 * time.Hour},
+			TenantStatus{AccessTokenRotationTime: &lastRotation}, true},
+		{"interval not elapsed", nil, &metav1.Duration{Duration: 72 * time.Hour},
+			TenantStatus{AccessTokenRotationTime: &lastRotation}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			tenant := &Tenant{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+				Spec:       TenantSpec{AccessTokenRotationInterval: tc.interval},
+				Status:     tc.status,
+			}
+
+			if got := tenant.AccessTokenRotationRequired(now); got != tc.expected {
+				subT.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestTenantNextAccessTokenRotation(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	lastRotation := metav1.NewTime(now.Add(-48 * time.Hour))
+
+	tenant := &Tenant{
+		Spec:   TenantSpec{AccessTokenRotationInterval: &metav1.Duration{Duration: 72 * time.Hour}},
+		Status: TenantStatus{AccessTokenRotationTime: &lastRotation},
+	}
+
+	next, enabled := tenant.NextAccessTokenRotation(now)
+	if !enabled {
+		t.Fatal("expected rotation to be enabled")
+	}
+
+	if next != 24*time.Hour {
+		t.Errorf("expected next rotation in %s, got %s", 24*time.Hour, next)
+	}
+}
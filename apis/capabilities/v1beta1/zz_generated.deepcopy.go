@@ -23,6 +23,7 @@ package v1beta1
 import (
 	"github.com/3scale/3scale-operator/pkg/common"
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -98,6 +99,11 @@ func (in *ActiveDocOpenAPIRefSpec) DeepCopyInto(out *ActiveDocOpenAPIRefSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.URLOptions != nil {
+		in, out := &in.URLOptions, &out.URLOptions
+		*out = new(OpenAPIURLOptionsSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveDocOpenAPIRefSpec.
@@ -118,6 +124,16 @@ func (in *ActiveDocSpec) DeepCopyInto(out *ActiveDocSpec) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.ProviderAccountNamespace != nil {
+		in, out := &in.ProviderAccountNamespace, &out.ProviderAccountNamespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.TenantRef != nil {
+		in, out := &in.TenantRef, &out.TenantRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 	if in.SystemName != nil {
 		in, out := &in.SystemName, &out.SystemName
 		*out = new(string)
@@ -278,6 +294,65 @@ func (in *AppKeyAppIDAuthenticationSpec) DeepCopy() *AppKeyAppIDAuthenticationSp
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Application) DeepCopyInto(out *Application) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Application.
+func (in *Application) DeepCopy() *Application {
+	if in == nil {
+		return nil
+	}
+	out := new(Application)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Application) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationList) DeepCopyInto(out *ApplicationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Application, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationList.
+func (in *ApplicationList) DeepCopy() *ApplicationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ApplicationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationPlanSpec) DeepCopyInto(out *ApplicationPlanSpec) {
 	*out = *in
@@ -337,6 +412,80 @@ func (in *ApplicationPlanSpec) DeepCopy() *ApplicationPlanSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSpec) DeepCopyInto(out *ApplicationSpec) {
+	*out = *in
+	out.DeveloperAccountRef = in.DeveloperAccountRef
+	out.ProductRef = in.ProductRef
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ProviderAccountRef != nil {
+		in, out := &in.ProviderAccountRef, &out.ProviderAccountRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ProviderAccountNamespace != nil {
+		in, out := &in.ProviderAccountNamespace, &out.ProviderAccountNamespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.TenantRef != nil {
+		in, out := &in.TenantRef, &out.TenantRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSpec.
+func (in *ApplicationSpec) DeepCopy() *ApplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationStatus) DeepCopyInto(out *ApplicationStatus) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.AccountID != nil {
+		in, out := &in.AccountID, &out.AccountID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ApplicationState != nil {
+		in, out := &in.ApplicationState, &out.ApplicationState
+		*out = new(string)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(common.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationStatus.
+func (in *ApplicationStatus) DeepCopy() *ApplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthenticationSpec) DeepCopyInto(out *AuthenticationSpec) {
 	*out = *in
@@ -455,6 +604,16 @@ func (in *BackendSpec) DeepCopyInto(out *BackendSpec) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.ProviderAccountNamespace != nil {
+		in, out := &in.ProviderAccountNamespace, &out.ProviderAccountNamespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.TenantRef != nil {
+		in, out := &in.TenantRef, &out.TenantRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendSpec.
@@ -576,6 +735,16 @@ func (in *CustomPolicyDefinitionSpec) DeepCopyInto(out *CustomPolicyDefinitionSp
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.ProviderAccountNamespace != nil {
+		in, out := &in.ProviderAccountNamespace, &out.ProviderAccountNamespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.TenantRef != nil {
+		in, out := &in.TenantRef, &out.TenantRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 	in.Schema.DeepCopyInto(&out.Schema)
 }
 
@@ -718,6 +887,16 @@ func (in *DeveloperAccountSpec) DeepCopyInto(out *DeveloperAccountSpec) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.ProviderAccountNamespace != nil {
+		in, out := &in.ProviderAccountNamespace, &out.ProviderAccountNamespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.TenantRef != nil {
+		in, out := &in.TenantRef, &out.TenantRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeveloperAccountSpec.
@@ -841,6 +1020,16 @@ func (in *DeveloperUserSpec) DeepCopyInto(out *DeveloperUserSpec) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.ProviderAccountNamespace != nil {
+		in, out := &in.ProviderAccountNamespace, &out.ProviderAccountNamespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.TenantRef != nil {
+		in, out := &in.TenantRef, &out.TenantRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeveloperUserSpec.
@@ -1138,6 +1327,56 @@ func (in *OpenAPI) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenAPIActiveDocSpec) DeepCopyInto(out *OpenAPIActiveDocSpec) {
+	*out = *in
+	if in.Published != nil {
+		in, out := &in.Published, &out.Published
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SkipSwaggerValidations != nil {
+		in, out := &in.SkipSwaggerValidations, &out.SkipSwaggerValidations
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIActiveDocSpec.
+func (in *OpenAPIActiveDocSpec) DeepCopy() *OpenAPIActiveDocSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenAPIActiveDocSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenAPIExternalRefSourceSpec) DeepCopyInto(out *OpenAPIExternalRefSourceSpec) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIExternalRefSourceSpec.
+func (in *OpenAPIExternalRefSourceSpec) DeepCopy() *OpenAPIExternalRefSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenAPIExternalRefSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenAPIList) DeepCopyInto(out *OpenAPIList) {
 	*out = *in
@@ -1170,6 +1409,37 @@ func (in *OpenAPIList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenAPIOIDCSpec) DeepCopyInto(out *OpenAPIOIDCSpec) {
+	*out = *in
+	out.IssuerEndpointRef = in.IssuerEndpointRef
+	if in.AuthenticationFlow != nil {
+		in, out := &in.AuthenticationFlow, &out.AuthenticationFlow
+		*out = new(OIDCAuthenticationFlowSpec)
+		**out = **in
+	}
+	if in.JwtClaimWithClientID != nil {
+		in, out := &in.JwtClaimWithClientID, &out.JwtClaimWithClientID
+		*out = new(string)
+		**out = **in
+	}
+	if in.JwtClaimWithClientIDType != nil {
+		in, out := &in.JwtClaimWithClientIDType, &out.JwtClaimWithClientIDType
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIOIDCSpec.
+func (in *OpenAPIOIDCSpec) DeepCopy() *OpenAPIOIDCSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenAPIOIDCSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenAPIRefSpec) DeepCopyInto(out *OpenAPIRefSpec) {
 	*out = *in
@@ -1178,11 +1448,33 @@ func (in *OpenAPIRefSpec) DeepCopyInto(out *OpenAPIRefSpec) {
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.DocumentKey != nil {
+		in, out := &in.DocumentKey, &out.DocumentKey
+		*out = new(string)
+		**out = **in
+	}
 	if in.URL != nil {
 		in, out := &in.URL, &out.URL
 		*out = new(string)
 		**out = **in
 	}
+	if in.URLOptions != nil {
+		in, out := &in.URLOptions, &out.URLOptions
+		*out = new(OpenAPIURLOptionsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalRefSources != nil {
+		in, out := &in.ExternalRefSources, &out.ExternalRefSources
+		*out = make([]OpenAPIExternalRefSourceSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIRefSpec.
@@ -1199,11 +1491,26 @@ func (in *OpenAPIRefSpec) DeepCopy() *OpenAPIRefSpec {
 func (in *OpenAPISpec) DeepCopyInto(out *OpenAPISpec) {
 	*out = *in
 	in.OpenAPIRef.DeepCopyInto(&out.OpenAPIRef)
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.ProviderAccountRef != nil {
 		in, out := &in.ProviderAccountRef, &out.ProviderAccountRef
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.ProviderAccountNamespace != nil {
+		in, out := &in.ProviderAccountNamespace, &out.ProviderAccountNamespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.TenantRef != nil {
+		in, out := &in.TenantRef, &out.TenantRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 	if in.ProductionPublicBaseURL != nil {
 		in, out := &in.ProductionPublicBaseURL, &out.ProductionPublicBaseURL
 		*out = new(string)
@@ -1224,6 +1531,11 @@ func (in *OpenAPISpec) DeepCopyInto(out *OpenAPISpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.BackendSplitMode != nil {
+		in, out := &in.BackendSplitMode, &out.BackendSplitMode
+		*out = new(string)
+		**out = **in
+	}
 	if in.PrefixMatching != nil {
 		in, out := &in.PrefixMatching, &out.PrefixMatching
 		*out = new(bool)
@@ -1239,6 +1551,21 @@ func (in *OpenAPISpec) DeepCopyInto(out *OpenAPISpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.SecuritySchemeName != nil {
+		in, out := &in.SecuritySchemeName, &out.SecuritySchemeName
+		*out = new(string)
+		**out = **in
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OpenAPIOIDCSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveDoc != nil {
+		in, out := &in.ActiveDoc, &out.ActiveDoc
+		*out = new(OpenAPIActiveDocSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPISpec.
@@ -1264,6 +1591,16 @@ func (in *OpenAPIStatus) DeepCopyInto(out *OpenAPIStatus) {
 		*out = make([]v1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.ActiveDocResourceName != nil {
+		in, out := &in.ActiveDocResourceName, &out.ActiveDocResourceName
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(common.Conditions, len(*in))
@@ -1283,6 +1620,38 @@ func (in *OpenAPIStatus) DeepCopy() *OpenAPIStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenAPIURLOptionsSpec) DeepCopyInto(out *OpenAPIURLOptionsSpec) {
+	*out = *in
+	if in.CredentialsRef != nil {
+		in, out := &in.CredentialsRef, &out.CredentialsRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CABundleRef != nil {
+		in, out := &in.CABundleRef, &out.CABundleRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIURLOptionsSpec.
+func (in *OpenAPIURLOptionsSpec) DeepCopy() *OpenAPIURLOptionsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenAPIURLOptionsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyConfig) DeepCopyInto(out *PolicyConfig) {
 	*out = *in
@@ -1447,6 +1816,16 @@ func (in *ProductSpec) DeepCopyInto(out *ProductSpec) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.ProviderAccountNamespace != nil {
+		in, out := &in.ProviderAccountNamespace, &out.ProviderAccountNamespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.TenantRef != nil {
+		in, out := &in.TenantRef, &out.TenantRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 	if in.Policies != nil {
 		in, out := &in.Policies, &out.Policies
 		*out = make([]PolicyConfig, len(*in))
@@ -1479,6 +1858,11 @@ func (in *ProductStatus) DeepCopyInto(out *ProductStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.StagingConfigVersion != nil {
+		in, out := &in.StagingConfigVersion, &out.StagingConfigVersion
+		*out = new(int64)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(common.Conditions, len(*in))
@@ -1498,6 +1882,118 @@ func (in *ProductStatus) DeepCopy() *ProductStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyConfigPromote) DeepCopyInto(out *ProxyConfigPromote) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyConfigPromote.
+func (in *ProxyConfigPromote) DeepCopy() *ProxyConfigPromote {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyConfigPromote)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProxyConfigPromote) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyConfigPromoteList) DeepCopyInto(out *ProxyConfigPromoteList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProxyConfigPromote, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyConfigPromoteList.
+func (in *ProxyConfigPromoteList) DeepCopy() *ProxyConfigPromoteList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyConfigPromoteList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProxyConfigPromoteList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyConfigPromoteSpec) DeepCopyInto(out *ProxyConfigPromoteSpec) {
+	*out = *in
+	out.ProductRef = in.ProductRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyConfigPromoteSpec.
+func (in *ProxyConfigPromoteSpec) DeepCopy() *ProxyConfigPromoteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyConfigPromoteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyConfigPromoteStatus) DeepCopyInto(out *ProxyConfigPromoteStatus) {
+	*out = *in
+	if in.ProductID != nil {
+		in, out := &in.ProductID, &out.ProductID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LatestStagingVersion != nil {
+		in, out := &in.LatestStagingVersion, &out.LatestStagingVersion
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LatestProductionVersion != nil {
+		in, out := &in.LatestProductionVersion, &out.LatestProductionVersion
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(common.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyConfigPromoteStatus.
+func (in *ProxyConfigPromoteStatus) DeepCopy() *ProxyConfigPromoteStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyConfigPromoteStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecuritySpec) DeepCopyInto(out *SecuritySpec) {
 	*out = *in
@@ -1523,6 +2019,124 @@ func (in *SecuritySpec) DeepCopy() *SecuritySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tenant) DeepCopyInto(out *Tenant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tenant.
+func (in *Tenant) DeepCopy() *Tenant {
+	if in == nil {
+		return nil
+	}
+	out := new(Tenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Tenant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantList) DeepCopyInto(out *TenantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Tenant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantList.
+func (in *TenantList) DeepCopy() *TenantList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
+	*out = *in
+	out.TenantSecretRef = in.TenantSecretRef
+	out.PasswordCredentialsRef = in.PasswordCredentialsRef
+	out.MasterCredentialsRef = in.MasterCredentialsRef
+	if in.AccessTokenRotationInterval != nil {
+		in, out := &in.AccessTokenRotationInterval, &out.AccessTokenRotationInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSpec.
+func (in *TenantSpec) DeepCopy() *TenantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
+	*out = *in
+	if in.AccessTokenID != nil {
+		in, out := &in.AccessTokenID, &out.AccessTokenID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.AccessTokenRotationTime != nil {
+		in, out := &in.AccessTokenRotationTime, &out.AccessTokenRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PreviousAccessTokenIDs != nil {
+		in, out := &in.PreviousAccessTokenIDs, &out.PreviousAccessTokenIDs
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(common.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantStatus.
+func (in *TenantStatus) DeepCopy() *TenantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UserKeyAuthenticationSpec) DeepCopyInto(out *UserKeyAuthenticationSpec) {
 	*out = *in
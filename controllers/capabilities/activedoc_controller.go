@@ -20,7 +20,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -35,9 +37,18 @@ import (
 	"github.com/3scale/3scale-operator/version"
 )
 
+const (
+	// activedocFinalizer removes the 3scale activedoc when the custom resource is deleted
+	activedocFinalizer = "activedoc.capabilities.3scale.net/finalizer"
+)
+
 // ActiveDocReconciler reconciles a ActiveDoc object
 type ActiveDocReconciler struct {
 	*reconcilers.BaseReconciler
+
+	// ResyncPeriod is the period to synchronize again activedocs that did not change.
+	// Zero disables the periodic resync.
+	ResyncPeriod time.Duration
 }
 
 // blank assignment to verify that BackendReconciler implements reconcile.Reconciler
@@ -45,6 +56,7 @@ var _ reconcile.Reconciler = &ActiveDocReconciler{}
 
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=activedocs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=activedocs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=activedocs/finalizers,verbs=get;list;watch;create;update;patch;delete
 
 func (r *ActiveDocReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	_ = context.Background()
@@ -74,10 +86,19 @@ func (r *ActiveDocReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		reqLogger.V(1).Info(string(jsonData))
 	}
 
-	// Ignore deleted resource, this can happen when foregroundDeletion is enabled
-	// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/#foreground-cascading-deletion
-	if activeDocCR.DeletionTimestamp != nil {
-		return ctrl.Result{}, nil
+	if activeDocCR.GetDeletionTimestamp() != nil {
+		return reconcileFinalizer(r.BaseReconciler, activeDocCR, activedocFinalizer, func() error {
+			return r.removeActiveDocFrom3scale(activeDocCR, reqLogger)
+		}, reqLogger)
+	}
+
+	finalizerAdded, err := ensureFinalizer(r.BaseReconciler, activeDocCR, activedocFinalizer)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if finalizerAdded {
+		reqLogger.Info("finalizer added. Requeueing.")
+		return ctrl.Result{Requeue: true}, nil
 	}
 
 	if activeDocCR.SetDefaults(reqLogger) {
@@ -123,7 +144,7 @@ func (r *ActiveDocReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, reconcileErr
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.ResyncPeriod}, nil
 }
 
 func (r *ActiveDocReconciler) reconcileSpec(activeDocCR *capabilitiesv1beta1.ActiveDoc, logger logr.Logger) (*ActiveDocStatusReconciler, error) {
@@ -133,7 +154,7 @@ func (r *ActiveDocReconciler) reconcileSpec(activeDocCR *capabilitiesv1beta1.Act
 		return statusReconciler, err
 	}
 
-	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), activeDocCR.Namespace, activeDocCR.Spec.ProviderAccountRef, logger)
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), activeDocCR.Namespace, activeDocCR.Spec.ProviderAccountRef, activeDocCR.Spec.ProviderAccountNamespace, activeDocCR.Spec.TenantRef, logger)
 	if err != nil {
 		statusReconciler := NewActiveDocStatusReconciler(r.BaseReconciler, activeDocCR, "", nil, err)
 		return statusReconciler, err
@@ -205,3 +226,29 @@ func (r *ActiveDocReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&capabilitiesv1beta1.ActiveDoc{}).
 		Complete(r)
 }
+
+// removeActiveDocFrom3scale removes the 3scale activedoc referenced in the status
+func (r *ActiveDocReconciler) removeActiveDocFrom3scale(activeDocCR *capabilitiesv1beta1.ActiveDoc, logger logr.Logger) error {
+	if activeDocCR.Status.ID == nil {
+		// 3scale activedoc has not been created
+		return nil
+	}
+
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), activeDocCR.Namespace, activeDocCR.Spec.ProviderAccountRef, activeDocCR.Spec.ProviderAccountNamespace, activeDocCR.Spec.TenantRef, logger)
+	if err != nil {
+		return err
+	}
+
+	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	if err != nil {
+		return err
+	}
+
+	err = threescaleAPIClient.DeleteActiveDoc(*activeDocCR.Status.ID)
+	if err != nil && !threescaleapi.IsNotFound(err) {
+		return fmt.Errorf("Error deleting activedoc [%s;%d]: %w", activeDocCR.Name, *activeDocCR.Status.ID, err)
+	}
+
+	logger.Info("activedoc removed from 3scale", "ID", *activeDocCR.Status.ID)
+	return nil
+}
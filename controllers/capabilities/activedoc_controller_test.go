@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestActiveDocReconcilerDeletion(t *testing.T) {
+	deleted := false
+	server, secret := newTestThreescaleServer(t, "myns", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodDelete && req.URL.Path == "/admin/api/active_docs/4.json" {
+			deleted = true
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	var activeDocID int64 = 4
+	now := metav1.Now()
+	activeDoc := &capabilitiesv1beta1.ActiveDoc{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "myactivedoc",
+			Namespace:         "myns",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{activedocFinalizer},
+		},
+		Spec:   capabilitiesv1beta1.ActiveDocSpec{Name: "My ActiveDoc"},
+		Status: capabilitiesv1beta1.ActiveDocStatus{ID: &activeDocID},
+	}
+
+	r := &ActiveDocReconciler{BaseReconciler: newTestBaseReconciler(t, activeDoc, secret)}
+	key := types.NamespacedName{Name: activeDoc.Name, Namespace: activeDoc.Namespace}
+	_, err := r.Reconcile(reconcile.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !deleted {
+		t.Fatal("3scale activedoc not deleted")
+	}
+
+	reconciled := &capabilitiesv1beta1.ActiveDoc{}
+	err = r.Client().Get(context.TODO(), key, reconciled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if controllerutil.ContainsFinalizer(reconciled, activedocFinalizer) {
+		t.Fatalf("finalizer not removed: %v", reconciled.Finalizers)
+	}
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"errors"
+	"fmt"
 	"net/url"
 	"reflect"
 
@@ -253,7 +254,7 @@ func (s *ActiveDocThreescaleReconciler) readOpenAPISecret() (*openapi3.Swagger,
 		}
 	}
 
-	err = openapiObj.Validate(s.Context())
+	err = helper.ValidateOpenAPI(s.Context(), openapiObj)
 	if err != nil {
 		fieldErrors = append(fieldErrors, field.Invalid(secretRefFldPath, s.resource.Spec.ActiveDocOpenAPIRef.SecretRef, err.Error()))
 		return nil, &helper.SpecFieldError{
@@ -280,7 +281,13 @@ func (s *ActiveDocThreescaleReconciler) readOpenAPIFromURL() (*openapi3.Swagger,
 		}
 	}
 
-	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromURI(openAPIURL)
+	// fetch errors might be transient, retry
+	data, err := controllerhelper.FetchOpenAPIURL(s.Context(), s.Client(), s.resource.Namespace, openAPIURL, s.resource.Spec.ActiveDocOpenAPIRef.URLOptions)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch OpenAPI document: %w", err)
+	}
+
+	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromDataWithPath(data, openAPIURL)
 	if err != nil {
 		fieldErrors = append(fieldErrors, field.Invalid(urlRefFldPath, s.resource.Spec.ActiveDocOpenAPIRef.URL, err.Error()))
 		return nil, &helper.SpecFieldError{
@@ -289,7 +296,7 @@ func (s *ActiveDocThreescaleReconciler) readOpenAPIFromURL() (*openapi3.Swagger,
 		}
 	}
 
-	err = openapiObj.Validate(s.Context())
+	err = helper.ValidateOpenAPI(s.Context(), openapiObj)
 	if err != nil {
 		fieldErrors = append(fieldErrors, field.Invalid(urlRefFldPath, s.resource.Spec.ActiveDocOpenAPIRef.URL, err.Error()))
 		return nil, &helper.SpecFieldError{
@@ -0,0 +1,319 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+	"github.com/3scale/3scale-operator/version"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// applicationFinalizer removes the 3scale application when the custom resource is deleted
+	applicationFinalizer = "application.capabilities.3scale.net/finalizer"
+)
+
+// ApplicationReconciler reconciles a Application object
+type ApplicationReconciler struct {
+	*reconcilers.BaseReconciler
+
+	// ResyncPeriod is the period to synchronize again applications that did not change.
+	// Zero disables the periodic resync.
+	ResyncPeriod time.Duration
+}
+
+// blank assignment to verify that ApplicationReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ApplicationReconciler{}
+
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=applications,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=applications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=applications/finalizers,verbs=get;list;watch;create;update;patch;delete
+
+func (r *ApplicationReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Logger().WithValues("application", req.NamespacedName)
+	reqLogger.Info("Reconcile Application", "Operator version", version.Version)
+
+	// Fetch the instance
+	applicationCR := &capabilitiesv1beta1.Application{}
+	err := r.Client().Get(context.TODO(), req.NamespacedName, applicationCR)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			reqLogger.Info("resource not found. Ignoring since object must have been deleted")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	if reqLogger.V(1).Enabled() {
+		jsonData, err := json.MarshalIndent(applicationCR, "", "  ")
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		reqLogger.V(1).Info(string(jsonData))
+	}
+
+	if applicationCR.GetDeletionTimestamp() != nil {
+		return reconcileFinalizer(r.BaseReconciler, applicationCR, applicationFinalizer, func() error {
+			return r.removeApplicationFrom3scale(applicationCR, reqLogger)
+		}, reqLogger)
+	}
+
+	finalizerAdded, err := ensureFinalizer(r.BaseReconciler, applicationCR, applicationFinalizer)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if finalizerAdded {
+		reqLogger.Info("finalizer added. Requeueing.")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	statusReconciler, reconcileErr := r.reconcileSpec(applicationCR, reqLogger)
+	statusResult, statusUpdateErr := statusReconciler.Reconcile()
+	if statusUpdateErr != nil {
+		if reconcileErr != nil {
+			return ctrl.Result{}, fmt.Errorf("Failed to reconcile application: %v. Failed to update status: %w", reconcileErr, statusUpdateErr)
+		}
+
+		return ctrl.Result{}, fmt.Errorf("Failed to update application status: %w", statusUpdateErr)
+	}
+
+	if statusResult.Requeue {
+		return statusResult, nil
+	}
+
+	if reconcileErr != nil {
+		if helper.IsInvalidSpecError(reconcileErr) {
+			// On Validation error, no need to retry as spec is not valid and needs to be changed
+			reqLogger.Info("ERROR", "spec validation error", reconcileErr)
+			r.EventRecorder().Eventf(applicationCR, corev1.EventTypeWarning, "Invalid application spec", "%v", reconcileErr)
+			return ctrl.Result{}, nil
+		}
+
+		if helper.IsOrphanSpecError(reconcileErr) {
+			// On Orphan spec error, retry
+			reqLogger.Info("orphan", "message", reconcileErr)
+			return ctrl.Result{Requeue: true}, nil
+		}
+
+		reqLogger.Error(reconcileErr, "Failed to reconcile")
+		r.EventRecorder().Eventf(applicationCR, corev1.EventTypeWarning, "ReconcileError", "%v", reconcileErr)
+		return ctrl.Result{}, reconcileErr
+	}
+
+	return ctrl.Result{RequeueAfter: r.ResyncPeriod}, nil
+}
+
+func (r *ApplicationReconciler) reconcileSpec(applicationCR *capabilitiesv1beta1.Application, logger logr.Logger) (*ApplicationStatusReconciler, error) {
+	err := r.validateSpec(applicationCR)
+	if err != nil {
+		statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, "", nil, err)
+		return statusReconciler, err
+	}
+
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), applicationCR.Namespace, applicationCR.Spec.ProviderAccountRef, applicationCR.Spec.ProviderAccountNamespace, applicationCR.Spec.TenantRef, logger)
+	if err != nil {
+		statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, "", nil, err)
+		return statusReconciler, err
+	}
+
+	developerAccountCR, err := r.findDeveloperAccount(applicationCR, providerAccount, logger)
+	if err != nil {
+		statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, providerAccount.AdminURLStr, nil, err)
+		return statusReconciler, err
+	}
+
+	productCR, err := r.findProduct(applicationCR, providerAccount, logger)
+	if err != nil {
+		statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, providerAccount.AdminURLStr, nil, err)
+		return statusReconciler, err
+	}
+
+	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	if err != nil {
+		statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, providerAccount.AdminURLStr, nil, err)
+		return statusReconciler, err
+	}
+
+	restClient, err := controllerhelper.RESTClient(providerAccount)
+	if err != nil {
+		statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, providerAccount.AdminURLStr, nil, err)
+		return statusReconciler, err
+	}
+
+	reconciler := NewApplicationThreescaleReconciler(r.BaseReconciler, applicationCR, developerAccountCR, productCR, threescaleAPIClient, restClient, providerAccount.AdminURLStr, logger)
+	applicationObj, err := reconciler.Reconcile()
+
+	statusReconciler := NewApplicationStatusReconciler(r.BaseReconciler, applicationCR, providerAccount.AdminURLStr, applicationObj, err)
+	return statusReconciler, err
+}
+
+func (r *ApplicationReconciler) validateSpec(resource *capabilitiesv1beta1.Application) error {
+	errors := field.ErrorList{}
+	errors = append(errors, resource.Validate()...)
+
+	if len(errors) == 0 {
+		return nil
+	}
+
+	return &helper.SpecFieldError{
+		ErrorType:      helper.InvalidError,
+		FieldErrorList: errors,
+	}
+}
+
+func (r *ApplicationReconciler) findDeveloperAccount(applicationCR *capabilitiesv1beta1.Application, providerAccount *controllerhelper.ProviderAccount, logger logr.Logger) (*capabilitiesv1beta1.DeveloperAccount, error) {
+	accountFldPath := field.NewPath("spec").Child("developerAccountRef")
+
+	devAccountCR := &capabilitiesv1beta1.DeveloperAccount{}
+	devAccountKey := types.NamespacedName{Name: applicationCR.Spec.DeveloperAccountRef.Name, Namespace: applicationCR.Namespace}
+	if err := r.Client().Get(r.Context(), devAccountKey, devAccountCR); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, &helper.SpecFieldError{
+				ErrorType: helper.OrphanError,
+				FieldErrorList: field.ErrorList{
+					field.Invalid(accountFldPath, applicationCR.Spec.DeveloperAccountRef, "developer account resource not found"),
+				},
+			}
+		}
+
+		return nil, err
+	}
+
+	// Check it belongs to the same providerAccount
+	accountProviderAccount, err := controllerhelper.LookupProviderAccount(r.Client(), applicationCR.Namespace, devAccountCR.Spec.ProviderAccountRef, devAccountCR.Spec.ProviderAccountNamespace, devAccountCR.Spec.TenantRef, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if providerAccount.AdminURLStr != accountProviderAccount.AdminURLStr {
+		return nil, &helper.SpecFieldError{
+			ErrorType: helper.OrphanError,
+			FieldErrorList: field.ErrorList{
+				field.Invalid(accountFldPath, applicationCR.Spec.DeveloperAccountRef, "developer account resource does not belong to the same provider account"),
+			},
+		}
+	}
+
+	if !devAccountCR.Status.IsReady() || devAccountCR.Status.ID == nil {
+		return nil, &helper.SpecFieldError{
+			ErrorType: helper.OrphanError,
+			FieldErrorList: field.ErrorList{
+				field.Invalid(accountFldPath, applicationCR.Spec.DeveloperAccountRef, "developer account resource not ready"),
+			},
+		}
+	}
+
+	return devAccountCR, nil
+}
+
+func (r *ApplicationReconciler) findProduct(applicationCR *capabilitiesv1beta1.Application, providerAccount *controllerhelper.ProviderAccount, logger logr.Logger) (*capabilitiesv1beta1.Product, error) {
+	productFldPath := field.NewPath("spec").Child("productRef")
+
+	productCR := &capabilitiesv1beta1.Product{}
+	productKey := types.NamespacedName{Name: applicationCR.Spec.ProductRef.Name, Namespace: applicationCR.Namespace}
+	if err := r.Client().Get(r.Context(), productKey, productCR); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, &helper.SpecFieldError{
+				ErrorType: helper.OrphanError,
+				FieldErrorList: field.ErrorList{
+					field.Invalid(productFldPath, applicationCR.Spec.ProductRef, "product resource not found"),
+				},
+			}
+		}
+
+		return nil, err
+	}
+
+	// Check it belongs to the same providerAccount
+	productProviderAccount, err := controllerhelper.LookupProviderAccount(r.Client(), applicationCR.Namespace, productCR.Spec.ProviderAccountRef, productCR.Spec.ProviderAccountNamespace, productCR.Spec.TenantRef, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if providerAccount.AdminURLStr != productProviderAccount.AdminURLStr {
+		return nil, &helper.SpecFieldError{
+			ErrorType: helper.OrphanError,
+			FieldErrorList: field.ErrorList{
+				field.Invalid(productFldPath, applicationCR.Spec.ProductRef, "product resource does not belong to the same provider account"),
+			},
+		}
+	}
+
+	if !productCR.IsSynced() || productCR.Status.ID == nil {
+		return nil, &helper.SpecFieldError{
+			ErrorType: helper.OrphanError,
+			FieldErrorList: field.ErrorList{
+				field.Invalid(productFldPath, applicationCR.Spec.ProductRef, "product resource not synced"),
+			},
+		}
+	}
+
+	return productCR, nil
+}
+
+// removeApplicationFrom3scale removes the 3scale application referenced in the status
+func (r *ApplicationReconciler) removeApplicationFrom3scale(applicationCR *capabilitiesv1beta1.Application, logger logr.Logger) error {
+	if applicationCR.Status.ID == nil || applicationCR.Status.AccountID == nil {
+		// 3scale application has not been created
+		return nil
+	}
+
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), applicationCR.Namespace, applicationCR.Spec.ProviderAccountRef, applicationCR.Spec.ProviderAccountNamespace, applicationCR.Spec.TenantRef, logger)
+	if err != nil {
+		return err
+	}
+
+	restClient, err := controllerhelper.RESTClient(providerAccount)
+	if err != nil {
+		return err
+	}
+
+	// Not found when the developer account has already been removed
+	err = restClient.DeleteApplication(*applicationCR.Status.AccountID, *applicationCR.Status.ID)
+	if err != nil && !controllerhelper.IsThreescaleNotFound(err) {
+		return fmt.Errorf("Error deleting application [%s;%d]: %w", applicationCR.Name, *applicationCR.Status.ID, err)
+	}
+
+	logger.Info("application removed from 3scale", "ID", *applicationCR.Status.ID)
+	return nil
+}
+
+func (r *ApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&capabilitiesv1beta1.Application{}).
+		Owns(&corev1.Secret{}).
+		Complete(r)
+}
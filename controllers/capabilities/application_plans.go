@@ -51,6 +51,7 @@ func (t *ProductThreescaleReconciler) syncApplicationPlans(_ interface{}) error
 	for _, systemName := range matchedKeys {
 		// interface to remote entity
 		planEntity := controllerhelper.NewApplicationPlanEntity(t.productEntity.ID(), existingMap[systemName], t.threescaleAPIClient, t.logger)
+		planEntity.SetDryRun(t.dryRun)
 		// desired spec
 		planSpec := t.resource.Spec.ApplicationPlans[systemName]
 		reconciler := newApplicationPlanReconciler(t.BaseReconciler, systemName, planSpec, t.threescaleAPIClient, t.productEntity, t.backendRemoteIndex, planEntity, t.logger)
@@ -80,6 +81,7 @@ func (t *ProductThreescaleReconciler) syncApplicationPlans(_ interface{}) error
 		}
 		// interface to remote entity
 		planEntity := controllerhelper.NewApplicationPlanEntity(t.productEntity.ID(), obj.Element, t.threescaleAPIClient, t.logger)
+		planEntity.SetDryRun(t.dryRun)
 
 		reconciler := newApplicationPlanReconciler(t.BaseReconciler, systemName, planSpec, t.threescaleAPIClient, t.productEntity, t.backendRemoteIndex, planEntity, t.logger)
 		err = reconciler.Reconcile()
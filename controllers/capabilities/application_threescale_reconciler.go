@@ -0,0 +1,306 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/common"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	oprand "github.com/3scale/3scale-operator/pkg/crypto/rand"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+type ApplicationThreescaleReconciler struct {
+	*reconcilers.BaseReconciler
+	applicationCR       *capabilitiesv1beta1.Application
+	developerAccountCR  *capabilitiesv1beta1.DeveloperAccount
+	productCR           *capabilitiesv1beta1.Product
+	threescaleAPIClient *threescaleapi.ThreeScaleClient
+	restClient          *controllerhelper.ThreescaleRESTClient
+	providerAccountHost string
+	logger              logr.Logger
+}
+
+func NewApplicationThreescaleReconciler(b *reconcilers.BaseReconciler,
+	applicationCR *capabilitiesv1beta1.Application,
+	developerAccountCR *capabilitiesv1beta1.DeveloperAccount,
+	productCR *capabilitiesv1beta1.Product,
+	threescaleAPIClient *threescaleapi.ThreeScaleClient,
+	restClient *controllerhelper.ThreescaleRESTClient,
+	providerAccountHost string,
+	logger logr.Logger,
+) *ApplicationThreescaleReconciler {
+	return &ApplicationThreescaleReconciler{
+		BaseReconciler:      b,
+		applicationCR:       applicationCR,
+		developerAccountCR:  developerAccountCR,
+		productCR:           productCR,
+		threescaleAPIClient: threescaleAPIClient,
+		restClient:          restClient,
+		providerAccountHost: providerAccountHost,
+		logger:              logger.WithValues("3scale Reconciler", providerAccountHost),
+	}
+}
+
+func (s *ApplicationThreescaleReconciler) Reconcile() (*controllerhelper.Application, error) {
+	s.logger.V(1).Info("START")
+
+	// parent resources are ready, hence IDs are not nil
+	accountID := *s.developerAccountCR.Status.ID
+	productID := *s.productCR.Status.ID
+
+	planID, err := s.findPlanID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	application, err := s.findApplication(accountID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if application == nil {
+		s.logger.V(1).Info("Application does not exist", "name", s.applicationCR.Spec.Name)
+		application, err = s.createApplication(accountID, planID)
+		if err != nil {
+			return application, err
+		}
+	} else {
+		s.logger.V(1).Info("Application already exists", "ID", application.Element.ID)
+	}
+
+	// The application is returned on errors as well, so the status keeps track of the application ID
+	syncedApplication, err := s.syncApplication(application, planID)
+	if err != nil {
+		return application, err
+	}
+
+	err = s.reconcileCredentialsSecret(syncedApplication)
+	if err != nil {
+		return syncedApplication, err
+	}
+
+	return syncedApplication, nil
+}
+
+func (s *ApplicationThreescaleReconciler) findPlanID(productID int64) (int64, error) {
+	planList, err := s.threescaleAPIClient.ListApplicationPlansByProduct(productID)
+	if err != nil {
+		return 0, fmt.Errorf("Error reading product [%s] application plans: %w", s.productCR.Spec.SystemName, err)
+	}
+
+	for idx := range planList.Plans {
+		if planList.Plans[idx].Element.SystemName == s.applicationCR.Spec.ApplicationPlanSystemName {
+			return planList.Plans[idx].Element.ID, nil
+		}
+	}
+
+	planFldPath := field.NewPath("spec").Child("applicationPlanSystemName")
+	return 0, &helper.SpecFieldError{
+		ErrorType: helper.OrphanError,
+		FieldErrorList: field.ErrorList{
+			field.Invalid(planFldPath, s.applicationCR.Spec.ApplicationPlanSystemName, "application plan not found in product"),
+		},
+	}
+}
+
+// findApplication looks up the application created by the custom resource, referenced by the status ID.
+// Applications cannot be moved between accounts or products,
+// thus, changes of any of the references are rejected.
+func (s *ApplicationThreescaleReconciler) findApplication(accountID, productID int64) (*controllerhelper.Application, error) {
+	status := s.applicationCR.Status
+	if status.ID == nil || status.AccountID == nil {
+		return nil, nil
+	}
+
+	application, err := s.restClient.Application(*status.AccountID, *status.ID)
+	if err != nil {
+		if controllerhelper.IsThreescaleNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Error reading application [%d]: %w", *status.ID, err)
+	}
+
+	specFldPath := field.NewPath("spec")
+	fieldErrors := field.ErrorList{}
+	if application.Element.AccountID != accountID {
+		fieldErrors = append(fieldErrors, field.Invalid(specFldPath.Child("developerAccountRef"), s.applicationCR.Spec.DeveloperAccountRef.Name, "application cannot be moved to another developer account"))
+	}
+	if application.Element.ProductID != productID {
+		fieldErrors = append(fieldErrors, field.Invalid(specFldPath.Child("productRef"), s.applicationCR.Spec.ProductRef.Name, "application cannot be moved to another product"))
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, &helper.SpecFieldError{
+			ErrorType:      helper.InvalidError,
+			FieldErrorList: fieldErrors,
+		}
+	}
+
+	return application, nil
+}
+
+// createApplication creates the application and records its ID in the status right away.
+// Otherwise, the application would be created again when the reconciliation fails before the status is updated.
+func (s *ApplicationThreescaleReconciler) createApplication(accountID, planID int64) (*controllerhelper.Application, error) {
+	app, err := s.threescaleAPIClient.CreateApp(strconv.FormatInt(accountID, 10), strconv.FormatInt(planID, 10), s.applicationCR.Spec.Name, s.applicationCR.Spec.Description)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating application [%s]: %w", s.applicationCR.Spec.Name, err)
+	}
+
+	application := &controllerhelper.Application{
+		Element: controllerhelper.ApplicationItem{
+			ID:          app.ID,
+			State:       app.State,
+			AccountID:   accountID,
+			ProductID:   app.ServiceID,
+			PlanID:      app.PlanID,
+			Name:        app.AppName,
+			Description: app.Description,
+			UserKey:     app.UserKey,
+		},
+	}
+
+	s.applicationCR.Status.ID = &application.Element.ID
+	s.applicationCR.Status.AccountID = &application.Element.AccountID
+	err = s.Client().Status().Update(s.Context(), s.applicationCR)
+	if err != nil {
+		return application, fmt.Errorf("Error recording application [%d] ID: %w", app.ID, err)
+	}
+
+	// Read the application back, the response of the creation does not include the application ID credential
+	readApplication, err := s.restClient.Application(accountID, app.ID)
+	if err != nil {
+		return application, fmt.Errorf("Error reading application [%d]: %w", app.ID, err)
+	}
+
+	return readApplication, nil
+}
+
+func (s *ApplicationThreescaleReconciler) syncApplication(application *controllerhelper.Application, planID int64) (*controllerhelper.Application, error) {
+	var err error
+	accountID := application.Element.AccountID
+	ID := application.Element.ID
+
+	if application.Element.Name != s.applicationCR.Spec.Name || application.Element.Description != s.applicationCR.Spec.Description {
+		application, err = s.restClient.UpdateApplication(accountID, ID, s.applicationCR.Spec.Name, s.applicationCR.Spec.Description)
+		if err != nil {
+			return nil, fmt.Errorf("Error sync application [%d] attributes: %w", ID, err)
+		}
+	}
+
+	if application.Element.PlanID != planID {
+		application, err = s.restClient.ChangeApplicationPlan(accountID, ID, planID)
+		if err != nil {
+			return nil, fmt.Errorf("Error sync application [%d] plan: %w", ID, err)
+		}
+	}
+
+	isSuspended := application.Element.State == controllerhelper.ApplicationStateSuspended
+	if s.applicationCR.Spec.Suspended && !isSuspended {
+		application, err = s.restClient.SuspendApplication(accountID, ID)
+		if err != nil {
+			return nil, fmt.Errorf("Error suspending application [%d]: %w", ID, err)
+		}
+	} else if !s.applicationCR.Spec.Suspended && isSuspended {
+		application, err = s.restClient.ResumeApplication(accountID, ID)
+		if err != nil {
+			return nil, fmt.Errorf("Error resuming application [%d]: %w", ID, err)
+		}
+	}
+
+	return application, nil
+}
+
+func (s *ApplicationThreescaleReconciler) reconcileCredentialsSecret(application *controllerhelper.Application) error {
+	credentials, err := s.applicationCredentials(application)
+	if err != nil {
+		return err
+	}
+
+	desired := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.applicationCR.CredentialsSecretName(),
+			Namespace: s.applicationCR.Namespace,
+		},
+		StringData: credentials,
+		Type:       corev1.SecretTypeOpaque,
+	}
+
+	err = s.SetOwnerReference(s.applicationCR, desired)
+	if err != nil {
+		return err
+	}
+
+	return s.ReconcileResource(&corev1.Secret{}, desired, applicationCredentialsSecretMutator)
+}
+
+// applicationCredentials returns user_key or app_id/app_key pair depending on product's authentication mode
+func (s *ApplicationThreescaleReconciler) applicationCredentials(application *controllerhelper.Application) (map[string]string, error) {
+	if application.Element.UserKey != "" {
+		return map[string]string{
+			capabilitiesv1beta1.ApplicationUserKeySecretField: application.Element.UserKey,
+		}, nil
+	}
+
+	accountID := application.Element.AccountID
+	ID := application.Element.ID
+
+	keys, err := s.restClient.ApplicationKeys(accountID, ID)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading application [%d] keys: %w", ID, err)
+	}
+
+	if len(keys) == 0 {
+		key := oprand.String(32)
+		err = s.restClient.CreateApplicationKey(accountID, ID, key)
+		if err != nil {
+			return nil, fmt.Errorf("Error creating application [%d] key: %w", ID, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return map[string]string{
+		capabilitiesv1beta1.ApplicationAppIDSecretField:  application.Element.ApplicationID,
+		capabilitiesv1beta1.ApplicationAppKeySecretField: keys[0],
+	}, nil
+}
+
+func applicationCredentialsSecretMutator(existingObj, desiredObj common.KubernetesObject) (bool, error) {
+	existing, ok := existingObj.(*corev1.Secret)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *corev1.Secret", existingObj)
+	}
+	desired, ok := desiredObj.(*corev1.Secret)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *corev1.Secret", desiredObj)
+	}
+
+	updated := false
+	for fieldName := range desired.StringData {
+		updated = reconcilers.SecretReconcileField(desired, existing, fieldName) || updated
+	}
+
+	// Credentials from previous authentication mode
+	for fieldName := range existing.Data {
+		if _, ok := desired.StringData[fieldName]; !ok {
+			delete(existing.Data, fieldName)
+			updated = true
+		}
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/helper"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+	logrtesting "github.com/go-logr/logr/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestApplicationThreescaleReconcilerPartialFailure(t *testing.T) {
+	var (
+		accountID int64 = 3
+		productID int64 = 4
+		planID    int64 = 5
+		appID     int64 = 6
+	)
+
+	applicationCR := &capabilitiesv1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "myapp", Namespace: "myns"},
+		Spec: capabilitiesv1beta1.ApplicationSpec{
+			Name:                      "My App",
+			ApplicationPlanSystemName: "basic",
+			Suspended:                 true,
+		},
+	}
+	developerAccountCR := &capabilitiesv1beta1.DeveloperAccount{
+		Status: capabilitiesv1beta1.DeveloperAccountStatus{ID: &accountID},
+	}
+	productCR := &capabilitiesv1beta1.Product{
+		Spec:   capabilitiesv1beta1.ProductSpec{SystemName: "myproduct"},
+		Status: capabilitiesv1beta1.ProductStatus{ID: &productID},
+	}
+
+	// 3scale state
+	remoteApps := []controllerhelper.Application{}
+	createCalls := 0
+	suspendFails := true
+
+	appsEndpoint := fmt.Sprintf("/admin/api/accounts/%d/applications.json", accountID)
+	appEndpoint := fmt.Sprintf("/admin/api/accounts/%d/applications/%d.json", accountID, appID)
+	suspendEndpoint := fmt.Sprintf("/admin/api/accounts/%d/applications/%d/suspend.json", accountID, appID)
+	httpClient := newTestHTTPClient(func(req *http.Request) *http.Response {
+		switch {
+		case req.Method == http.MethodGet && req.URL.Path == fmt.Sprintf("/admin/api/services/%d/application_plans.json", productID):
+			return newTestJSONResponse(t, http.StatusOK, &threescaleapi.ApplicationPlanJSONList{
+				Plans: []threescaleapi.ApplicationPlan{
+					{Element: threescaleapi.ApplicationPlanItem{ID: planID, SystemName: "basic"}},
+				},
+			})
+		case req.Method == http.MethodPost && req.URL.Path == appsEndpoint:
+			createCalls++
+			app := controllerhelper.Application{Element: controllerhelper.ApplicationItem{
+				ID: appID, State: "live", AccountID: accountID, ProductID: productID, PlanID: planID,
+				Name: req.FormValue("name"), UserKey: "userkey",
+			}}
+			remoteApps = append(remoteApps, app)
+			return newTestJSONResponse(t, http.StatusCreated, &threescaleapi.ApplicationElem{
+				Application: threescaleapi.Application{
+					ID: appID, State: "live", ServiceID: productID, PlanID: planID, AppName: app.Element.Name, UserKey: "userkey",
+				},
+			})
+		case req.Method == http.MethodGet && req.URL.Path == appEndpoint && len(remoteApps) > 0:
+			return newTestJSONResponse(t, http.StatusOK, &remoteApps[0])
+		case req.Method == http.MethodPut && req.URL.Path == suspendEndpoint:
+			if suspendFails {
+				return newTestJSONResponse(t, http.StatusInternalServerError, map[string]string{})
+			}
+			remoteApps[0].Element.State = controllerhelper.ApplicationStateSuspended
+			return newTestJSONResponse(t, http.StatusOK, &remoteApps[0])
+		}
+
+		t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		return newTestJSONResponse(t, http.StatusNotFound, map[string]string{})
+	})
+
+	adminPortal, err := threescaleapi.NewAdminPortal("https", "www.example.com", 443)
+	if err != nil {
+		t.Fatal(err)
+	}
+	portaClient := threescaleapi.NewThreeScale(adminPortal, "12345", httpClient)
+	restClient, err := controllerhelper.NewThreescaleRESTClient("https://www.example.com", "12345", httpClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	baseReconciler := newTestBaseReconciler(t, applicationCR)
+
+	reconciler := NewApplicationThreescaleReconciler(baseReconciler, applicationCR, developerAccountCR, productCR,
+		portaClient, restClient, "https://www.example.com", logrtesting.NullLogger{})
+
+	// the application is created, but suspending it fails
+	application, err := reconciler.Reconcile()
+	if err == nil {
+		t.Fatal("error expected")
+	}
+	if application == nil || application.Element.ID != appID {
+		t.Fatalf("created application expected along with the error, got %v", application)
+	}
+
+	// the application ID is recorded before the status is reconciled
+	storedCR := &capabilitiesv1beta1.Application{}
+	err = baseReconciler.Client().Get(baseReconciler.Context(), types.NamespacedName{Name: "myapp", Namespace: "myns"}, storedCR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storedCR.Status.ID == nil || *storedCR.Status.ID != appID || storedCR.Status.AccountID == nil || *storedCR.Status.AccountID != accountID {
+		t.Fatalf("application ID not recorded: %v", storedCR.Status)
+	}
+
+	// the application is found by the recorded ID
+	suspendFails = false
+	application, err = reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if application.Element.ID != appID || application.Element.State != controllerhelper.ApplicationStateSuspended {
+		t.Fatalf("unexpected application %v", application.Element)
+	}
+	if createCalls != 1 {
+		t.Fatalf("application created %d times", createCalls)
+	}
+
+	secret := &corev1.Secret{}
+	err = baseReconciler.Client().Get(baseReconciler.Context(), types.NamespacedName{Name: "myapp-credentials", Namespace: "myns"}, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplicationThreescaleReconcilerReferenceChange(t *testing.T) {
+	var (
+		accountID    int64 = 3
+		newAccountID int64 = 7
+		productID    int64 = 4
+		planID       int64 = 5
+		appID        int64 = 6
+	)
+
+	applicationCR := &capabilitiesv1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "myapp", Namespace: "myns"},
+		Spec: capabilitiesv1beta1.ApplicationSpec{
+			Name:                      "My App",
+			DeveloperAccountRef:       corev1.LocalObjectReference{Name: "newaccount"},
+			ApplicationPlanSystemName: "basic",
+		},
+		Status: capabilitiesv1beta1.ApplicationStatus{ID: &appID, AccountID: &accountID},
+	}
+	developerAccountCR := &capabilitiesv1beta1.DeveloperAccount{
+		Status: capabilitiesv1beta1.DeveloperAccountStatus{ID: &newAccountID},
+	}
+	productCR := &capabilitiesv1beta1.Product{
+		Spec:   capabilitiesv1beta1.ProductSpec{SystemName: "myproduct"},
+		Status: capabilitiesv1beta1.ProductStatus{ID: &productID},
+	}
+
+	httpClient := newTestHTTPClient(func(req *http.Request) *http.Response {
+		switch {
+		case req.Method == http.MethodGet && req.URL.Path == fmt.Sprintf("/admin/api/services/%d/application_plans.json", productID):
+			return newTestJSONResponse(t, http.StatusOK, &threescaleapi.ApplicationPlanJSONList{
+				Plans: []threescaleapi.ApplicationPlan{
+					{Element: threescaleapi.ApplicationPlanItem{ID: planID, SystemName: "basic"}},
+				},
+			})
+		case req.Method == http.MethodGet && req.URL.Path == fmt.Sprintf("/admin/api/accounts/%d/applications/%d.json", accountID, appID):
+			return newTestJSONResponse(t, http.StatusOK, &controllerhelper.Application{Element: controllerhelper.ApplicationItem{
+				ID: appID, State: "live", AccountID: accountID, ProductID: productID, PlanID: planID, Name: "My App",
+			}})
+		}
+
+		// the live application is neither deleted nor created again
+		t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		return newTestJSONResponse(t, http.StatusNotFound, map[string]string{})
+	})
+
+	adminPortal, err := threescaleapi.NewAdminPortal("https", "www.example.com", 443)
+	if err != nil {
+		t.Fatal(err)
+	}
+	portaClient := threescaleapi.NewThreeScale(adminPortal, "12345", httpClient)
+	restClient, err := controllerhelper.NewThreescaleRESTClient("https://www.example.com", "12345", httpClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reconciler := NewApplicationThreescaleReconciler(newTestBaseReconciler(t, applicationCR), applicationCR, developerAccountCR, productCR,
+		portaClient, restClient, "https://www.example.com", logrtesting.NullLogger{})
+	_, err = reconciler.Reconcile()
+	if !helper.IsInvalidSpecError(err) {
+		t.Fatalf("invalid spec error expected, got %v", err)
+	}
+}
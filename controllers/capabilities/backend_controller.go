@@ -20,10 +20,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
@@ -34,9 +40,18 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
+const (
+	// backendFinalizer removes the 3scale backend when the custom resource is deleted
+	backendFinalizer = "backend.capabilities.3scale.net/finalizer"
+)
+
 // BackendReconciler reconciles a Backend object
 type BackendReconciler struct {
 	*reconcilers.BaseReconciler
+
+	// ResyncPeriod is the period to synchronize again backends that did not change.
+	// 3scale backend objects modified outside the operator are corrected. Zero disables the periodic resync.
+	ResyncPeriod time.Duration
 }
 
 // blank assignment to verify that BackendReconciler implements reconcile.Reconciler
@@ -74,10 +89,19 @@ func (r *BackendReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		reqLogger.V(1).Info(string(jsonData))
 	}
 
-	// Ignore deleted Backends, this can happen when foregroundDeletion is enabled
-	// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/#foreground-cascading-deletion
-	if backend.DeletionTimestamp != nil {
-		return ctrl.Result{}, nil
+	if backend.GetDeletionTimestamp() != nil {
+		return reconcileFinalizer(r.BaseReconciler, backend, backendFinalizer, func() error {
+			return r.removeBackend(backend)
+		}, reqLogger)
+	}
+
+	finalizerAdded, err := ensureFinalizer(r.BaseReconciler, backend, backendFinalizer)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if finalizerAdded {
+		reqLogger.Info("finalizer added. Requeueing.")
+		return ctrl.Result{Requeue: true}, nil
 	}
 
 	if backend.SetDefaults(reqLogger) {
@@ -118,7 +142,7 @@ func (r *BackendReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	}
 
 	reqLogger.Info("END", "error", reconcileErr)
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.ResyncPeriod}, nil
 }
 
 func (r *BackendReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -136,30 +160,129 @@ func (r *BackendReconciler) reconcile(backendResource *capabilitiesv1beta1.Backe
 		return statusReconciler, err
 	}
 
-	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), backendResource.Namespace, backendResource.Spec.ProviderAccountRef, logger)
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), backendResource.Namespace, backendResource.Spec.ProviderAccountRef, backendResource.Spec.ProviderAccountNamespace, backendResource.Spec.TenantRef, logger)
 	if err != nil {
 		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backendResource, nil, "", err)
 		return statusReconciler, err
 	}
 
-	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	threescaleAPIClient, changeRecorder, err := controllerhelper.PortaClientWithChangeRecorder(providerAccount)
 	if err != nil {
 		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backendResource, nil, providerAccount.AdminURLStr, err)
 		return statusReconciler, err
 	}
 
+	// In dry-run mode, changes to 3scale objects are computed but not applied
+	var dryRun *controllerhelper.DryRunRecorder
+	if capabilitiesv1beta1.IsDryRun(backendResource) {
+		dryRun = controllerhelper.NewDryRunRecorder()
+	}
+
+	// Changes to 3scale objects of a spec already synchronized are reported as drift
+	var drift *driftDetector
+	if driftCheckRequired(backendResource.Generation, backendResource.Status.ObservedGeneration, backendResource.Status.Conditions, capabilitiesv1beta1.BackendSyncedConditionType) {
+		drift = newDriftDetector(changeRecorder)
+	}
+
 	backendRemoteIndex, err := controllerhelper.NewBackendAPIRemoteIndex(threescaleAPIClient, logger)
 	if err != nil {
 		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backendResource, nil, providerAccount.AdminURLStr, err)
 		return statusReconciler, err
 	}
 
-	reconciler := NewThreescaleReconciler(r.BaseReconciler, backendResource, threescaleAPIClient, backendRemoteIndex, providerAccount)
+	reconciler := NewThreescaleReconciler(r.BaseReconciler, backendResource, threescaleAPIClient, backendRemoteIndex, providerAccount, drift, dryRun)
 	backendAPIEntity, err := reconciler.Reconcile()
+	if err == nil {
+		err = reconcileDryRunConfigMap(r.BaseReconciler, backendResource, "backend", dryRun)
+	}
 	statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backendResource, backendAPIEntity, providerAccount.AdminURLStr, err)
+	statusReconciler.driftedTasks = drift.Tasks()
+	statusReconciler.dryRun = dryRun
+	if len(statusReconciler.driftedTasks) > 0 {
+		logger.Info("3scale backend drift corrected", "tasks", statusReconciler.driftedTasks)
+		r.EventRecorder().Eventf(backendResource, corev1.EventTypeWarning, DriftedReason, "3scale backend modified outside the operator corrected by tasks: %s", strings.Join(statusReconciler.driftedTasks, ", "))
+	}
 	return statusReconciler, err
 }
 
+// removeBackend removes the 3scale backend and reports the progress in the backend status
+func (r *BackendReconciler) removeBackend(backend *capabilitiesv1beta1.Backend) error {
+	statusReconciler, deletionErr := r.removeBackendFrom3scale(backend)
+	_, statusUpdateErr := statusReconciler.Reconcile()
+	if statusUpdateErr != nil {
+		if deletionErr != nil {
+			return fmt.Errorf("Failed to remove backend from 3scale: %v. Failed to update backend status: %w", deletionErr, statusUpdateErr)
+		}
+
+		return fmt.Errorf("Failed to update backend status: %w", statusUpdateErr)
+	}
+
+	return deletionErr
+}
+
+func (r *BackendReconciler) removeBackendFrom3scale(backend *capabilitiesv1beta1.Backend) (*BackendStatusReconciler, error) {
+	logger := r.Logger().WithValues("backend", backend.Name)
+
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), backend.Namespace, backend.Spec.ProviderAccountRef, backend.Spec.ProviderAccountNamespace, backend.Spec.TenantRef, logger)
+	if err != nil {
+		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backend, nil, "", err)
+		return statusReconciler, err
+	}
+
+	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	if err != nil {
+		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backend, nil, providerAccount.AdminURLStr, err)
+		return statusReconciler, err
+	}
+
+	backendRemoteIndex, err := controllerhelper.NewBackendAPIRemoteIndex(threescaleAPIClient, logger)
+	if err != nil {
+		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backend, nil, providerAccount.AdminURLStr, err)
+		return statusReconciler, err
+	}
+
+	backendAPIEntity := findRemoteBackend(backend, backendRemoteIndex)
+
+	// Products managed by custom resources block the deletion
+	// even when the backend usage has not been created in 3scale yet
+	productUsers, err := backendProductResourceUsers(backend, r.Client(), providerAccount.AdminURLStr, logger)
+	if err != nil {
+		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backend, backendAPIEntity, providerAccount.AdminURLStr, err)
+		return statusReconciler, err
+	}
+
+	if backendAPIEntity != nil {
+		remoteProductUsers, err := backendRemoteProductUsers(backendAPIEntity.ID(), threescaleAPIClient)
+		if err != nil {
+			statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backend, backendAPIEntity, providerAccount.AdminURLStr, err)
+			return statusReconciler, err
+		}
+		productUsers = append(productUsers, remoteProductUsers...)
+	}
+
+	if len(productUsers) > 0 {
+		err = &helper.WaitError{
+			Err: fmt.Errorf("backend [%s] is used by products: %s", backend.Spec.SystemName, strings.Join(productUsers, ", ")),
+		}
+		statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backend, backendAPIEntity, providerAccount.AdminURLStr, err)
+		return statusReconciler, err
+	}
+
+	if backendAPIEntity != nil {
+		// 3scale removes backend's metrics, methods and mapping rules
+		err = threescaleAPIClient.DeleteBackendApi(backendAPIEntity.ID())
+		if err != nil && !threescaleapi.IsNotFound(err) {
+			err = fmt.Errorf("Error deleting backend [%s;%d]: %w", backend.Spec.SystemName, backendAPIEntity.ID(), err)
+			statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backend, backendAPIEntity, providerAccount.AdminURLStr, err)
+			return statusReconciler, err
+		}
+		logger.Info("backend removed from 3scale", "ID", backendAPIEntity.ID())
+	}
+
+	statusReconciler := NewBackendStatusReconciler(r.BaseReconciler, backend, backendAPIEntity, providerAccount.AdminURLStr, nil)
+	return statusReconciler, nil
+}
+
 func (r *BackendReconciler) validateSpec(backendResource *capabilitiesv1beta1.Backend) error {
 	errors := field.ErrorList{}
 	// internal validation
@@ -174,3 +297,81 @@ func (r *BackendReconciler) validateSpec(backendResource *capabilitiesv1beta1.Ba
 		FieldErrorList: errors,
 	}
 }
+
+// findRemoteBackend looks up the 3scale backend by status ID.
+// Backends without status ID have never been synchronized by the custom resource,
+// hence, they are not managed and not looked up by system name. Returns nil when not found
+func findRemoteBackend(backend *capabilitiesv1beta1.Backend, backendRemoteIndex *controllerhelper.BackendAPIRemoteIndex) *controllerhelper.BackendAPIEntity {
+	if backend.Status.ID == nil {
+		return nil
+	}
+
+	if backendAPIEntity, ok := backendRemoteIndex.FindByID(*backend.Status.ID); ok {
+		return backendAPIEntity
+	}
+
+	return nil
+}
+
+// backendProductResourceUsers returns the product custom resources from the same
+// 3scale provider account having the backend in the backend usage list
+func backendProductResourceUsers(backend *capabilitiesv1beta1.Backend, cl client.Client, providerAccountURLStr string, logger logr.Logger) ([]string, error) {
+	productList := &capabilitiesv1beta1.ProductList{}
+	err := cl.List(context.TODO(), productList, client.InNamespace(backend.Namespace))
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing product resources: %w", err)
+	}
+
+	users := make([]string, 0)
+	for idx := range productList.Items {
+		product := &productList.Items[idx]
+		if _, ok := product.Spec.BackendUsages[backend.Spec.SystemName]; !ok {
+			continue
+		}
+
+		productProviderAccount, err := controllerhelper.LookupProviderAccount(cl, product.Namespace, product.Spec.ProviderAccountRef, product.Spec.ProviderAccountNamespace, product.Spec.TenantRef, logger)
+		if controllerhelper.IsTenantNotFoundError(err) {
+			// The backend provider account has been found, hence the product belongs to another tenant
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed looking up provider account of product [%s]: %w", product.Name, err)
+		}
+
+		if productProviderAccount.AdminURLStr != providerAccountURLStr {
+			continue
+		}
+
+		users = append(users, fmt.Sprintf("%s (resource)", product.Name))
+	}
+
+	sort.Strings(users)
+	return users, nil
+}
+
+// backendRemoteProductUsers returns the 3scale products having a backend usage for the given backend
+func backendRemoteProductUsers(backendID int64, threescaleAPIClient *threescaleapi.ThreeScaleClient) ([]string, error) {
+	productList, err := threescaleAPIClient.ListProducts()
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing 3scale products: %w", err)
+	}
+
+	users := make([]string, 0)
+	for idx := range productList.Products {
+		product := productList.Products[idx].Element
+		backendUsages, err := threescaleAPIClient.ListBackendapiUsages(product.ID)
+		if err != nil {
+			return nil, fmt.Errorf("Failed listing backend usages of 3scale product [%s;%d]: %w", product.SystemName, product.ID, err)
+		}
+
+		for _, backendUsage := range backendUsages {
+			if backendUsage.Element.BackendAPIID == backendID {
+				users = append(users, fmt.Sprintf("%s (3scale)", product.SystemName))
+				break
+			}
+		}
+	}
+
+	sort.Strings(users)
+	return users, nil
+}
@@ -0,0 +1,366 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/common"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+	logrtesting "github.com/go-logr/logr/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// newTestThreescaleServer serves the 3scale admin API with the given handler and returns
+// the default provider account secret pointing to it
+func newTestThreescaleServer(t *testing.T, ns string, handler http.HandlerFunc) (*httptest.Server, *corev1.Secret) {
+	t.Helper()
+	server := httptest.NewTLSServer(handler)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "threescale-provider-account", Namespace: ns},
+		Data: map[string][]byte{
+			"adminURL": []byte(server.URL),
+			"token":    []byte("12345"),
+		},
+	}
+
+	return server, secret
+}
+
+func writeTestJSON(t *testing.T, w http.ResponseWriter, obj interface{}) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		t.Error(err)
+	}
+}
+
+func newTestDeletedBackend() *capabilitiesv1beta1.Backend {
+	var backendID int64 = 3
+	now := metav1.Now()
+
+	return &capabilitiesv1beta1.Backend{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "mybackend",
+			Namespace:         "myns",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{backendFinalizer},
+		},
+		Spec: capabilitiesv1beta1.BackendSpec{
+			Name:           "My Backend",
+			SystemName:     "mybackend",
+			PrivateBaseURL: "https://api.example.com",
+		},
+		Status: capabilitiesv1beta1.BackendStatus{ID: &backendID},
+	}
+}
+
+// testBackendDeletionHandler serves a 3scale account with backend 3 and product 5.
+// Product 5 uses backend 3 when used is true.
+func testBackendDeletionHandler(t *testing.T, used bool, deleted *bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodGet && req.URL.Path == "/admin/api/backend_apis.json":
+			writeTestJSON(t, w, &threescaleapi.BackendApiList{
+				Backends: []threescaleapi.BackendApi{
+					{Element: threescaleapi.BackendApiItem{ID: 3, SystemName: "mybackend"}},
+				},
+			})
+		case req.Method == http.MethodGet && req.URL.Path == "/admin/api/services.json":
+			writeTestJSON(t, w, &threescaleapi.ProductList{
+				Products: []threescaleapi.Product{
+					{Element: threescaleapi.ProductItem{ID: 5, SystemName: "myproduct"}},
+				},
+			})
+		case req.Method == http.MethodGet && req.URL.Path == "/admin/api/services/5/backend_usages.json":
+			backendUsages := threescaleapi.BackendAPIUsageList{}
+			if used {
+				backendUsages = append(backendUsages, threescaleapi.BackendAPIUsage{
+					Element: threescaleapi.BackendAPIUsageItem{ID: 7, Path: "/", ProductID: 5, BackendAPIID: 3},
+				})
+			}
+			writeTestJSON(t, w, backendUsages)
+		case req.Method == http.MethodDelete && req.URL.Path == "/admin/api/backend_apis/3.json":
+			*deleted = true
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func testBackendReconcile(t *testing.T, r *BackendReconciler, backend *capabilitiesv1beta1.Backend) (reconcile.Result, *capabilitiesv1beta1.Backend) {
+	t.Helper()
+	key := types.NamespacedName{Name: backend.Name, Namespace: backend.Namespace}
+	result, err := r.Reconcile(reconcile.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reconciled := &capabilitiesv1beta1.Backend{}
+	err = r.Client().Get(context.TODO(), key, reconciled)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return result, reconciled
+}
+
+func TestBackendReconcilerAddsFinalizer(t *testing.T) {
+	backend := newTestDeletedBackend()
+	backend.DeletionTimestamp = nil
+	backend.Finalizers = nil
+
+	r := &BackendReconciler{BaseReconciler: newTestBaseReconciler(t, backend)}
+	_, reconciled := testBackendReconcile(t, r, backend)
+	if !controllerutil.ContainsFinalizer(reconciled, backendFinalizer) {
+		t.Fatalf("finalizer not added: %v", reconciled.Finalizers)
+	}
+}
+
+func TestBackendReconcilerDeletion(t *testing.T) {
+	deleted := false
+	server, secret := newTestThreescaleServer(t, "myns", testBackendDeletionHandler(t, false, &deleted))
+	defer server.Close()
+
+	backend := newTestDeletedBackend()
+	r := &BackendReconciler{BaseReconciler: newTestBaseReconciler(t, backend, secret)}
+	_, reconciled := testBackendReconcile(t, r, backend)
+	if !deleted {
+		t.Fatal("3scale backend not deleted")
+	}
+	if controllerutil.ContainsFinalizer(reconciled, backendFinalizer) {
+		t.Fatalf("finalizer not removed: %v", reconciled.Finalizers)
+	}
+}
+
+func TestBackendReconcilerDeletionOrphan(t *testing.T) {
+	deleted := false
+	server, secret := newTestThreescaleServer(t, "myns", testBackendDeletionHandler(t, false, &deleted))
+	defer server.Close()
+
+	backend := newTestDeletedBackend()
+	backend.Annotations = map[string]string{capabilitiesv1beta1.DeletionPolicyAnnotation: capabilitiesv1beta1.DeletionPolicyOrphan}
+	r := &BackendReconciler{BaseReconciler: newTestBaseReconciler(t, backend, secret)}
+	_, reconciled := testBackendReconcile(t, r, backend)
+	if deleted {
+		t.Fatal("orphan 3scale backend deleted")
+	}
+	if controllerutil.ContainsFinalizer(reconciled, backendFinalizer) {
+		t.Fatalf("finalizer not removed: %v", reconciled.Finalizers)
+	}
+}
+
+func TestBackendReconcilerDeletionBlocked(t *testing.T) {
+	cases := []struct {
+		name     string
+		used     bool
+		products []*capabilitiesv1beta1.Product
+	}{
+		{"used by 3scale product", true, nil},
+		{"used by product resource", false, []*capabilitiesv1beta1.Product{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "myproduct", Namespace: "myns"},
+				Spec: capabilitiesv1beta1.ProductSpec{
+					Name: "My Product",
+					BackendUsages: map[string]capabilitiesv1beta1.BackendUsageSpec{
+						"mybackend": {Path: "/"},
+					},
+				},
+			},
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			deleted := false
+			server, secret := newTestThreescaleServer(subT, "myns", testBackendDeletionHandler(subT, tc.used, &deleted))
+			defer server.Close()
+
+			backend := newTestDeletedBackend()
+			objs := []runtime.Object{backend, secret}
+			for _, product := range tc.products {
+				objs = append(objs, product)
+			}
+
+			r := &BackendReconciler{BaseReconciler: newTestBaseReconciler(subT, objs...)}
+			result, reconciled := testBackendReconcile(subT, r, backend)
+			if deleted {
+				subT.Fatal("3scale backend in use deleted")
+			}
+			if !result.Requeue && result.RequeueAfter == 0 {
+				subT.Fatal("blocked deletion not retried")
+			}
+			if !controllerutil.ContainsFinalizer(reconciled, backendFinalizer) {
+				subT.Fatalf("finalizer removed: %v", reconciled.Finalizers)
+			}
+			if !reconciled.Status.Conditions.IsTrueFor(capabilitiesv1beta1.BackendDeletionBlockedConditionType) {
+				subT.Fatalf("deletion blocked condition expected: %v", reconciled.Status.Conditions)
+			}
+		})
+	}
+}
+
+func TestFindRemoteBackend(t *testing.T) {
+	var backendID int64 = 3
+
+	httpClient := newTestHTTPClient(func(req *http.Request) *http.Response {
+		if req.Method == http.MethodGet && req.URL.Path == "/admin/api/backend_apis.json" {
+			return newTestJSONResponse(t, http.StatusOK, &threescaleapi.BackendApiList{
+				Backends: []threescaleapi.BackendApi{
+					{Element: threescaleapi.BackendApiItem{ID: backendID, SystemName: "mybackend"}},
+				},
+			})
+		}
+
+		t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		return newTestJSONResponse(t, http.StatusNotFound, map[string]string{})
+	})
+
+	adminPortal, err := threescaleapi.NewAdminPortal("https", "www.example.com", 443)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := threescaleapi.NewThreeScale(adminPortal, "12345", httpClient)
+
+	backendRemoteIndex, err := controllerhelper.NewBackendAPIRemoteIndex(client, logrtesting.NullLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend := &capabilitiesv1beta1.Backend{
+		Spec: capabilitiesv1beta1.BackendSpec{SystemName: "mybackend"},
+	}
+
+	// backends are not looked up by system name
+	if backendAPIEntity := findRemoteBackend(backend, backendRemoteIndex); backendAPIEntity != nil {
+		t.Fatalf("backend not synchronized by the custom resource found: %d", backendAPIEntity.ID())
+	}
+
+	backend.Status.ID = &backendID
+	if backendAPIEntity := findRemoteBackend(backend, backendRemoteIndex); backendAPIEntity == nil || backendAPIEntity.ID() != backendID {
+		t.Fatalf("backend [%d] expected", backendID)
+	}
+}
+
+// backendDriftServer fakes the 3scale backend 3 already synchronized with the custom resource.
+// The backend name can be changed outside the operator.
+type backendDriftServer struct {
+	t       *testing.T
+	mu      sync.Mutex
+	name    string
+	updates int
+}
+
+func (s *backendDriftServer) SetName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.name = name
+}
+
+func (s *backendDriftServer) backendItem() threescaleapi.BackendApiItem {
+	return threescaleapi.BackendApiItem{ID: 3, SystemName: "mybackend", Name: s.name, PrivateEndpoint: "https://api.example.com"}
+}
+
+func (s *backendDriftServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case req.Method == http.MethodGet && req.URL.Path == "/admin/api/backend_apis.json":
+		writeTestJSON(s.t, w, &threescaleapi.BackendApiList{
+			Backends: []threescaleapi.BackendApi{{Element: s.backendItem()}},
+		})
+	case req.Method == http.MethodPut && req.URL.Path == "/admin/api/backend_apis/3.json":
+		if err := req.ParseForm(); err != nil {
+			s.t.Error(err)
+		}
+		s.name = req.PostForm.Get("name")
+		s.updates++
+		writeTestJSON(s.t, w, &threescaleapi.BackendApi{Element: s.backendItem()})
+	case req.Method == http.MethodGet && req.URL.Path == "/admin/api/backend_apis/3/metrics.json":
+		writeTestJSON(s.t, w, &threescaleapi.MetricJSONList{
+			Metrics: []threescaleapi.MetricJSON{
+				{Element: threescaleapi.MetricItem{ID: 1, SystemName: "hits", Name: "Hits", Unit: "hit", Description: "Number of API hits"}},
+			},
+		})
+	case req.Method == http.MethodGet && req.URL.Path == "/admin/api/backend_apis/3/metrics/1/methods.json":
+		writeTestJSON(s.t, w, &threescaleapi.MethodList{})
+	case req.Method == http.MethodGet && req.URL.Path == "/admin/api/backend_apis/3/mapping_rules.json":
+		writeTestJSON(s.t, w, &threescaleapi.MappingRuleJSONList{})
+	default:
+		s.t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestBackendReconcilerDrift(t *testing.T) {
+	backendServer := &backendDriftServer{t: t, name: "My Backend"}
+	server, secret := newTestThreescaleServer(t, "myns", backendServer.ServeHTTP)
+	defer server.Close()
+
+	backend := newTestDeletedBackend()
+	backend.DeletionTimestamp = nil
+	backend.Generation = 1
+	backend.Spec.Metrics = map[string]capabilitiesv1beta1.MetricSpec{
+		"hits": {Name: "Hits", Unit: "hit", Description: "Number of API hits"},
+	}
+	backend.Status.ObservedGeneration = 1
+	backend.Status.Conditions = common.Conditions{
+		{Type: capabilitiesv1beta1.BackendSyncedConditionType, Status: corev1.ConditionTrue},
+	}
+
+	r := &BackendReconciler{BaseReconciler: newTestBaseReconciler(t, backend, secret), ResyncPeriod: 10 * time.Minute}
+
+	// 3scale backend unchanged
+	result, reconciled := testBackendReconcile(t, r, backend)
+	if result.RequeueAfter != r.ResyncPeriod {
+		t.Fatalf("resync expected after %s, got %v", r.ResyncPeriod, result)
+	}
+	if !reconciled.Status.Conditions.IsFalseFor(capabilitiesv1beta1.BackendDriftedConditionType) {
+		t.Fatalf("drifted condition not expected: %v", reconciled.Status.Conditions)
+	}
+	if backendServer.updates != 0 {
+		t.Fatalf("unexpected backend updates %d", backendServer.updates)
+	}
+
+	// 3scale backend changed from the admin portal
+	backendServer.SetName("Changed Backend")
+	result, reconciled = testBackendReconcile(t, r, backend)
+	if result.RequeueAfter != r.ResyncPeriod {
+		t.Fatalf("resync expected after %s, got %v", r.ResyncPeriod, result)
+	}
+	if backendServer.name != "My Backend" {
+		t.Fatalf("drift not corrected: backend name %s", backendServer.name)
+	}
+	driftedCondition := reconciled.Status.Conditions.GetCondition(capabilitiesv1beta1.BackendDriftedConditionType)
+	if driftedCondition == nil || driftedCondition.Status != corev1.ConditionTrue || !strings.Contains(driftedCondition.Message, "SyncBackend") {
+		t.Fatalf("drifted condition expected: %v", reconciled.Status.Conditions)
+	}
+	recorder := r.EventRecorder().(*record.FakeRecorder)
+	if event := <-recorder.Events; !strings.Contains(event, DriftedReason) {
+		t.Fatalf("drifted event expected, got %s", event)
+	}
+
+	// drift is cleared on the next synchronization without changes
+	_, reconciled = testBackendReconcile(t, r, backend)
+	if !reconciled.Status.Conditions.IsFalseFor(capabilitiesv1beta1.BackendDriftedConditionType) {
+		t.Fatalf("drifted condition not cleared: %v", reconciled.Status.Conditions)
+	}
+}
@@ -21,7 +21,11 @@ type BackendStatusReconciler struct {
 	backendAPIEntity    *controllerhelper.BackendAPIEntity
 	providerAccountHost string
 	syncError           error
-	logger              logr.Logger
+	// driftedTasks are the tasks that corrected 3scale objects modified outside the operator
+	driftedTasks []string
+	// dryRun holds the changes computed in dry-run mode
+	dryRun *controllerhelper.DryRunRecorder
+	logger logr.Logger
 }
 
 func NewBackendStatusReconciler(b *reconcilers.BaseReconciler, backendResource *capabilitiesv1beta1.Backend, backendAPIEntity *controllerhelper.BackendAPIEntity, providerAccountHost string, syncError error) *BackendStatusReconciler {
@@ -86,6 +90,10 @@ func (s *BackendStatusReconciler) calculateStatus() *capabilitiesv1beta1.Backend
 	newStatus.Conditions.SetCondition(s.syncCondition())
 	newStatus.Conditions.SetCondition(s.invalidCondition())
 	newStatus.Conditions.SetCondition(s.failedCondition())
+	newStatus.Conditions.SetCondition(s.deletingCondition())
+	newStatus.Conditions.SetCondition(driftedCondition(capabilitiesv1beta1.BackendDriftedConditionType, s.driftedTasks))
+	newStatus.Conditions.SetCondition(dryRunCondition(capabilitiesv1beta1.BackendDryRunConditionType, s.dryRun, dryRunConfigMapName("backend", s.backendResource.Name)))
+	newStatus.Conditions.SetCondition(s.deletionBlockedCondition())
 
 	return newStatus
 }
@@ -96,7 +104,8 @@ func (s *BackendStatusReconciler) syncCondition() common.Condition {
 		Status: corev1.ConditionFalse,
 	}
 
-	if s.syncError == nil {
+	// Changes are not applied in dry-run mode
+	if s.syncError == nil && s.dryRun == nil {
 		condition.Status = corev1.ConditionTrue
 	}
 
@@ -131,3 +140,31 @@ func (s *BackendStatusReconciler) failedCondition() common.Condition {
 
 	return condition
 }
+
+func (s *BackendStatusReconciler) deletingCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.BackendDeletingConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if s.backendResource.GetDeletionTimestamp() != nil {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = "3scale backend is being removed"
+	}
+
+	return condition
+}
+
+func (s *BackendStatusReconciler) deletionBlockedCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.BackendDeletionBlockedConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if s.backendResource.GetDeletionTimestamp() != nil && helper.IsWaitError(s.syncError) {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = s.syncError.Error()
+	}
+
+	return condition
+}
@@ -21,6 +21,8 @@ type BackendThreescaleReconciler struct {
 	backendRemoteIndex  *controllerhelper.BackendAPIRemoteIndex
 	threescaleAPIClient *threescaleapi.ThreeScaleClient
 	providerAccount     *controllerhelper.ProviderAccount
+	drift               *driftDetector
+	dryRun              *controllerhelper.DryRunRecorder
 	logger              logr.Logger
 }
 
@@ -29,6 +31,8 @@ func NewThreescaleReconciler(b *reconcilers.BaseReconciler,
 	threescaleAPIClient *threescaleapi.ThreeScaleClient,
 	backendRemoteIndex *controllerhelper.BackendAPIRemoteIndex,
 	providerAccount *controllerhelper.ProviderAccount,
+	drift *driftDetector,
+	dryRun *controllerhelper.DryRunRecorder,
 ) *BackendThreescaleReconciler {
 
 	return &BackendThreescaleReconciler{
@@ -37,26 +41,33 @@ func NewThreescaleReconciler(b *reconcilers.BaseReconciler,
 		backendRemoteIndex:  backendRemoteIndex,
 		threescaleAPIClient: threescaleAPIClient,
 		providerAccount:     providerAccount,
+		drift:               drift,
+		dryRun:              dryRun,
 		logger:              b.Logger().WithValues("3scale Reconciler", backendResource.Name),
 	}
 }
 
 func (t *BackendThreescaleReconciler) Reconcile() (*controllerhelper.BackendAPIEntity, error) {
 	taskRunner := helper.NewTaskRunner(nil, t.logger)
-	taskRunner.AddTask("SyncBackend", t.syncBackend)
+	taskRunner.AddTask("SyncBackend", t.trackTask("SyncBackend", t.syncBackend))
 	// First methods and metrics, then mapping rules.
 	// Mapping rules reference methods and metrics.
 	// When a method/metric is deleted,
 	// any orphan mapping rule will be deleted automatically by 3scale
-	taskRunner.AddTask("SyncMethods", t.syncMethods)
-	taskRunner.AddTask("SyncMetrics", t.syncMetrics)
-	taskRunner.AddTask("SyncMappingRules", t.syncMappingRules)
+	taskRunner.AddTask("SyncMethods", t.trackTask("SyncMethods", t.syncMethods))
+	taskRunner.AddTask("SyncMetrics", t.trackTask("SyncMetrics", t.syncMetrics))
+	taskRunner.AddTask("SyncMappingRules", t.trackTask("SyncMappingRules", t.syncMappingRules))
 
 	err := taskRunner.Run()
 	if err != nil {
 		return nil, err
 	}
 
+	if t.backendAPIEntity.IsPlanned() {
+		// The backend does not exist yet, there is no backend to report in the status
+		return nil, nil
+	}
+
 	return t.backendAPIEntity, nil
 }
 
@@ -68,15 +79,19 @@ func (t *BackendThreescaleReconciler) syncBackend(_ interface{}) error {
 
 	backendAPIEntity, exists := t.backendRemoteIndex.FindBySystemName(t.backendResource.Spec.SystemName)
 
-	if !exists {
-		// Create backend using system_name.
-		// it cannot be modified later
-		params := threescaleapi.Params{
-			"system_name":      t.backendResource.Spec.SystemName,
-			"name":             t.backendResource.Spec.Name,
-			"private_endpoint": t.backendResource.Spec.PrivateBaseURL,
-		}
-		backendAPIEntity, err = t.backendRemoteIndex.CreateBackendAPI(params)
+	if !exists && t.dryRun != nil {
+		t.dryRun.Create("backend", t.newBackendParams())
+		// The rest of the changes are computed against an empty backend
+		backendAPIEntity = controllerhelper.NewBackendAPIEntity(&threescaleapi.BackendApi{
+			Element: threescaleapi.BackendApiItem{
+				ID:              controllerhelper.DryRunPlannedID,
+				Name:            t.backendResource.Spec.Name,
+				SystemName:      t.backendResource.Spec.SystemName,
+				PrivateEndpoint: t.backendResource.Spec.PrivateBaseURL,
+			},
+		}, t.threescaleAPIClient, t.logger)
+	} else if !exists {
+		backendAPIEntity, err = t.backendRemoteIndex.CreateBackendAPI(t.newBackendParams())
 		if err != nil {
 			return fmt.Errorf("Error sync backend [%s]: %w", t.backendResource.Spec.SystemName, err)
 		}
@@ -84,6 +99,7 @@ func (t *BackendThreescaleReconciler) syncBackend(_ interface{}) error {
 
 	// Will be used by coming steps
 	t.backendAPIEntity = backendAPIEntity
+	t.backendAPIEntity.SetDryRun(t.dryRun)
 
 	updatedParams := threescaleapi.Params{}
 
@@ -109,6 +125,22 @@ func (t *BackendThreescaleReconciler) syncBackend(_ interface{}) error {
 	return nil
 }
 
+// newBackendParams returns the params to create the 3scale backend
+func (t *BackendThreescaleReconciler) newBackendParams() threescaleapi.Params {
+	// Create backend using system_name.
+	// it cannot be modified later
+	return threescaleapi.Params{
+		"system_name":      t.backendResource.Spec.SystemName,
+		"name":             t.backendResource.Spec.Name,
+		"private_endpoint": t.backendResource.Spec.PrivateBaseURL,
+	}
+}
+
+// trackTask wraps the task to record the task name of the drifted and dry-run changes
+func (t *BackendThreescaleReconciler) trackTask(name string, task func(interface{}) error) func(interface{}) error {
+	return t.dryRun.Track(name, t.drift.Track(name, task))
+}
+
 func (t *BackendThreescaleReconciler) syncMethods(_ interface{}) error {
 	desiredKeys := make([]string, 0, len(t.backendResource.Spec.Methods))
 	for systemName := range t.backendResource.Spec.Methods {
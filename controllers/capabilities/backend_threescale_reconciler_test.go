@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+	logrtesting "github.com/go-logr/logr/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBackendThreescaleReconcilerDryRunNewBackend(t *testing.T) {
+	httpClient := newTestHTTPClient(func(req *http.Request) *http.Response {
+		if req.Method == http.MethodGet && req.URL.Path == "/admin/api/backend_apis.json" {
+			return newTestJSONResponse(t, http.StatusOK, &threescaleapi.BackendApiList{})
+		}
+
+		// the backend does not exist, nothing else is read and nothing is modified
+		t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		return newTestJSONResponse(t, http.StatusNotFound, map[string]string{})
+	})
+
+	adminPortal, err := threescaleapi.NewAdminPortal("https", "www.example.com", 443)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := threescaleapi.NewThreeScale(adminPortal, "12345", httpClient)
+
+	backend := &capabilitiesv1beta1.Backend{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "mybackend",
+			Namespace:   "myns",
+			Annotations: map[string]string{capabilitiesv1beta1.DryRunAnnotation: "true"},
+		},
+		Spec: capabilitiesv1beta1.BackendSpec{
+			Name:           "My Backend",
+			SystemName:     "mybackend",
+			PrivateBaseURL: "https://backend.example.com",
+			Metrics: map[string]capabilitiesv1beta1.MetricSpec{
+				"metric01": {Name: "Metric 01", Unit: "hit"},
+			},
+			MappingRules: []capabilitiesv1beta1.MappingRuleSpec{
+				{HTTPMethod: "GET", Pattern: "/pets", MetricMethodRef: "metric01", Increment: 1},
+			},
+		},
+	}
+	backend.SetDefaults(logrtesting.NullLogger{})
+
+	baseReconciler := newTestBaseReconciler(t, backend)
+	backendRemoteIndex, err := controllerhelper.NewBackendAPIRemoteIndex(client, logrtesting.NullLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	providerAccount := &controllerhelper.ProviderAccount{AdminURLStr: "https://www.example.com", Token: "12345"}
+
+	dryRun := controllerhelper.NewDryRunRecorder()
+	reconciler := NewThreescaleReconciler(baseReconciler, backend, client, backendRemoteIndex, providerAccount, nil, dryRun)
+	backendEntity, err := reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backendEntity != nil {
+		t.Fatalf("planned backend reported: %d", backendEntity.ID())
+	}
+
+	// the whole backend is planned, not only its creation
+	planned := map[string]bool{}
+	for _, change := range dryRun.Changes() {
+		if change.Action == controllerhelper.DryRunActionCreate {
+			planned[change.Object] = true
+		}
+	}
+	for _, object := range []string{"backend", "metric", "mapping rule"} {
+		if !planned[object] {
+			t.Errorf("%s creation not planned: %v", object, dryRun.Changes())
+		}
+	}
+}
@@ -20,9 +20,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -34,9 +37,18 @@ import (
 	"github.com/go-logr/logr"
 )
 
+const (
+	// custompolicydefinitionFinalizer removes the 3scale custom policy definition when the custom resource is deleted
+	custompolicydefinitionFinalizer = "custompolicydefinition.capabilities.3scale.net/finalizer"
+)
+
 // CustomPolicyDefinitionReconciler reconciles a CustomPolicyDefinition object
 type CustomPolicyDefinitionReconciler struct {
 	*reconcilers.BaseReconciler
+
+	// ResyncPeriod is the period to synchronize again custom policy definitions that did not change.
+	// Zero disables the periodic resync.
+	ResyncPeriod time.Duration
 }
 
 // blank assignment to verify that PolicyReconciler implements reconcile.Reconciler
@@ -44,6 +56,7 @@ var _ reconcile.Reconciler = &CustomPolicyDefinitionReconciler{}
 
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=custompolicydefinitions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=custompolicydefinitions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=custompolicydefinitions/finalizers,verbs=get;list;watch;create;update;patch;delete
 
 func (r *CustomPolicyDefinitionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	_ = context.Background()
@@ -73,10 +86,19 @@ func (r *CustomPolicyDefinitionReconciler) Reconcile(req ctrl.Request) (ctrl.Res
 		reqLogger.V(1).Info(string(jsonData))
 	}
 
-	// Ignore deleted resource, this can happen when foregroundDeletion is enabled
-	// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/#foreground-cascading-deletion
-	if customPolicyDefinitionCR.DeletionTimestamp != nil {
-		return ctrl.Result{}, nil
+	if customPolicyDefinitionCR.GetDeletionTimestamp() != nil {
+		return reconcileFinalizer(r.BaseReconciler, customPolicyDefinitionCR, custompolicydefinitionFinalizer, func() error {
+			return r.removeCustomPolicyDefinitionFrom3scale(customPolicyDefinitionCR, reqLogger)
+		}, reqLogger)
+	}
+
+	finalizerAdded, err := ensureFinalizer(r.BaseReconciler, customPolicyDefinitionCR, custompolicydefinitionFinalizer)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if finalizerAdded {
+		reqLogger.Info("finalizer added. Requeueing.")
+		return ctrl.Result{Requeue: true}, nil
 	}
 
 	statusReconciler, reconcileErr := r.reconcileSpec(customPolicyDefinitionCR, reqLogger)
@@ -106,11 +128,17 @@ func (r *CustomPolicyDefinitionReconciler) Reconcile(req ctrl.Request) (ctrl.Res
 		return ctrl.Result{}, reconcileErr
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.ResyncPeriod}, nil
 }
 
 func (r *CustomPolicyDefinitionReconciler) reconcileSpec(customPolicyDefinitionCR *capabilitiesv1beta1.CustomPolicyDefinition, logger logr.Logger) (*CustomPolicyDefinitionStatusReconciler, error) {
-	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), customPolicyDefinitionCR.Namespace, customPolicyDefinitionCR.Spec.ProviderAccountRef, logger)
+	err := r.validateSpec(customPolicyDefinitionCR)
+	if err != nil {
+		statusReconciler := NewCustomPolicyDefinitionStatusReconciler(r.BaseReconciler, customPolicyDefinitionCR, "", nil, err)
+		return statusReconciler, err
+	}
+
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), customPolicyDefinitionCR.Namespace, customPolicyDefinitionCR.Spec.ProviderAccountRef, customPolicyDefinitionCR.Spec.ProviderAccountNamespace, customPolicyDefinitionCR.Spec.TenantRef, logger)
 	if err != nil {
 		statusReconciler := NewCustomPolicyDefinitionStatusReconciler(r.BaseReconciler, customPolicyDefinitionCR, "", nil, err)
 		return statusReconciler, err
@@ -129,8 +157,48 @@ func (r *CustomPolicyDefinitionReconciler) reconcileSpec(customPolicyDefinitionC
 	return statusReconciler, err
 }
 
+func (r *CustomPolicyDefinitionReconciler) validateSpec(resource *capabilitiesv1beta1.CustomPolicyDefinition) error {
+	errors := field.ErrorList{}
+	errors = append(errors, resource.Validate()...)
+
+	if len(errors) == 0 {
+		return nil
+	}
+
+	return &helper.SpecFieldError{
+		ErrorType:      helper.InvalidError,
+		FieldErrorList: errors,
+	}
+}
+
 func (r *CustomPolicyDefinitionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&capabilitiesv1beta1.CustomPolicyDefinition{}).
 		Complete(r)
 }
+
+// removeCustomPolicyDefinitionFrom3scale removes the 3scale custom policy definition referenced in the status
+func (r *CustomPolicyDefinitionReconciler) removeCustomPolicyDefinitionFrom3scale(customPolicyDefinitionCR *capabilitiesv1beta1.CustomPolicyDefinition, logger logr.Logger) error {
+	if customPolicyDefinitionCR.Status.ID == nil {
+		// 3scale custom policy has not been created
+		return nil
+	}
+
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), customPolicyDefinitionCR.Namespace, customPolicyDefinitionCR.Spec.ProviderAccountRef, customPolicyDefinitionCR.Spec.ProviderAccountNamespace, customPolicyDefinitionCR.Spec.TenantRef, logger)
+	if err != nil {
+		return err
+	}
+
+	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	if err != nil {
+		return err
+	}
+
+	err = threescaleAPIClient.DeleteAPIcastPolicy(*customPolicyDefinitionCR.Status.ID)
+	if err != nil && !threescaleapi.IsNotFound(err) {
+		return fmt.Errorf("Error deleting custom policy [%s;%d]: %w", customPolicyDefinitionCR.Name, *customPolicyDefinitionCR.Status.ID, err)
+	}
+
+	logger.Info("custom policy removed from 3scale", "ID", *customPolicyDefinitionCR.Status.ID)
+	return nil
+}
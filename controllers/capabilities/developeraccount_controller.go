@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
@@ -27,6 +28,7 @@ import (
 	"github.com/3scale/3scale-operator/pkg/reconcilers"
 	"github.com/3scale/3scale-operator/version"
 
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -35,9 +37,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+const (
+	// developeraccountFinalizer removes the 3scale developer account when the custom resource is deleted
+	developeraccountFinalizer = "developeraccount.capabilities.3scale.net/finalizer"
+)
+
 // DeveloperAccountReconciler reconciles a DeveloperAccount object
 type DeveloperAccountReconciler struct {
 	*reconcilers.BaseReconciler
+
+	// ResyncPeriod is the period to synchronize again developer accounts that did not change.
+	// Zero disables the periodic resync.
+	ResyncPeriod time.Duration
 }
 
 // blank assignment to verify that DeveloperAccountReconciler implements reconcile.Reconciler
@@ -45,6 +56,7 @@ var _ reconcile.Reconciler = &DeveloperAccountReconciler{}
 
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=developeraccounts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=developeraccounts/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=developeraccounts/finalizers,verbs=get;list;watch;create;update;patch;delete
 
 func (r *DeveloperAccountReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	_ = context.Background()
@@ -74,10 +86,19 @@ func (r *DeveloperAccountReconciler) Reconcile(req ctrl.Request) (ctrl.Result, e
 		reqLogger.V(1).Info(string(jsonData))
 	}
 
-	// Ignore deleted resource, this can happen when foregroundDeletion is enabled
-	// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/#foreground-cascading-deletion
-	if developerAccountCR.DeletionTimestamp != nil {
-		return ctrl.Result{}, nil
+	if developerAccountCR.GetDeletionTimestamp() != nil {
+		return reconcileFinalizer(r.BaseReconciler, developerAccountCR, developeraccountFinalizer, func() error {
+			return r.removeDeveloperAccountFrom3scale(developerAccountCR, reqLogger)
+		}, reqLogger)
+	}
+
+	finalizerAdded, err := ensureFinalizer(r.BaseReconciler, developerAccountCR, developeraccountFinalizer)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if finalizerAdded {
+		reqLogger.Info("finalizer added. Requeueing.")
+		return ctrl.Result{Requeue: true}, nil
 	}
 
 	statusReconciler, reconcileErr := r.reconcileSpec(developerAccountCR, reqLogger)
@@ -113,7 +134,7 @@ func (r *DeveloperAccountReconciler) Reconcile(req ctrl.Request) (ctrl.Result, e
 		return ctrl.Result{}, reconcileErr
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.ResyncPeriod}, nil
 }
 
 func (r *DeveloperAccountReconciler) reconcileSpec(accountCR *capabilitiesv1beta1.DeveloperAccount, logger logr.Logger) (*DeveloperAccountStatusReconciler, error) {
@@ -123,7 +144,7 @@ func (r *DeveloperAccountReconciler) reconcileSpec(accountCR *capabilitiesv1beta
 		return statusReconciler, err
 	}
 
-	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), accountCR.Namespace, accountCR.Spec.ProviderAccountRef, logger)
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), accountCR.Namespace, accountCR.Spec.ProviderAccountRef, accountCR.Spec.ProviderAccountNamespace, accountCR.Spec.TenantRef, logger)
 	if err != nil {
 		statusReconciler := NewDeveloperAccountStatusReconciler(r.BaseReconciler, accountCR, "", nil, err)
 		return statusReconciler, err
@@ -161,3 +182,30 @@ func (r *DeveloperAccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&capabilitiesv1beta1.DeveloperAccount{}).
 		Complete(r)
 }
+
+// removeDeveloperAccountFrom3scale removes the 3scale developer account referenced in the status
+func (r *DeveloperAccountReconciler) removeDeveloperAccountFrom3scale(developerAccountCR *capabilitiesv1beta1.DeveloperAccount, logger logr.Logger) error {
+	if developerAccountCR.Status.ID == nil {
+		// 3scale developer account has not been created
+		return nil
+	}
+
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), developerAccountCR.Namespace, developerAccountCR.Spec.ProviderAccountRef, developerAccountCR.Spec.ProviderAccountNamespace, developerAccountCR.Spec.TenantRef, logger)
+	if err != nil {
+		return err
+	}
+
+	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	if err != nil {
+		return err
+	}
+
+	// 3scale removes developer account's users and applications
+	err = threescaleAPIClient.DeleteDeveloperAccount(*developerAccountCR.Status.ID)
+	if err != nil && !threescaleapi.IsNotFound(err) {
+		return fmt.Errorf("Error deleting developer account [%s;%d]: %w", developerAccountCR.Name, *developerAccountCR.Status.ID, err)
+	}
+
+	logger.Info("developer account removed from 3scale", "ID", *developerAccountCR.Status.ID)
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
@@ -27,6 +28,7 @@ import (
 	"github.com/3scale/3scale-operator/pkg/reconcilers"
 	"github.com/3scale/3scale-operator/version"
 
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -36,9 +38,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+const (
+	// developeruserFinalizer removes the 3scale developer user when the custom resource is deleted
+	developeruserFinalizer = "developeruser.capabilities.3scale.net/finalizer"
+)
+
 // DeveloperUserReconciler reconciles a DeveloperUser object
 type DeveloperUserReconciler struct {
 	*reconcilers.BaseReconciler
+
+	// ResyncPeriod is the period to synchronize again developer users that did not change.
+	// Zero disables the periodic resync.
+	ResyncPeriod time.Duration
 }
 
 // blank assignment to verify that DeveloperUserReconciler implements reconcile.Reconciler
@@ -46,6 +57,7 @@ var _ reconcile.Reconciler = &DeveloperUserReconciler{}
 
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=developerusers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=developerusers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=developerusers/finalizers,verbs=get;list;watch;create;update;patch;delete
 
 func (r *DeveloperUserReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	reqLogger := r.Logger().WithValues("developeruser", req.NamespacedName)
@@ -74,10 +86,19 @@ func (r *DeveloperUserReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 		reqLogger.V(1).Info(string(jsonData))
 	}
 
-	// Ignore deleted resource, this can happen when foregroundDeletion is enabled
-	// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/#foreground-cascading-deletion
-	if developerUserCR.DeletionTimestamp != nil {
-		return ctrl.Result{}, nil
+	if developerUserCR.GetDeletionTimestamp() != nil {
+		return reconcileFinalizer(r.BaseReconciler, developerUserCR, developeruserFinalizer, func() error {
+			return r.removeDeveloperUserFrom3scale(developerUserCR, reqLogger)
+		}, reqLogger)
+	}
+
+	finalizerAdded, err := ensureFinalizer(r.BaseReconciler, developerUserCR, developeruserFinalizer)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if finalizerAdded {
+		reqLogger.Info("finalizer added. Requeueing.")
+		return ctrl.Result{Requeue: true}, nil
 	}
 
 	statusReconciler, reconcileErr := r.reconcileSpec(developerUserCR, reqLogger)
@@ -113,7 +134,7 @@ func (r *DeveloperUserReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 		return ctrl.Result{}, reconcileErr
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.ResyncPeriod}, nil
 }
 
 func (r *DeveloperUserReconciler) reconcileSpec(userCR *capabilitiesv1beta1.DeveloperUser, logger logr.Logger) (*DeveloperUserStatusReconciler, error) {
@@ -123,7 +144,7 @@ func (r *DeveloperUserReconciler) reconcileSpec(userCR *capabilitiesv1beta1.Deve
 		return statusReconciler, err
 	}
 
-	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), userCR.Namespace, userCR.Spec.ProviderAccountRef, logger)
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), userCR.Namespace, userCR.Spec.ProviderAccountRef, userCR.Spec.ProviderAccountNamespace, userCR.Spec.TenantRef, logger)
 	if err != nil {
 		statusReconciler := NewDeveloperUserStatusReconciler(r.BaseReconciler, userCR, nil, "", nil, err)
 		return statusReconciler, err
@@ -181,7 +202,7 @@ func (r *DeveloperUserReconciler) findParentAccount(userCR *capabilitiesv1beta1.
 	}
 
 	// Check it belongs to the same providerAccount
-	parentProviderAccount, err := controllerhelper.LookupProviderAccount(r.Client(), userCR.Namespace, devAccountCR.Spec.ProviderAccountRef, logger)
+	parentProviderAccount, err := controllerhelper.LookupProviderAccount(r.Client(), userCR.Namespace, devAccountCR.Spec.ProviderAccountRef, devAccountCR.Spec.ProviderAccountNamespace, devAccountCR.Spec.TenantRef, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -213,3 +234,30 @@ func (r *DeveloperUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&capabilitiesv1beta1.DeveloperUser{}).
 		Complete(r)
 }
+
+// removeDeveloperUserFrom3scale removes the 3scale developer user referenced in the status
+func (r *DeveloperUserReconciler) removeDeveloperUserFrom3scale(developerUserCR *capabilitiesv1beta1.DeveloperUser, logger logr.Logger) error {
+	if developerUserCR.Status.ID == nil || developerUserCR.Status.AccountID == nil {
+		// 3scale developer user has not been created
+		return nil
+	}
+
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), developerUserCR.Namespace, developerUserCR.Spec.ProviderAccountRef, developerUserCR.Spec.ProviderAccountNamespace, developerUserCR.Spec.TenantRef, logger)
+	if err != nil {
+		return err
+	}
+
+	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	if err != nil {
+		return err
+	}
+
+	// Not found when the parent developer account has already been removed
+	err = threescaleAPIClient.DeleteDeveloperUser(*developerUserCR.Status.AccountID, *developerUserCR.Status.ID)
+	if err != nil && !threescaleapi.IsNotFound(err) {
+		return fmt.Errorf("Error deleting developer user [%s;%d]: %w", developerUserCR.Name, *developerUserCR.Status.ID, err)
+	}
+
+	logger.Info("developer user removed from 3scale", "ID", *developerUserCR.Status.ID)
+	return nil
+}
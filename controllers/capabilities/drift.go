@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/3scale/3scale-operator/pkg/common"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/helper"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// CapabilitiesResyncPeriodEnvVar is the env var with the period of the capabilities resync.
+	// Go duration format, for instance "10m". Periodic resync is disabled when empty.
+	CapabilitiesResyncPeriodEnvVar = "CAPABILITIES_RESYNC_PERIOD"
+
+	// DriftedReason is the event reason when 3scale objects modified outside the operator are corrected
+	DriftedReason = "Drifted"
+)
+
+// CapabilitiesResyncPeriod returns the period of the capabilities resync read from the environment.
+// Zero means the periodic resync is disabled.
+func CapabilitiesResyncPeriod() (time.Duration, error) {
+	value := helper.GetEnvVar(CapabilitiesResyncPeriodEnvVar, "")
+	if value == "" {
+		return 0, nil
+	}
+
+	period, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", CapabilitiesResyncPeriodEnvVar, err)
+	}
+
+	if period < 0 {
+		return 0, fmt.Errorf("%s: negative period %s", CapabilitiesResyncPeriodEnvVar, value)
+	}
+
+	return period, nil
+}
+
+// driftDetector records the synchronization tasks that had to modify 3scale objects.
+// When the custom resource spec did not change since the last successful synchronization,
+// any modification means that the 3scale objects were changed outside the operator.
+type driftDetector struct {
+	recorder *controllerhelper.ThreescaleChangeRecorder
+	tasks    []string
+}
+
+func newDriftDetector(recorder *controllerhelper.ThreescaleChangeRecorder) *driftDetector {
+	return &driftDetector{recorder: recorder}
+}
+
+// Track wraps the task to record the task name when the task modifies 3scale objects
+func (d *driftDetector) Track(name string, task func(interface{}) error) func(interface{}) error {
+	if d == nil || d.recorder == nil {
+		return task
+	}
+
+	return func(ctx interface{}) error {
+		changes := d.recorder.Changes()
+		err := task(ctx)
+		if d.recorder.Changes() > changes {
+			d.tasks = append(d.tasks, name)
+		}
+		return err
+	}
+}
+
+// Tasks returns the names of the tasks that modified 3scale objects
+func (d *driftDetector) Tasks() []string {
+	if d == nil {
+		return nil
+	}
+
+	return d.tasks
+}
+
+// driftCheckRequired returns true when the custom resource spec has already been synchronized,
+// hence 3scale objects are not expected to be modified
+func driftCheckRequired(generation, observedGeneration int64, conditions common.Conditions, syncedType common.ConditionType) bool {
+	return generation == observedGeneration && conditions.IsTrueFor(syncedType)
+}
+
+// driftedCondition returns the condition listing the tasks that corrected 3scale objects
+func driftedCondition(conditionType common.ConditionType, driftedTasks []string) common.Condition {
+	condition := common.Condition{
+		Type:   conditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if len(driftedTasks) > 0 {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = fmt.Sprintf("3scale objects modified outside the operator were corrected by tasks: %s", strings.Join(driftedTasks, ", "))
+	}
+
+	return condition
+}
@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDriftDetectorTrack(t *testing.T) {
+	recorder := &controllerhelper.ThreescaleChangeRecorder{
+		Transport: roundTripFunc(func(req *http.Request) *http.Response {
+			return newTestJSONResponse(t, http.StatusOK, map[string]string{})
+		}),
+	}
+	httpClient := &http.Client{Transport: recorder}
+
+	task := func(method string) func(interface{}) error {
+		return func(interface{}) error {
+			req, err := http.NewRequest(method, "https://3scale-admin.example.com/admin/api/backend_apis.json", nil)
+			if err != nil {
+				return err
+			}
+			resp, err := httpClient.Do(req)
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		}
+	}
+
+	drift := newDriftDetector(recorder)
+	for name, method := range map[string]string{"SyncBackend": http.MethodGet, "SyncMethods": http.MethodPut} {
+		if err := drift.Track(name, task(method))(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !reflect.DeepEqual([]string{"SyncMethods"}, drift.Tasks()) {
+		t.Fatalf("unexpected drifted tasks %v", drift.Tasks())
+	}
+
+	// drift is not detected when the spec has not been synchronized yet
+	var noDrift *driftDetector
+	if err := noDrift.Track("SyncMethods", task(http.MethodPut))(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(noDrift.Tasks()) != 0 {
+		t.Fatalf("unexpected drifted tasks %v", noDrift.Tasks())
+	}
+}
+
+func TestDriftedCondition(t *testing.T) {
+	condition := driftedCondition("Drifted", nil)
+	if condition.Status != corev1.ConditionFalse {
+		t.Fatalf("unexpected condition %v", condition)
+	}
+
+	condition = driftedCondition("Drifted", []string{"SyncMethods", "SyncMappingRules"})
+	if condition.Status != corev1.ConditionTrue {
+		t.Fatalf("unexpected condition %v", condition)
+	}
+	if condition.Message != "3scale objects modified outside the operator were corrected by tasks: SyncMethods, SyncMappingRules" {
+		t.Fatalf("unexpected condition message %s", condition.Message)
+	}
+}
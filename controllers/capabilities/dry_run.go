@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/3scale/3scale-operator/pkg/common"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DryRunConfigMapField is the field of the dry-run configmap with the changes computed in dry-run mode
+	DryRunConfigMapField = "changes.yaml"
+)
+
+// dryRunConfigMapName returns the name of the configmap with the changes computed in dry-run mode.
+// The kind prefix avoids conflicts between products and backends with the same name
+func dryRunConfigMapName(kind, name string) string {
+	return fmt.Sprintf("%s-%s-dry-run", kind, name)
+}
+
+// reconcileDryRunConfigMap writes the changes computed in dry-run mode in a configmap owned by the custom resource.
+// The configmap is deleted when the dry-run mode is disabled
+func reconcileDryRunConfigMap(b *reconcilers.BaseReconciler, owner common.KubernetesObject, kind string, dryRun *controllerhelper.DryRunRecorder) error {
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dryRunConfigMapName(kind, owner.GetName()),
+			Namespace: owner.GetNamespace(),
+		},
+	}
+
+	if dryRun == nil {
+		common.TagObjectToDelete(configMap)
+	} else {
+		data, err := yaml.Marshal(dryRun.Changes())
+		if err != nil {
+			return err
+		}
+		configMap.Data = map[string]string{DryRunConfigMapField: string(data)}
+	}
+
+	err := b.SetOwnerReference(owner, configMap)
+	if err != nil {
+		return err
+	}
+
+	return b.ReconcileResource(&corev1.ConfigMap{}, configMap, func(existingObj, desiredObj common.KubernetesObject) (bool, error) {
+		existing, ok := existingObj.(*corev1.ConfigMap)
+		if !ok {
+			return false, fmt.Errorf("%T is not a *corev1.ConfigMap", existingObj)
+		}
+		desired, ok := desiredObj.(*corev1.ConfigMap)
+		if !ok {
+			return false, fmt.Errorf("%T is not a *corev1.ConfigMap", desiredObj)
+		}
+
+		updated, err := b.EnsureOwnerReference(owner, existing)
+		if err != nil {
+			return false, err
+		}
+
+		if !reflect.DeepEqual(existing.Data, desired.Data) {
+			existing.Data = desired.Data
+			updated = true
+		}
+
+		return updated, nil
+	})
+}
+
+// dryRunCondition returns the condition reporting the changes computed in dry-run mode
+func dryRunCondition(conditionType common.ConditionType, dryRun *controllerhelper.DryRunRecorder, configMapName string) common.Condition {
+	condition := common.Condition{
+		Type:   conditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if dryRun != nil {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = fmt.Sprintf("%d changes computed in dry-run mode, see configmap %s", len(dryRun.Changes()), configMapName)
+	}
+
+	return condition
+}
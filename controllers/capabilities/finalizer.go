@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/common"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+)
+
+// remoteDeletionFunc removes the 3scale object linked to the custom resource
+type remoteDeletionFunc func() error
+
+// ensureFinalizer adds the finalizer to the custom resource unless orphan deletion policy is set
+// or the custom resource is in dry-run mode.
+// Returns true when the custom resource has been updated
+func ensureFinalizer(b *reconcilers.BaseReconciler, obj common.KubernetesObject, finalizer string) (bool, error) {
+	if capabilitiesv1beta1.IsDeletionPolicyOrphan(obj) || capabilitiesv1beta1.IsDryRun(obj) || controllerutil.ContainsFinalizer(obj, finalizer) {
+		return false, nil
+	}
+
+	controllerutil.AddFinalizer(obj, finalizer)
+	err := b.UpdateResource(obj)
+	if err != nil {
+		return false, fmt.Errorf("Failed adding finalizer %s: %w", finalizer, err)
+	}
+
+	return true, nil
+}
+
+// reconcileFinalizer removes the 3scale object, unless orphan deletion policy is set
+// or the custom resource is in dry-run mode, and then releases the custom resource removing the finalizer.
+// Failed removals are reported with warning events and retried.
+// When the referenced Tenant does not exist anymore, the 3scale object is orphaned.
+func reconcileFinalizer(b *reconcilers.BaseReconciler, obj common.KubernetesObject, finalizer string, removeFn remoteDeletionFunc, logger logr.Logger) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(obj, finalizer) {
+		// Ignore deleted resources, this can happen when foregroundDeletion is enabled
+		// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/#foreground-cascading-deletion
+		return ctrl.Result{}, nil
+	}
+
+	// Dry-run mode never modifies 3scale, the deletion is handled as orphan
+	if !capabilitiesv1beta1.IsDeletionPolicyOrphan(obj) && !capabilitiesv1beta1.IsDryRun(obj) {
+		err := removeFn()
+		if controllerhelper.IsTenantNotFoundError(err) {
+			// The tenant credentials are gone, the 3scale object cannot be removed anymore.
+			// The deletion is handled as orphan
+			logger.Info("tenant not found. 3scale object orphaned", "reason", err)
+			b.EventRecorder().Eventf(obj, corev1.EventTypeWarning, "OrphanDeletion", "3scale object not removed: %v", err)
+			err = nil
+		}
+		if err != nil {
+			if helper.IsWaitError(err) {
+				// On wait error, retry
+				logger.Info("deletion blocked. Retrying", "reason", err)
+				b.EventRecorder().Eventf(obj, corev1.EventTypeWarning, "DeletionBlocked", "%v", err)
+				return ctrl.Result{Requeue: true}, nil
+			}
+
+			logger.Error(err, "Failed to remove 3scale object")
+			b.EventRecorder().Eventf(obj, corev1.EventTypeWarning, "DeletionError", "%v", err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(obj, finalizer)
+	err := b.UpdateResource(obj)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("Failed removing finalizer %s: %w", finalizer, err)
+	}
+
+	logger.Info("finalizer removed")
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/helper"
+
+	logrtesting "github.com/go-logr/logr/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func newTestFinalizerProduct(annotations map[string]string, finalizers ...string) *capabilitiesv1beta1.Product {
+	return &capabilitiesv1beta1.Product{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "myproduct",
+			Namespace:   "myns",
+			Annotations: annotations,
+			Finalizers:  finalizers,
+		},
+	}
+}
+
+func TestEnsureFinalizer(t *testing.T) {
+	product := newTestFinalizerProduct(nil)
+	b := newTestBaseReconciler(t, product)
+
+	added, err := ensureFinalizer(b, product, productFinalizer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !added || !controllerutil.ContainsFinalizer(product, productFinalizer) {
+		t.Fatal("finalizer not added")
+	}
+
+	// already added
+	added, err = ensureFinalizer(b, product, productFinalizer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added {
+		t.Fatal("finalizer added twice")
+	}
+}
+
+func TestEnsureFinalizerOrphan(t *testing.T) {
+	product := newTestFinalizerProduct(map[string]string{
+		capabilitiesv1beta1.DeletionPolicyAnnotation: capabilitiesv1beta1.DeletionPolicyOrphan,
+	})
+
+	added, err := ensureFinalizer(newTestBaseReconciler(t, product), product, productFinalizer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added || controllerutil.ContainsFinalizer(product, productFinalizer) {
+		t.Fatal("finalizer added with orphan deletion policy")
+	}
+}
+
+func TestReconcileFinalizer(t *testing.T) {
+	cases := []struct {
+		name              string
+		annotations       map[string]string
+		removeErr         error
+		expectedRemoved   bool
+		expectedFinalizer bool
+		expectedRequeue   bool
+		expectedErr       bool
+	}{
+		{"removed", nil, nil, true, false, false, false},
+		{"orphan", map[string]string{capabilitiesv1beta1.DeletionPolicyAnnotation: capabilitiesv1beta1.DeletionPolicyOrphan}, nil, false, false, false, false},
+		{"wait error", nil, &helper.WaitError{Err: errors.New("in use")}, true, true, true, false},
+		{"remove error", nil, errors.New("unavailable"), true, true, false, true},
+		{"tenant not found", nil, fmt.Errorf("LookupProviderAccount: %w", &controllerhelper.TenantNotFoundError{Namespace: "myns", Name: "mytenant"}), true, false, false, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			product := newTestFinalizerProduct(tc.annotations, productFinalizer)
+
+			removed := false
+			result, err := reconcileFinalizer(newTestBaseReconciler(subT, product), product, productFinalizer, func() error {
+				removed = true
+				return tc.removeErr
+			}, logrtesting.NullLogger{})
+			if (err != nil) != tc.expectedErr {
+				subT.Fatalf("unexpected error: %v", err)
+			}
+			if removed != tc.expectedRemoved {
+				subT.Fatalf("3scale object removal: expected %t, got %t", tc.expectedRemoved, removed)
+			}
+			if controllerutil.ContainsFinalizer(product, productFinalizer) != tc.expectedFinalizer {
+				subT.Fatalf("finalizer: expected %t, got %v", tc.expectedFinalizer, product.Finalizers)
+			}
+			if result.Requeue != tc.expectedRequeue {
+				subT.Fatalf("requeue: expected %t, got %t", tc.expectedRequeue, result.Requeue)
+			}
+		})
+	}
+}
+
+func TestReconcileFinalizerTenantNotFound(t *testing.T) {
+	product := newTestFinalizerProduct(nil, productFinalizer)
+	b := newTestBaseReconciler(t, product)
+
+	_, err := reconcileFinalizer(b, product, productFinalizer, func() error {
+		return &controllerhelper.TenantNotFoundError{Namespace: "myns", Name: "mytenant"}
+	}, logrtesting.NullLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if controllerutil.ContainsFinalizer(product, productFinalizer) {
+		t.Fatal("finalizer not removed")
+	}
+
+	recorder := b.EventRecorder().(*record.FakeRecorder)
+	if event := <-recorder.Events; !strings.Contains(event, "OrphanDeletion") {
+		t.Fatalf("unexpected event %s", event)
+	}
+}
+
+func TestEnsureFinalizerDryRun(t *testing.T) {
+	product := &capabilitiesv1beta1.Product{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "myproduct",
+			Namespace:   "myns",
+			Annotations: map[string]string{capabilitiesv1beta1.DryRunAnnotation: "true"},
+		},
+	}
+
+	added, err := ensureFinalizer(newTestBaseReconciler(t, product), product, productFinalizer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added || controllerutil.ContainsFinalizer(product, productFinalizer) {
+		t.Fatal("finalizer added in dry-run mode")
+	}
+}
+
+func TestReconcileFinalizerDryRun(t *testing.T) {
+	product := &capabilitiesv1beta1.Product{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "myproduct",
+			Namespace:   "myns",
+			Annotations: map[string]string{capabilitiesv1beta1.DryRunAnnotation: "true"},
+			Finalizers:  []string{productFinalizer},
+		},
+	}
+
+	_, err := reconcileFinalizer(newTestBaseReconciler(t, product), product, productFinalizer, func() error {
+		return errors.New("3scale object removed in dry-run mode")
+	}, logrtesting.NullLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if controllerutil.ContainsFinalizer(product, productFinalizer) {
+		t.Fatal("finalizer not removed")
+	}
+}
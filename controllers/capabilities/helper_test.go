@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+
+	logrtesting "github.com/go-logr/logr/testing"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// roundTripFunc replaces the http transport to avoid making real calls to 3scale
+type roundTripFunc func(req *http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
+
+func newTestHTTPClient(fn roundTripFunc) *http.Client {
+	return &http.Client{Transport: fn}
+}
+
+func newTestJSONResponse(t *testing.T, statusCode int, obj interface{}) *http.Response {
+	t.Helper()
+	body, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       ioutil.NopCloser(bytes.NewBuffer(body)),
+		Header:     make(http.Header),
+	}
+}
+
+func newTestBaseReconciler(t *testing.T, objs ...runtime.Object) *reconcilers.BaseReconciler {
+	t.Helper()
+	s := scheme.Scheme
+	err := capabilitiesv1beta1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cl := fake.NewFakeClientWithScheme(s, objs...)
+	clientAPIReader := fake.NewFakeClientWithScheme(s, objs...)
+	clientset := fakeclientset.NewSimpleClientset()
+	recorder := record.NewFakeRecorder(10000)
+
+	return reconcilers.NewBaseReconciler(context.TODO(), cl, s, clientAPIReader, logrtesting.NullLogger{}, clientset.Discovery(), recorder)
+}
@@ -0,0 +1,225 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/common"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// OpenAPIActiveDocSecretField is the field of the generated secret with the OpenAPI document
+	OpenAPIActiveDocSecretField = "openapi.json"
+
+	// OpenAPIDigestAnnotation is the annotation of the generated activedoc with the OpenAPI document digest.
+	// The annotation changes when the OpenAPI document content changes and the activedoc is reconciled again.
+	OpenAPIDigestAnnotation = "capabilities.3scale.net/openapi-digest"
+)
+
+// OpenAPIActiveDocReconciler reconciles the activedoc generated from the OpenAPI document
+// and the secret holding the OpenAPI document referenced by the activedoc
+type OpenAPIActiveDocReconciler struct {
+	*reconcilers.BaseReconciler
+	openapiCR     *capabilitiesv1beta1.OpenAPI
+	openapiObj    *openapi3.Swagger
+	openapiDigest string
+	logger        logr.Logger
+}
+
+func NewOpenAPIActiveDocReconciler(b *reconcilers.BaseReconciler,
+	openapiCR *capabilitiesv1beta1.OpenAPI,
+	openapiObj *openapi3.Swagger,
+	openapiDigest string,
+	logger logr.Logger,
+) *OpenAPIActiveDocReconciler {
+	return &OpenAPIActiveDocReconciler{
+		BaseReconciler: b,
+		openapiCR:      openapiCR,
+		openapiObj:     openapiObj,
+		openapiDigest:  openapiDigest,
+		logger:         logger,
+	}
+}
+
+func (p *OpenAPIActiveDocReconciler) Logger() logr.Logger {
+	return p.logger
+}
+
+func (p *OpenAPIActiveDocReconciler) Reconcile() error {
+	desiredSecret, err := p.desiredSecret()
+	if err != nil {
+		return err
+	}
+
+	desiredActiveDoc, err := p.desiredActiveDoc()
+	if err != nil {
+		return err
+	}
+
+	if p.openapiCR.Spec.ActiveDoc == nil {
+		common.TagObjectToDelete(desiredActiveDoc)
+		common.TagObjectToDelete(desiredSecret)
+	}
+
+	err = p.ReconcileResource(&corev1.Secret{}, desiredSecret, p.secretMutator)
+	if err != nil {
+		return err
+	}
+
+	return p.ReconcileResource(&capabilitiesv1beta1.ActiveDoc{}, desiredActiveDoc, p.activeDocMutator)
+}
+
+func (p *OpenAPIActiveDocReconciler) desiredSecret() (*corev1.Secret, error) {
+	data, err := json.Marshal(p.openapiObj)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      p.desiredObjName(),
+			Namespace: p.openapiCR.Namespace,
+		},
+		Data: map[string][]byte{
+			OpenAPIActiveDocSecretField: data,
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+
+	err = p.SetOwnerReference(p.openapiCR, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+func (p *OpenAPIActiveDocReconciler) desiredActiveDoc() (*capabilitiesv1beta1.ActiveDoc, error) {
+	productSystemName := p.desiredProductSystemName()
+	// activedoc system name only allows alphanumeric chars
+	systemName := helper.NonAlphanumRegexp.ReplaceAllString(strings.ToLower(productSystemName), "")
+
+	activeDoc := &capabilitiesv1beta1.ActiveDoc{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       capabilitiesv1beta1.ActiveDocKind,
+			APIVersion: capabilitiesv1beta1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      p.desiredObjName(),
+			Namespace: p.openapiCR.Namespace,
+			Annotations: map[string]string{
+				OpenAPIDigestAnnotation: p.openapiDigest,
+			},
+		},
+		Spec: capabilitiesv1beta1.ActiveDocSpec{
+			Name:                     p.openapiObj.Info.Title,
+			SystemName:               &systemName,
+			Description:              &p.openapiObj.Info.Description,
+			ProviderAccountRef:       p.openapiCR.Spec.ProviderAccountRef,
+			ProviderAccountNamespace: p.openapiCR.Spec.ProviderAccountNamespace,
+			TenantRef:                p.openapiCR.Spec.TenantRef,
+			ProductSystemName:        &productSystemName,
+			ActiveDocOpenAPIRef: capabilitiesv1beta1.ActiveDocOpenAPIRefSpec{
+				SecretRef: &corev1.ObjectReference{
+					Name:      p.desiredObjName(),
+					Namespace: p.openapiCR.Namespace,
+				},
+			},
+		},
+	}
+
+	if p.openapiCR.Spec.ActiveDoc != nil {
+		activeDoc.Spec.Published = p.openapiCR.Spec.ActiveDoc.Published
+		activeDoc.Spec.SkipSwaggerValidations = p.openapiCR.Spec.ActiveDoc.SkipSwaggerValidations
+	}
+
+	err := p.SetOwnerReference(p.openapiCR, activeDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	return activeDoc, nil
+}
+
+func (p *OpenAPIActiveDocReconciler) secretMutator(existingObj, desiredObj common.KubernetesObject) (bool, error) {
+	existing, ok := existingObj.(*corev1.Secret)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *corev1.Secret", existingObj)
+	}
+	desired, ok := desiredObj.(*corev1.Secret)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *corev1.Secret", desiredObj)
+	}
+
+	updated, err := p.EnsureOwnerReference(p.openapiCR, existing)
+	if err != nil {
+		return false, err
+	}
+
+	if !reflect.DeepEqual(existing.Data, desired.Data) {
+		p.Logger().Info(fmt.Sprintf("%s data has changed", common.ObjectInfo(desired)))
+		existing.Data = desired.Data
+		updated = true
+	}
+
+	return updated, nil
+}
+
+func (p *OpenAPIActiveDocReconciler) activeDocMutator(existingObj, desiredObj common.KubernetesObject) (bool, error) {
+	existing, ok := existingObj.(*capabilitiesv1beta1.ActiveDoc)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *capabilitiesv1beta1.ActiveDoc", existingObj)
+	}
+	desired, ok := desiredObj.(*capabilitiesv1beta1.ActiveDoc)
+	if !ok {
+		return false, fmt.Errorf("%T is not a *capabilitiesv1beta1.ActiveDoc", desiredObj)
+	}
+
+	// Metadata labels and annotations
+	updated := helper.EnsureObjectMeta(existing, desired)
+
+	// OwnerRefenrence
+	updatedTmp, err := p.EnsureOwnerReference(p.openapiCR, existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
+	if !reflect.DeepEqual(existing.Spec, desired.Spec) {
+		diff := cmp.Diff(existing.Spec, desired.Spec)
+		p.Logger().Info(fmt.Sprintf("%s spec has changed: %s", common.ObjectInfo(desired), diff))
+		existing.Spec = desired.Spec
+		updated = true
+	}
+
+	return updated, nil
+}
+
+func (p *OpenAPIActiveDocReconciler) desiredProductSystemName() string {
+	// Same as product system name
+	if p.openapiCR.Spec.ProductSystemName != nil {
+		return *p.openapiCR.Spec.ProductSystemName
+	}
+
+	return helper.SystemNameFromOpenAPITitle(p.openapiObj)
+}
+
+func (p *OpenAPIActiveDocReconciler) desiredObjName() string {
+	// Same as product obj name
+	return fmt.Sprintf("%s-%s", helper.K8sNameFromOpenAPITitle(p.openapiObj), string(p.openapiCR.UID))
+}
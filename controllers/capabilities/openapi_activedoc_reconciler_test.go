@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	logrtesting "github.com/go-logr/logr/testing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newTestOpenAPICR() *capabilitiesv1beta1.OpenAPI {
+	return &capabilitiesv1beta1.OpenAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "myopenapi", Namespace: "myns", UID: "abcd"},
+		Spec: capabilitiesv1beta1.OpenAPISpec{
+			ProviderAccountRef: &corev1.LocalObjectReference{Name: "mysecret"},
+		},
+	}
+}
+
+const testOpenAPIDoc = `
+openapi: 3.0.2
+info:
+  title: My Petstore-API v2
+  description: Petstore
+  version: "1.0"
+servers:
+  - url: https://petstore.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: pets
+`
+
+func newTestOpenAPIObj(t *testing.T) *openapi3.Swagger {
+	t.Helper()
+	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testOpenAPIDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return openapiObj
+}
+
+func TestOpenAPIActiveDocReconcilerDesiredActiveDoc(t *testing.T) {
+	published := true
+	openapiCR := newTestOpenAPICR()
+	openapiCR.Spec.ActiveDoc = &capabilitiesv1beta1.OpenAPIActiveDocSpec{Published: &published}
+	openapiObj := newTestOpenAPIObj(t)
+
+	reconciler := NewOpenAPIActiveDocReconciler(newTestBaseReconciler(t, openapiCR), openapiCR, openapiObj, "digest", logrtesting.NullLogger{})
+	activeDoc, err := reconciler.desiredActiveDoc()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if activeDoc.Name != "mypetstoreapiv2-abcd" || activeDoc.Namespace != "myns" {
+		t.Fatalf("unexpected activedoc name %s/%s", activeDoc.Namespace, activeDoc.Name)
+	}
+	if activeDoc.Annotations[OpenAPIDigestAnnotation] != "digest" {
+		t.Fatalf("unexpected annotations %v", activeDoc.Annotations)
+	}
+	if activeDoc.Spec.Name != "My Petstore-API v2" {
+		t.Fatalf("unexpected name %s", activeDoc.Spec.Name)
+	}
+	// activedoc system name only allows alphanumeric chars
+	if activeDoc.Spec.SystemName == nil || *activeDoc.Spec.SystemName != "mypetstoreapiv2" {
+		t.Fatalf("unexpected system name %v", activeDoc.Spec.SystemName)
+	}
+	if activeDoc.Spec.ProductSystemName == nil || *activeDoc.Spec.ProductSystemName != "my_petstore_api_v2" {
+		t.Fatalf("unexpected product system name %v", activeDoc.Spec.ProductSystemName)
+	}
+	if activeDoc.Spec.ProviderAccountRef == nil || activeDoc.Spec.ProviderAccountRef.Name != "mysecret" {
+		t.Fatalf("unexpected provider account reference %v", activeDoc.Spec.ProviderAccountRef)
+	}
+	if activeDoc.Spec.Published == nil || !*activeDoc.Spec.Published {
+		t.Fatal("published activedoc expected")
+	}
+
+	secretRef := activeDoc.Spec.ActiveDocOpenAPIRef.SecretRef
+	if secretRef == nil || secretRef.Name != activeDoc.Name || secretRef.Namespace != "myns" {
+		t.Fatalf("unexpected secret reference %v", secretRef)
+	}
+	if len(activeDoc.OwnerReferences) != 1 || activeDoc.OwnerReferences[0].Name != "myopenapi" {
+		t.Fatalf("unexpected owner references %v", activeDoc.OwnerReferences)
+	}
+}
+
+func TestOpenAPIActiveDocReconcilerProductSystemName(t *testing.T) {
+	productSystemName := "Custom_Product-1"
+	openapiCR := newTestOpenAPICR()
+	openapiCR.Spec.ProductSystemName = &productSystemName
+
+	reconciler := NewOpenAPIActiveDocReconciler(newTestBaseReconciler(t, openapiCR), openapiCR, newTestOpenAPIObj(t), "digest", logrtesting.NullLogger{})
+	activeDoc, err := reconciler.desiredActiveDoc()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// linked to the same product system name
+	if activeDoc.Spec.ProductSystemName == nil || *activeDoc.Spec.ProductSystemName != productSystemName {
+		t.Fatalf("unexpected product system name %v", activeDoc.Spec.ProductSystemName)
+	}
+	if activeDoc.Spec.SystemName == nil || *activeDoc.Spec.SystemName != "customproduct1" {
+		t.Fatalf("unexpected system name %v", activeDoc.Spec.SystemName)
+	}
+}
+
+func TestOpenAPIActiveDocReconcilerTenantRef(t *testing.T) {
+	openapiCR := newTestOpenAPICR()
+	openapiCR.Spec.ProviderAccountRef = nil
+	openapiCR.Spec.TenantRef = &corev1.LocalObjectReference{Name: "mytenant"}
+
+	reconciler := NewOpenAPIActiveDocReconciler(newTestBaseReconciler(t, openapiCR), openapiCR, newTestOpenAPIObj(t), "digest", logrtesting.NullLogger{})
+	activeDoc, err := reconciler.desiredActiveDoc()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if activeDoc.Spec.TenantRef == nil || activeDoc.Spec.TenantRef.Name != "mytenant" {
+		t.Fatalf("unexpected tenant reference %v", activeDoc.Spec.TenantRef)
+	}
+}
+
+func TestOpenAPIActiveDocReconcilerReconcile(t *testing.T) {
+	openapiCR := newTestOpenAPICR()
+	openapiCR.Spec.ActiveDoc = &capabilitiesv1beta1.OpenAPIActiveDocSpec{}
+
+	baseReconciler := newTestBaseReconciler(t, openapiCR)
+	reconciler := NewOpenAPIActiveDocReconciler(baseReconciler, openapiCR, newTestOpenAPIObj(t), "digest", logrtesting.NullLogger{})
+	err := reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := types.NamespacedName{Name: "mypetstoreapiv2-abcd", Namespace: "myns"}
+	secret := &corev1.Secret{}
+	err = baseReconciler.Client().Get(baseReconciler.Context(), key, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret.Data[OpenAPIActiveDocSecretField]) == 0 {
+		t.Fatal("openapi document expected in the secret")
+	}
+	err = baseReconciler.Client().Get(baseReconciler.Context(), key, &capabilitiesv1beta1.ActiveDoc{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// activedoc disabled, generated objects removed
+	openapiCR.Spec.ActiveDoc = nil
+	err = reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = baseReconciler.Client().Get(baseReconciler.Context(), key, &corev1.Secret{})
+	if !errors.IsNotFound(err) {
+		t.Fatalf("secret not removed: %v", err)
+	}
+	err = baseReconciler.Client().Get(baseReconciler.Context(), key, &capabilitiesv1beta1.ActiveDoc{})
+	if !errors.IsNotFound(err) {
+		t.Fatalf("activedoc not removed: %v", err)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type OpenAPIBackendReconciler struct {
@@ -49,38 +50,61 @@ func (p *OpenAPIBackendReconciler) Logger() logr.Logger {
 }
 
 func (p *OpenAPIBackendReconciler) Reconcile() ([]*capabilitiesv1beta1.Backend, error) {
-	desired, err := p.desired()
+	desiredList, err := p.desired()
 	if err != nil {
 		return nil, err
 	}
 
 	if p.Logger().V(1).Enabled() {
-		jsonData, err := json.MarshalIndent(desired, "", "  ")
+		jsonData, err := json.MarshalIndent(desiredList, "", "  ")
 		if err != nil {
 			return nil, err
 		}
 		p.Logger().V(1).Info(string(jsonData))
 	}
 
-	// single backend implementation
-	return nil, p.ReconcileResource(&capabilitiesv1beta1.Backend{}, desired, p.backendMutator)
+	for _, desired := range desiredList {
+		err = p.ReconcileResource(&capabilitiesv1beta1.Backend{}, desired, p.backendMutator)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = p.deleteStaleBackends(desiredList)
+	if err != nil {
+		return nil, err
+	}
+
+	return desiredList, nil
+}
+
+func (p *OpenAPIBackendReconciler) desired() ([]*capabilitiesv1beta1.Backend, error) {
+	splitter, err := newOpenAPIBackendSplitter(p.openapiCR, p.openapiObj)
+	if err != nil {
+		return nil, err
+	}
+
+	backends := make([]*capabilitiesv1beta1.Backend, 0, len(splitter.Backends()))
+	for _, openapiBackend := range splitter.Backends() {
+		backend, err := p.desiredBackend(openapiBackend)
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, backend)
+	}
+
+	return backends, nil
 }
 
-func (p *OpenAPIBackendReconciler) desired() (*capabilitiesv1beta1.Backend, error) {
+func (p *OpenAPIBackendReconciler) desiredBackend(openapiBackend *openAPIBackend) (*capabilitiesv1beta1.Backend, error) {
 	fieldErrors := field.ErrorList{}
 	specFldPath := field.NewPath("spec")
 	openapiRefFldPath := specFldPath.Child("openapiRef")
 
-	// system name
-	systemName := p.desiredSystemName()
-
-	// obj name
-	objName := p.desiredObjName()
-
 	// DNS Subdomain Names
 	// If the name would be part of some label, validation would be DNS Label Names (validation.IsDNS1123Label)
 	// https://kubernetes.io/docs/concepts/overview/working-with-objects/names/
-	errStrings := validation.IsDNS1123Subdomain(objName)
+	errStrings := validation.IsDNS1123Subdomain(openapiBackend.ObjName)
 	if len(errStrings) > 0 {
 		fieldErrors = append(fieldErrors, field.Invalid(openapiRefFldPath, p.openapiCR.Spec.OpenAPIRef, strings.Join(errStrings, ",")))
 		return nil, &helper.SpecFieldError{
@@ -89,33 +113,23 @@ func (p *OpenAPIBackendReconciler) desired() (*capabilitiesv1beta1.Backend, erro
 		}
 	}
 
-	// backend name
-	name := fmt.Sprintf("%s Backend", p.openapiObj.Info.Title)
-
-	// backend description
-	description := fmt.Sprintf("Backend of %s", p.openapiObj.Info.Title)
-
-	// private base URL
-	privateBaseURL, err := p.desiredPrivateBaseURL()
-	if err != nil {
-		return nil, err
-	}
-
 	backend := &capabilitiesv1beta1.Backend{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       capabilitiesv1beta1.BackendKind,
 			APIVersion: capabilitiesv1beta1.GroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      objName,
+			Name:      openapiBackend.ObjName,
 			Namespace: p.openapiCR.Namespace,
 		},
 		Spec: capabilitiesv1beta1.BackendSpec{
-			Name:               name,
-			SystemName:         systemName,
-			PrivateBaseURL:     privateBaseURL,
-			Description:        description,
-			ProviderAccountRef: p.openapiCR.Spec.ProviderAccountRef,
+			Name:                     openapiBackend.Name,
+			SystemName:               openapiBackend.SystemName,
+			PrivateBaseURL:           openapiBackend.PrivateBaseURL,
+			Description:              openapiBackend.Description,
+			ProviderAccountRef:       p.openapiCR.Spec.ProviderAccountRef,
+			ProviderAccountNamespace: p.openapiCR.Spec.ProviderAccountNamespace,
+			TenantRef:                p.openapiCR.Spec.TenantRef,
 		},
 	}
 
@@ -127,7 +141,7 @@ func (p *OpenAPIBackendReconciler) desired() (*capabilitiesv1beta1.Backend, erro
 		return nil, errors.New(validationErrors.ToAggregate().Error())
 	}
 
-	err = p.SetOwnerReference(p.openapiCR, backend)
+	err := p.SetOwnerReference(p.openapiCR, backend)
 	if err != nil {
 		return nil, err
 	}
@@ -135,6 +149,40 @@ func (p *OpenAPIBackendReconciler) desired() (*capabilitiesv1beta1.Backend, erro
 	return backend, nil
 }
 
+// deleteStaleBackends deletes the owned backends that are no longer generated from the OpenAPI document
+func (p *OpenAPIBackendReconciler) deleteStaleBackends(desiredList []*capabilitiesv1beta1.Backend) error {
+	desiredNames := map[string]bool{}
+	for _, desired := range desiredList {
+		desiredNames[desired.Name] = true
+	}
+
+	backendList := &capabilitiesv1beta1.BackendList{}
+	err := p.Client().List(p.Context(), backendList, client.InNamespace(p.openapiCR.Namespace))
+	if err != nil {
+		return fmt.Errorf("Failed to list backends: %w", err)
+	}
+
+	for idx := range backendList.Items {
+		backend := &backendList.Items[idx]
+		if desiredNames[backend.Name] || backend.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		for _, ownerRef := range backend.GetOwnerReferences() {
+			if ownerRef.UID == p.openapiCR.UID {
+				common.TagObjectToDelete(backend)
+				err = p.ReconcileResource(&capabilitiesv1beta1.Backend{}, backend, p.backendMutator)
+				if err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 func (p *OpenAPIBackendReconciler) backendMutator(existingObj, desiredObj common.KubernetesObject) (bool, error) {
 	existing, ok := existingObj.(*capabilitiesv1beta1.Backend)
 	if !ok {
@@ -168,42 +216,3 @@ func (p *OpenAPIBackendReconciler) backendMutator(existingObj, desiredObj common
 
 	return updated, nil
 }
-
-func (p *OpenAPIBackendReconciler) desiredSystemName() string {
-	// Same as product system name
-	// Duplicated implementation. Refactor
-	if p.openapiCR.Spec.ProductSystemName != nil {
-		return *p.openapiCR.Spec.ProductSystemName
-	}
-
-	return helper.SystemNameFromOpenAPITitle(p.openapiObj)
-}
-
-func (p *OpenAPIBackendReconciler) desiredObjName() string {
-	// DNS1123 Label compliant name. Due to UIDs are 36 characters of length this
-	// means that the maximum prefix lenght that can be provided is of 26
-	// characters. If the generated name is not DNS1123 compliant an error is
-	// returned
-	// Maybe truncate?
-	return fmt.Sprintf("%s-%s", helper.K8sNameFromOpenAPITitle(p.openapiObj), string(p.openapiCR.UID))
-}
-
-func (p *OpenAPIBackendReconciler) desiredPrivateBaseURL() (string, error) {
-	if p.openapiCR.Spec.PrivateBaseURL != nil {
-		return *p.openapiCR.Spec.PrivateBaseURL, nil
-	}
-
-	privateBaseURL, err := helper.BaseURLFromOpenAPI(p.openapiObj)
-	if err != nil {
-		fieldErrors := field.ErrorList{}
-		specFldPath := field.NewPath("spec")
-		openapiRefFldPath := specFldPath.Child("openapiRef")
-		fieldErrors = append(fieldErrors, field.Invalid(openapiRefFldPath, p.openapiCR.Spec.OpenAPIRef, err.Error()))
-		return "", &helper.SpecFieldError{
-			ErrorType:      helper.InvalidError,
-			FieldErrorList: fieldErrors,
-		}
-	}
-
-	return privateBaseURL, nil
-}
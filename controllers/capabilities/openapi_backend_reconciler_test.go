@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"testing"
+
+	logrtesting "github.com/go-logr/logr/testing"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestOpenAPIBackendReconcilerTenantRef(t *testing.T) {
+	openapiCR := newTestOpenAPICR()
+	openapiCR.Spec.ProviderAccountRef = nil
+	openapiCR.Spec.TenantRef = &corev1.LocalObjectReference{Name: "mytenant"}
+
+	reconciler := NewOpenAPIBackendReconciler(newTestBaseReconciler(t, openapiCR), openapiCR, newTestOpenAPIObj(t), nil, logrtesting.NullLogger{})
+	backends, err := reconciler.desired()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(backends) != 1 {
+		t.Fatalf("one backend expected, got %d", len(backends))
+	}
+	if backends[0].Spec.ProviderAccountRef != nil {
+		t.Fatalf("unexpected provider account reference %v", backends[0].Spec.ProviderAccountRef)
+	}
+	if backends[0].Spec.TenantRef == nil || backends[0].Spec.TenantRef.Name != "mytenant" {
+		t.Fatalf("unexpected tenant reference %v", backends[0].Spec.TenantRef)
+	}
+}
@@ -0,0 +1,334 @@
+package controllers
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// openAPIBackend is a 3scale backend generated from the OpenAPI document
+type openAPIBackend struct {
+	ObjName        string
+	SystemName     string
+	Name           string
+	Description    string
+	PrivateBaseURL string
+	// Path is the backend usage path of the product
+	Path string
+
+	// server URL (servers mode) or tag (tags mode) of the operations assigned to the backend
+	serverURL string
+	tag       string
+}
+
+// openAPIBackendTag is an item of the x-3scale-backends document extension indexed by tag name
+type openAPIBackendTag struct {
+	PrivateBaseURL string `json:"privateBaseURL"`
+	Path           string `json:"path,omitempty"`
+}
+
+// openAPIBackendSplitter generates the backends from the OpenAPI document
+// and assigns the OpenAPI operations to the backends.
+// By default, a single backend is generated from the first server.
+type openAPIBackendSplitter struct {
+	openapiCR  *capabilitiesv1beta1.OpenAPI
+	openapiObj *openapi3.Swagger
+	backends   []*openAPIBackend
+}
+
+func newOpenAPIBackendSplitter(openapiCR *capabilitiesv1beta1.OpenAPI, openapiObj *openapi3.Swagger) (*openAPIBackendSplitter, error) {
+	s := &openAPIBackendSplitter{
+		openapiCR:  openapiCR,
+		openapiObj: openapiObj,
+	}
+
+	var err error
+	switch s.mode() {
+	case capabilitiesv1beta1.OpenAPIBackendSplitModeServers:
+		err = s.splitByServers()
+	case capabilitiesv1beta1.OpenAPIBackendSplitModeTags:
+		err = s.splitByTags()
+	default:
+		err = s.addDefaultBackend()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 3scale backend usage paths must be unique within the product
+	backendPaths := map[string]bool{}
+	for _, backend := range s.backends {
+		if backendPaths[backend.Path] {
+			return nil, s.invalidOpenAPIRefError(fmt.Sprintf("backend path %s is used by more than one backend", backend.Path))
+		}
+		backendPaths[backend.Path] = true
+	}
+
+	return s, nil
+}
+
+func (s *openAPIBackendSplitter) mode() string {
+	if s.openapiCR.Spec.BackendSplitMode == nil {
+		return ""
+	}
+
+	return *s.openapiCR.Spec.BackendSplitMode
+}
+
+// Backends returns the backends generated from the OpenAPI document
+func (s *openAPIBackendSplitter) Backends() []*openAPIBackend {
+	return s.backends
+}
+
+// OperationBackend returns the backend of the OpenAPI operation
+func (s *openAPIBackendSplitter) OperationBackend(path, verb string, pathItem *openapi3.PathItem, operation *openapi3.Operation) (*openAPIBackend, error) {
+	switch s.mode() {
+	case capabilitiesv1beta1.OpenAPIBackendSplitModeServers:
+		serverURL := operationServerURL(pathItem, operation)
+		if serverURL == "" {
+			return s.backends[0], nil
+		}
+
+		for _, backend := range s.backends {
+			if backend.serverURL == serverURL {
+				return backend, nil
+			}
+		}
+
+		return nil, s.invalidOpenAPIRefError(fmt.Sprintf("operation %s %s: server %s is not declared in the document servers", strings.ToUpper(verb), path, serverURL))
+	case capabilitiesv1beta1.OpenAPIBackendSplitModeTags:
+		for _, tag := range operation.Tags {
+			for _, backend := range s.backends {
+				if backend.tag != "" && backend.tag == tag {
+					return backend, nil
+				}
+			}
+		}
+
+		// default backend
+		for _, backend := range s.backends {
+			if backend.tag == "" {
+				return backend, nil
+			}
+		}
+
+		return nil, fmt.Errorf("operation %s %s: default backend not found", strings.ToUpper(verb), path)
+	}
+
+	return s.backends[0], nil
+}
+
+// splitByServers generates one backend for each document server.
+// The backend usage path is the server URL path.
+func (s *openAPIBackendSplitter) splitByServers() error {
+	if len(s.openapiObj.Servers) == 0 {
+		return s.invalidOpenAPIRefError("servers are required to generate the backends from the servers")
+	}
+
+	for idx, server := range s.openapiObj.Servers {
+		serverURL, err := helper.RenderOpenAPIServerURL(server)
+		if err != nil {
+			return s.invalidOpenAPIRefError(err.Error())
+		}
+
+		privateBaseURL, backendPath, err := openAPIBackendURL(serverURL)
+		if err != nil {
+			return s.invalidOpenAPIRefError(fmt.Sprintf("server %s: %s", server.URL, err.Error()))
+		}
+
+		backend := s.newBackend(fmt.Sprintf("server%d", idx), fmt.Sprintf("server %s", server.URL))
+		backend.PrivateBaseURL = privateBaseURL
+		backend.Path = backendPath
+		backend.serverURL = server.URL
+		s.backends = append(s.backends, backend)
+	}
+
+	return nil
+}
+
+// splitByTags generates one backend for each tag of the x-3scale-backends document extension.
+// Operations without any of those tags are assigned to the default backend.
+func (s *openAPIBackendSplitter) splitByTags() error {
+	backendTags := map[string]openAPIBackendTag{}
+	found, err := helper.DecodeOpenAPIExtension(s.openapiObj.ExtensionProps, helper.OpenAPIExtension3scaleBackends, &backendTags)
+	if err != nil {
+		return s.invalidOpenAPIRefError(err.Error())
+	}
+
+	if !found {
+		return s.invalidOpenAPIRefError(fmt.Sprintf("%s extension is required to generate the backends from the tags", helper.OpenAPIExtension3scaleBackends))
+	}
+
+	tags := make([]string, 0, len(backendTags))
+	for tag := range backendTags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	suffixes := map[string]bool{}
+	for _, tag := range tags {
+		backendTag := backendTags[tag]
+
+		suffix := strings.ToLower(helper.NonAlphanumRegexp.ReplaceAllString(tag, ""))
+		if suffix == "" || suffixes[suffix] {
+			return s.invalidOpenAPIRefError(fmt.Sprintf("%s: tag %s: cannot generate a unique backend name", helper.OpenAPIExtension3scaleBackends, tag))
+		}
+		suffixes[suffix] = true
+
+		backendURL, err := url.Parse(backendTag.PrivateBaseURL)
+		if err != nil {
+			return s.invalidOpenAPIRefError(fmt.Sprintf("%s: tag %s: %s", helper.OpenAPIExtension3scaleBackends, tag, err.Error()))
+		}
+
+		privateBaseURL, backendPath, err := openAPIBackendURL(backendURL)
+		if err != nil {
+			return s.invalidOpenAPIRefError(fmt.Sprintf("%s: tag %s: %s", helper.OpenAPIExtension3scaleBackends, tag, err.Error()))
+		}
+
+		if backendTag.Path != "" {
+			if !strings.HasPrefix(backendTag.Path, "/") {
+				return s.invalidOpenAPIRefError(fmt.Sprintf("%s: tag %s: path must begin with a slash", helper.OpenAPIExtension3scaleBackends, tag))
+			}
+			backendPath = backendTag.Path
+		}
+
+		backend := s.newBackend(suffix, fmt.Sprintf("tag %s", tag))
+		backend.PrivateBaseURL = privateBaseURL
+		backend.Path = backendPath
+		backend.tag = tag
+		s.backends = append(s.backends, backend)
+	}
+
+	if !s.defaultBackendRequired() {
+		return nil
+	}
+
+	return s.addDefaultBackend()
+}
+
+// defaultBackendRequired returns true when some operation does not have any tag with backend
+func (s *openAPIBackendSplitter) defaultBackendRequired() bool {
+	for _, pathItem := range s.openapiObj.Paths {
+		for _, operation := range pathItem.Operations() {
+			tagged := false
+			for _, tag := range operation.Tags {
+				for _, backend := range s.backends {
+					tagged = tagged || backend.tag == tag
+				}
+			}
+
+			if !tagged {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// addDefaultBackend adds the backend generated from the first server
+func (s *openAPIBackendSplitter) addDefaultBackend() error {
+	backend := s.newBackend("", "")
+	backend.Path = "/"
+
+	if s.openapiCR.Spec.PrivateBaseURL != nil {
+		backend.PrivateBaseURL = *s.openapiCR.Spec.PrivateBaseURL
+	} else {
+		privateBaseURL, err := helper.BaseURLFromOpenAPI(s.openapiObj)
+		if err != nil {
+			return s.invalidOpenAPIRefError(err.Error())
+		}
+		backend.PrivateBaseURL = privateBaseURL
+	}
+
+	s.backends = append(s.backends, backend)
+	return nil
+}
+
+// newBackend returns a backend with the names of the backend generated for the given name suffix.
+// The default backend has an empty suffix.
+func (s *openAPIBackendSplitter) newBackend(suffix, source string) *openAPIBackend {
+	title := s.openapiObj.Info.Title
+
+	// Same as product system name
+	systemName := helper.SystemNameFromOpenAPITitle(s.openapiObj)
+	if s.openapiCR.Spec.ProductSystemName != nil {
+		systemName = *s.openapiCR.Spec.ProductSystemName
+	}
+
+	// DNS1123 Label compliant name. Due to UIDs are 36 characters of length this
+	// means that the maximum prefix lenght that can be provided is of 26
+	// characters. If the generated name is not DNS1123 compliant an error is
+	// returned
+	k8sName := helper.K8sNameFromOpenAPITitle(s.openapiObj)
+
+	if suffix == "" {
+		return &openAPIBackend{
+			ObjName:     fmt.Sprintf("%s-%s", k8sName, string(s.openapiCR.UID)),
+			SystemName:  systemName,
+			Name:        fmt.Sprintf("%s Backend", title),
+			Description: fmt.Sprintf("Backend of %s", title),
+		}
+	}
+
+	return &openAPIBackend{
+		ObjName:     fmt.Sprintf("%s-%s-%s", k8sName, suffix, string(s.openapiCR.UID)),
+		SystemName:  fmt.Sprintf("%s_%s", systemName, suffix),
+		Name:        fmt.Sprintf("%s %s Backend", title, suffix),
+		Description: fmt.Sprintf("Backend of %s for %s", title, source),
+	}
+}
+
+func (s *openAPIBackendSplitter) invalidOpenAPIRefError(msg string) error {
+	fieldErrors := field.ErrorList{}
+	specFldPath := field.NewPath("spec")
+	openapiRefFldPath := specFldPath.Child("openapiRef")
+	fieldErrors = append(fieldErrors, field.Invalid(openapiRefFldPath, s.openapiCR.Spec.OpenAPIRef, msg))
+	return &helper.SpecFieldError{
+		ErrorType:      helper.InvalidError,
+		FieldErrorList: fieldErrors,
+	}
+}
+
+// openAPIBackendURL returns the private base URL and the backend usage path of the backend URL.
+// The backend usage path is the URL path, so request paths are not modified by the API gateway.
+func openAPIBackendURL(backendURL *url.URL) (string, string, error) {
+	if backendURL.Host == "" {
+		return "", "", fmt.Errorf("absolute URL required")
+	}
+
+	scheme := "https"
+	if backendURL.Scheme != "" {
+		scheme = backendURL.Scheme
+	}
+
+	backendPath := LastSlashRegexp.ReplaceAllString(backendURL.Path, "")
+
+	privateBaseURL := fmt.Sprintf("%s://%s%s", scheme, backendURL.Host, backendPath)
+
+	if backendPath == "" {
+		backendPath = "/"
+	}
+
+	return privateBaseURL, backendPath, nil
+}
+
+// operationServerURL returns the server URL declared at operation or path item level
+func operationServerURL(pathItem *openapi3.PathItem, operation *openapi3.Operation) string {
+	if operation.Servers != nil && len(*operation.Servers) > 0 {
+		return (*operation.Servers)[0].URL
+	}
+
+	if len(pathItem.Servers) > 0 {
+		return pathItem.Servers[0].URL
+	}
+
+	return ""
+}
@@ -0,0 +1,374 @@
+package controllers
+
+import (
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	logrtesting "github.com/go-logr/logr/testing"
+)
+
+const testOpenAPIServersDoc = `
+openapi: 3.0.2
+info:
+  title: Petstore
+  version: "1.0"
+servers:
+  - url: https://pets.example.com/v1
+  - url: https://stores.example.com/v2/
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: pets
+  /stores:
+    servers:
+      - url: https://stores.example.com/v2/
+    get:
+      operationId: listStores
+      responses:
+        "200":
+          description: stores
+`
+
+const testOpenAPITagsDoc = `
+openapi: 3.0.2
+info:
+  title: Petstore
+  version: "1.0"
+servers:
+  - url: https://api.example.com/v1
+x-3scale-backends:
+  pets:
+    privateBaseURL: https://pets.example.com/v1/pets
+  Stores:
+    privateBaseURL: https://stores.example.com
+    path: /v1/stores
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      responses:
+        "200":
+          description: pets
+  /stores:
+    get:
+      operationId: listStores
+      tags: [other, Stores]
+      responses:
+        "200":
+          description: stores
+  /users:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: users
+`
+
+func newTestSplitterOpenAPIObj(t *testing.T, doc string) *openapi3.Swagger {
+	t.Helper()
+	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return openapiObj
+}
+
+func TestOpenAPIBackendSplitter(t *testing.T) {
+	serversMode := capabilitiesv1beta1.OpenAPIBackendSplitModeServers
+	tagsMode := capabilitiesv1beta1.OpenAPIBackendSplitModeTags
+
+	type expectedBackend struct {
+		systemName     string
+		privateBaseURL string
+		path           string
+	}
+
+	cases := []struct {
+		name     string
+		mode     *string
+		doc      string
+		backends []expectedBackend
+		// expected backend system name by operation path
+		operations map[string]string
+	}{
+		{"default", nil, testOpenAPIServersDoc,
+			[]expectedBackend{
+				{"petstore", "https://pets.example.com", "/"},
+			},
+			map[string]string{"/pets": "petstore", "/stores": "petstore"},
+		},
+		{"servers", &serversMode, testOpenAPIServersDoc,
+			[]expectedBackend{
+				{"petstore_server0", "https://pets.example.com/v1", "/v1"},
+				{"petstore_server1", "https://stores.example.com/v2", "/v2"},
+			},
+			map[string]string{"/pets": "petstore_server0", "/stores": "petstore_server1"},
+		},
+		{"tags with default backend", &tagsMode, testOpenAPITagsDoc,
+			[]expectedBackend{
+				{"petstore_stores", "https://stores.example.com", "/v1/stores"},
+				{"petstore_pets", "https://pets.example.com/v1/pets", "/v1/pets"},
+				{"petstore", "https://api.example.com", "/"},
+			},
+			map[string]string{"/pets": "petstore_pets", "/stores": "petstore_stores", "/users": "petstore"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			openapiCR := newTestOpenAPICR()
+			openapiCR.Spec.BackendSplitMode = tc.mode
+			openapiObj := newTestSplitterOpenAPIObj(subT, tc.doc)
+
+			splitter, err := newOpenAPIBackendSplitter(openapiCR, openapiObj)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			backends := splitter.Backends()
+			if len(backends) != len(tc.backends) {
+				subT.Fatalf("expected %d backends, got %d", len(tc.backends), len(backends))
+			}
+			for idx, expected := range tc.backends {
+				if backends[idx].SystemName != expected.systemName ||
+					backends[idx].PrivateBaseURL != expected.privateBaseURL ||
+					backends[idx].Path != expected.path {
+					subT.Errorf("backend %d: expected %v, got %v", idx, expected, *backends[idx])
+				}
+			}
+
+			for path, expectedSystemName := range tc.operations {
+				pathItem := openapiObj.Paths[path]
+				backend, err := splitter.OperationBackend(path, "get", pathItem, pathItem.Get)
+				if err != nil {
+					subT.Fatal(err)
+				}
+				if backend.SystemName != expectedSystemName {
+					subT.Errorf("operation %s: expected backend %s, got %s", path, expectedSystemName, backend.SystemName)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAPIBackendSplitterTagsWithoutDefaultBackend(t *testing.T) {
+	tagsMode := capabilitiesv1beta1.OpenAPIBackendSplitModeTags
+	openapiCR := newTestOpenAPICR()
+	openapiCR.Spec.BackendSplitMode = &tagsMode
+
+	openapiObj := newTestSplitterOpenAPIObj(t, testOpenAPITagsDoc)
+	delete(openapiObj.Paths, "/users")
+
+	splitter, err := newOpenAPIBackendSplitter(openapiCR, openapiObj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, backend := range splitter.Backends() {
+		if backend.tag == "" {
+			t.Fatalf("default backend generated when all the operations have backend tags: %v", *backend)
+		}
+	}
+}
+
+func TestOpenAPIBackendSplitterErrors(t *testing.T) {
+	serversMode := capabilitiesv1beta1.OpenAPIBackendSplitModeServers
+	tagsMode := capabilitiesv1beta1.OpenAPIBackendSplitModeTags
+
+	cases := []struct {
+		name string
+		mode *string
+		doc  string
+	}{
+		{"servers mode without servers", &serversMode, `
+openapi: 3.0.2
+info: {title: Petstore, version: "1.0"}
+paths: {}
+`},
+		{"servers mode with relative server", &serversMode, `
+openapi: 3.0.2
+info: {title: Petstore, version: "1.0"}
+servers:
+  - url: /v1
+paths: {}
+`},
+		{"servers with the same path", &serversMode, `
+openapi: 3.0.2
+info: {title: Petstore, version: "1.0"}
+servers:
+  - url: https://pets.example.com/v1
+  - url: https://stores.example.com/v1
+paths: {}
+`},
+		{"tags mode without backends extension", &tagsMode, testOpenAPIServersDoc},
+		{"tags mode with relative path", &tagsMode, `
+openapi: 3.0.2
+info: {title: Petstore, version: "1.0"}
+x-3scale-backends:
+  pets:
+    privateBaseURL: https://pets.example.com
+    path: pets
+paths: {}
+`},
+		{"tags without unique backend names", &tagsMode, `
+openapi: 3.0.2
+info: {title: Petstore, version: "1.0"}
+x-3scale-backends:
+  pets:
+    privateBaseURL: https://pets.example.com
+    path: /pets
+  Pets:
+    privateBaseURL: https://pets2.example.com
+    path: /pets2
+paths: {}
+`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			openapiCR := newTestOpenAPICR()
+			openapiCR.Spec.BackendSplitMode = tc.mode
+
+			_, err := newOpenAPIBackendSplitter(openapiCR, newTestSplitterOpenAPIObj(subT, tc.doc))
+			if !helper.IsInvalidSpecError(err) {
+				subT.Fatalf("invalid spec error expected, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOpenAPIBackendSplitterUndeclaredServer(t *testing.T) {
+	serversMode := capabilitiesv1beta1.OpenAPIBackendSplitModeServers
+	openapiCR := newTestOpenAPICR()
+	openapiCR.Spec.BackendSplitMode = &serversMode
+
+	openapiObj := newTestSplitterOpenAPIObj(t, testOpenAPIServersDoc)
+	splitter, err := newOpenAPIBackendSplitter(openapiCR, openapiObj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pathItem := openapiObj.Paths["/pets"]
+	pathItem.Servers = openapi3.Servers{{URL: "https://other.example.com"}}
+	_, err = splitter.OperationBackend("/pets", "get", pathItem, pathItem.Get)
+	if !helper.IsInvalidSpecError(err) {
+		t.Fatalf("invalid spec error expected, got %v", err)
+	}
+}
+
+func TestOpenAPIProductReconcilerMappingRulesPartitioning(t *testing.T) {
+	serversMode := capabilitiesv1beta1.OpenAPIBackendSplitModeServers
+	tagsMode := capabilitiesv1beta1.OpenAPIBackendSplitModeTags
+
+	cases := []struct {
+		name string
+		mode *string
+		doc  string
+		// expected mapping rule pattern by metric method ref
+		patterns map[string]string
+	}{
+		{"servers", &serversMode, testOpenAPIServersDoc,
+			map[string]string{"listpets": "/v1/pets$", "liststores": "/v2/stores$"}},
+		{"tags", &tagsMode, testOpenAPITagsDoc,
+			map[string]string{"listpets": "/v1/pets$", "liststores": "/v1/stores$", "listusers": "/v1/users$"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			openapiCR := newTestOpenAPICR()
+			openapiCR.Spec.BackendSplitMode = tc.mode
+			openapiObj := newTestSplitterOpenAPIObj(subT, tc.doc)
+
+			splitter, err := newOpenAPIBackendSplitter(openapiCR, openapiObj)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			reconciler := NewOpenAPIProductReconciler(newTestBaseReconciler(subT, openapiCR), openapiCR, openapiObj, nil, logrtesting.NullLogger{})
+			mappingRules, err := reconciler.desiredMappingRules(splitter)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if len(mappingRules) != len(tc.patterns) {
+				subT.Fatalf("unexpected mapping rules %v", mappingRules)
+			}
+			for _, mappingRule := range mappingRules {
+				if tc.patterns[mappingRule.MetricMethodRef] != mappingRule.Pattern {
+					subT.Errorf("mapping rule %s: expected pattern %s, got %s", mappingRule.MetricMethodRef, tc.patterns[mappingRule.MetricMethodRef], mappingRule.Pattern)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAPIProductReconcilerMappingRulesOutsideBackendPath(t *testing.T) {
+	tagsMode := capabilitiesv1beta1.OpenAPIBackendSplitModeTags
+	openapiCR := newTestOpenAPICR()
+	openapiCR.Spec.BackendSplitMode = &tagsMode
+
+	// the /v1 backend path does not include the /v10/pets pattern
+	openapiObj := newTestSplitterOpenAPIObj(t, `
+openapi: 3.0.2
+info: {title: Petstore, version: "1.0"}
+servers:
+  - url: https://api.example.com/v10
+x-3scale-backends:
+  pets:
+    privateBaseURL: https://pets.example.com
+    path: /v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      responses:
+        "200":
+          description: pets
+`)
+
+	splitter, err := newOpenAPIBackendSplitter(openapiCR, openapiObj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reconciler := NewOpenAPIProductReconciler(newTestBaseReconciler(t, openapiCR), openapiCR, openapiObj, nil, logrtesting.NullLogger{})
+	_, err = reconciler.desiredMappingRules(splitter)
+	if !helper.IsInvalidSpecError(err) {
+		t.Fatalf("invalid spec error expected, got %v", err)
+	}
+}
+
+func TestPatternInBackendPath(t *testing.T) {
+	cases := []struct {
+		pattern     string
+		backendPath string
+		expected    bool
+	}{
+		{"/pets$", "/", true},
+		{"/v1", "/v1", true},
+		{"/v1$", "/v1", true},
+		{"/v1/pets$", "/v1", true},
+		{"/v1/pets$", "/v1/", true},
+		{"/v10/pets$", "/v1", false},
+		{"/v1pets$", "/v1", false},
+		{"/v1$", "/v1/pets", false},
+		{"/v2/pets$", "/v1", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.pattern+" "+tc.backendPath, func(subT *testing.T) {
+			if patternInBackendPath(tc.pattern, tc.backendPath) != tc.expected {
+				subT.Errorf("expected %t", tc.expected)
+			}
+		})
+	}
+}
@@ -21,14 +21,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"path"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
@@ -38,6 +42,13 @@ import (
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
+// Indexes of the secrets and configmaps the OpenAPI documents are read from.
+// Index values are namespaced names, changes of those objects trigger the reconciliation of the OpenAPI CRs
+const (
+	openapiSourceSecretsIndex    = "openapi.capabilities.3scale.net/source-secrets"
+	openapiSourceConfigMapsIndex = "openapi.capabilities.3scale.net/source-configmaps"
+)
+
 // OpenAPIReconciler reconciles a OpenAPI object
 type OpenAPIReconciler struct {
 	*reconcilers.BaseReconciler
@@ -113,6 +124,10 @@ func (r *OpenAPIReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			// On Validation error, no need to retry as spec is not valid and needs to be changed
 			reqLogger.Info("ERROR", "spec validation error", reconcileErr)
 			r.EventRecorder().Eventf(openapiCR, corev1.EventTypeWarning, "Invalid OpenAPI Spec", "%v", reconcileErr)
+			if openapiCR.RefreshRequired() {
+				// The OpenAPI document might be fixed at the URL source
+				return ctrl.Result{RequeueAfter: openapiCR.Spec.RefreshInterval.Duration}, nil
+			}
 			return ctrl.Result{}, nil
 		}
 
@@ -125,50 +140,174 @@ func (r *OpenAPIReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 }
 
 func (r *OpenAPIReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.TODO(), &capabilitiesv1beta1.OpenAPI{}, openapiSourceSecretsIndex, openapiSourceSecrets)
+	if err != nil {
+		return err
+	}
+
+	err = mgr.GetFieldIndexer().IndexField(context.TODO(), &capabilitiesv1beta1.OpenAPI{}, openapiSourceConfigMapsIndex, openapiSourceConfigMaps)
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&capabilitiesv1beta1.OpenAPI{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: r.openapiSourceEventMapper(openapiSourceSecretsIndex),
+		}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: r.openapiSourceEventMapper(openapiSourceConfigMapsIndex),
+		}).
+		Watches(&source.Kind{Type: &capabilitiesv1beta1.CustomPolicyDefinition{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.customPolicyDefinitionEventMapper),
+		}).
 		Complete(r)
 }
 
+// customPolicyDefinitionEventMapper maps custom policy definition events to the OpenAPI CRs in the same namespace.
+// The policy chain of the OpenAPI documents may reference the custom policy
+func (r *OpenAPIReconciler) customPolicyDefinitionEventMapper(mapObject handler.MapObject) []reconcile.Request {
+	openapiList := &capabilitiesv1beta1.OpenAPIList{}
+	err := r.Client().List(context.TODO(), openapiList, client.InNamespace(mapObject.Meta.GetNamespace()))
+	if err != nil {
+		r.Logger().Error(err, "Failed to list OpenAPI CRs", "namespace", mapObject.Meta.GetNamespace())
+		return nil
+	}
+
+	return openapiRequests(openapiList)
+}
+
+// openapiSourceEventMapper maps secret or configmap events to the OpenAPI CRs reading from them
+func (r *OpenAPIReconciler) openapiSourceEventMapper(index string) handler.ToRequestsFunc {
+	return func(mapObject handler.MapObject) []reconcile.Request {
+		sourceKey := types.NamespacedName{Name: mapObject.Meta.GetName(), Namespace: mapObject.Meta.GetNamespace()}
+
+		openapiList := &capabilitiesv1beta1.OpenAPIList{}
+		err := r.Client().List(context.TODO(), openapiList, client.MatchingFields{index: sourceKey.String()})
+		if err != nil {
+			r.Logger().Error(err, "Failed to list OpenAPI CRs", "source", sourceKey)
+			return nil
+		}
+
+		return openapiRequests(openapiList)
+	}
+}
+
+func openapiRequests(openapiList *capabilitiesv1beta1.OpenAPIList) []reconcile.Request {
+	requests := make([]reconcile.Request, 0, len(openapiList.Items))
+	for idx := range openapiList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Name:      openapiList.Items[idx].Name,
+			Namespace: openapiList.Items[idx].Namespace,
+		}})
+	}
+
+	return requests
+}
+
+// openapiSourceSecrets returns the namespaced names of the secrets the OpenAPI document is read from
+func openapiSourceSecrets(obj runtime.Object) []string {
+	openapiCR, ok := obj.(*capabilitiesv1beta1.OpenAPI)
+	if !ok {
+		return nil
+	}
+
+	var keys []string
+	if openapiCR.Spec.OpenAPIRef.SecretRef != nil {
+		keys = append(keys, openapiSourceKey(openapiCR, openapiCR.Spec.OpenAPIRef.SecretRef.Namespace, openapiCR.Spec.OpenAPIRef.SecretRef.Name))
+	}
+
+	for _, externalRefSource := range openapiCR.Spec.OpenAPIRef.ExternalRefSources {
+		if externalRefSource.SecretRef != nil {
+			keys = append(keys, openapiSourceKey(openapiCR, "", externalRefSource.SecretRef.Name))
+		}
+	}
+
+	return keys
+}
+
+// openapiSourceConfigMaps returns the namespaced names of the configmaps the OpenAPI document is read from
+func openapiSourceConfigMaps(obj runtime.Object) []string {
+	openapiCR, ok := obj.(*capabilitiesv1beta1.OpenAPI)
+	if !ok {
+		return nil
+	}
+
+	var keys []string
+	if openapiCR.Spec.OpenAPIRef.ConfigMapRef != nil {
+		keys = append(keys, openapiSourceKey(openapiCR, openapiCR.Spec.OpenAPIRef.ConfigMapRef.Namespace, openapiCR.Spec.OpenAPIRef.ConfigMapRef.Name))
+	}
+
+	for _, externalRefSource := range openapiCR.Spec.OpenAPIRef.ExternalRefSources {
+		if externalRefSource.ConfigMapRef != nil {
+			keys = append(keys, openapiSourceKey(openapiCR, "", externalRefSource.ConfigMapRef.Name))
+		}
+	}
+
+	return keys
+}
+
+// openapiSourceKey returns the namespaced name of the source object, in the OpenAPI CR namespace when not set
+func openapiSourceKey(openapiCR *capabilitiesv1beta1.OpenAPI, namespace, name string) string {
+	if namespace == "" {
+		namespace = openapiCR.Namespace
+	}
+
+	return types.NamespacedName{Name: name, Namespace: namespace}.String()
+}
+
 func (r *OpenAPIReconciler) reconcileSpec(openapiCR *capabilitiesv1beta1.OpenAPI) (*OpenAPIStatusReconciler, ctrl.Result, error) {
 	logger := r.Logger().WithValues("openapi", openapiCR.Name)
 
 	err := r.validateSpec(openapiCR)
 	if err != nil {
-		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, "", err, false)
+		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, "", "", nil, err, false)
 		return statusReconciler, ctrl.Result{}, err
 	}
 
-	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), openapiCR.Namespace, openapiCR.Spec.ProviderAccountRef, logger)
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), openapiCR.Namespace, openapiCR.Spec.ProviderAccountRef, openapiCR.Spec.ProviderAccountNamespace, openapiCR.Spec.TenantRef, logger)
 	if err != nil {
-		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, "", err, false)
+		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, "", "", nil, err, false)
 		return statusReconciler, ctrl.Result{}, err
 	}
 
-	openapiObj, err := r.readOpenAPI(openapiCR)
+	openapiObj, openapiDigest, conversionWarnings, err := r.readOpenAPI(openapiCR)
 	if err != nil {
-		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, err, false)
+		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, "", nil, err, false)
 		return statusReconciler, ctrl.Result{}, err
 	}
 
-	err = r.validateOpenAPIAs3scaleProduct(openapiCR, openapiObj)
+	securityWarnings, err := r.validateOpenAPIAs3scaleProduct(openapiCR, openapiObj)
 	if err != nil {
-		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, err, false)
+		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, openapiDigest, conversionWarnings, err, false)
 		return statusReconciler, ctrl.Result{}, err
 	}
 
-	backendReconciler := NewOpenAPIBackendReconciler(r.BaseReconciler, openapiCR, openapiObj, providerAccount, logger)
-	_, err = backendReconciler.Reconcile()
-	if err != nil {
-		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, err, false)
-		return statusReconciler, ctrl.Result{}, err
-	}
+	warnings := append(conversionWarnings, securityWarnings...)
 
-	productReconciler := NewOpenAPIProductReconciler(r.BaseReconciler, openapiCR, openapiObj, providerAccount, logger)
-	_, err = productReconciler.Reconcile()
-	if err != nil {
-		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, err, false)
-		return statusReconciler, ctrl.Result{}, err
+	if r.regenerationRequired(openapiCR, openapiDigest) {
+		backendReconciler := NewOpenAPIBackendReconciler(r.BaseReconciler, openapiCR, openapiObj, providerAccount, logger)
+		_, err = backendReconciler.Reconcile()
+		if err != nil {
+			statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, openapiDigest, warnings, err, false)
+			return statusReconciler, ctrl.Result{}, err
+		}
+
+		productReconciler := NewOpenAPIProductReconciler(r.BaseReconciler, openapiCR, openapiObj, providerAccount, logger)
+		_, err = productReconciler.Reconcile()
+		if err != nil {
+			statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, openapiDigest, warnings, err, false)
+			return statusReconciler, ctrl.Result{}, err
+		}
+
+		activeDocReconciler := NewOpenAPIActiveDocReconciler(r.BaseReconciler, openapiCR, openapiObj, openapiDigest, logger)
+		err = activeDocReconciler.Reconcile()
+		if err != nil {
+			statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, openapiDigest, warnings, err, false)
+			return statusReconciler, ctrl.Result{}, err
+		}
+	} else {
+		logger.V(1).Info("OpenAPI document content not changed, skipping product and backend update", "digest", openapiDigest)
 	}
 
 	// No need to check for backend sync state.
@@ -177,14 +316,31 @@ func (r *OpenAPIReconciler) reconcileSpec(openapiCR *capabilitiesv1beta1.OpenAPI
 	// The product controller makes sure the backend usage's items are valid Backend CRs and are sync'ed.
 	productSynced, err := r.checkProductSynced(openapiCR)
 	if err != nil {
-		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, err, false)
+		statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, openapiDigest, warnings, err, false)
 		return statusReconciler, ctrl.Result{}, err
 	}
 
-	statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, err, productSynced)
+	statusReconciler := NewOpenAPIStatusReconciler(r.BaseReconciler, openapiCR, providerAccount.AdminURLStr, openapiDigest, warnings, err, productSynced)
+
+	if productSynced && openapiCR.RefreshRequired() {
+		// fetch the OpenAPI document again after the refresh interval
+		return statusReconciler, ctrl.Result{RequeueAfter: openapiCR.Spec.RefreshInterval.Duration}, err
+	}
+
 	return statusReconciler, ctrl.Result{Requeue: !productSynced}, err
 }
 
+// regenerationRequired returns true when the product, the backend and the activedoc need to be reconciled.
+// They are not reconciled again when neither the OpenAPI document content
+// nor the spec have changed since the latest successful reconciliation
+func (r *OpenAPIReconciler) regenerationRequired(resource *capabilitiesv1beta1.OpenAPI, openapiDigest string) bool {
+	return resource.Status.OpenAPIDigest != openapiDigest ||
+		resource.Generation != resource.Status.ObservedGeneration ||
+		resource.Status.ProductResourceName == nil ||
+		(resource.Spec.ActiveDoc != nil && resource.Status.ActiveDocResourceName == nil) ||
+		!resource.Status.Conditions.IsTrueFor(capabilitiesv1beta1.OpenAPIReadyConditionType)
+}
+
 func (r *OpenAPIReconciler) validateSpec(resource *capabilitiesv1beta1.OpenAPI) error {
 	errors := field.ErrorList{}
 	errors = append(errors, resource.Validate()...)
@@ -220,140 +376,283 @@ func (r *OpenAPIReconciler) checkProductSynced(resource *capabilitiesv1beta1.Ope
 	return product.Status.Conditions.IsTrueFor(capabilitiesv1beta1.ProductSyncedConditionType), nil
 }
 
-func (r *OpenAPIReconciler) readOpenAPI(resource *capabilitiesv1beta1.OpenAPI) (*openapi3.Swagger, error) {
+// readOpenAPI returns the OpenAPI document, the digest of the document content
+// and the warnings of the OpenAPI 2.0 document conversion
+func (r *OpenAPIReconciler) readOpenAPI(resource *capabilitiesv1beta1.OpenAPI) (*openapi3.Swagger, string, []string, error) {
 	// OpenAPIRef is oneOf by CRD openapiV3 validation
 	if resource.Spec.OpenAPIRef.SecretRef != nil {
 		return r.readOpenAPISecret(resource)
 	}
 
+	if resource.Spec.OpenAPIRef.ConfigMapRef != nil {
+		return r.readOpenAPIConfigMap(resource)
+	}
+
 	// Must be URL
 	return r.readOpenAPIFromURL(resource)
 }
 
-func (r *OpenAPIReconciler) readOpenAPISecret(resource *capabilitiesv1beta1.OpenAPI) (*openapi3.Swagger, error) {
+func (r *OpenAPIReconciler) readOpenAPISecret(resource *capabilitiesv1beta1.OpenAPI) (*openapi3.Swagger, string, []string, error) {
 	fieldErrors := field.ErrorList{}
 	specFldPath := field.NewPath("spec")
 	openapiRefFldPath := specFldPath.Child("openapiRef")
 	secretRefFldPath := openapiRefFldPath.Child("secretRef")
 
 	objectKey := types.NamespacedName{Name: resource.Spec.OpenAPIRef.SecretRef.Name, Namespace: resource.Spec.OpenAPIRef.SecretRef.Namespace}
-	openapiSecretObj := &corev1.Secret{}
 
 	// Read secret
-	if err := r.Client().Get(r.Context(), objectKey, openapiSecretObj); err != nil {
+	data, err := r.readSecretData(objectKey)
+	if err != nil {
 		if errors.IsNotFound(err) {
 			fieldErrors = append(fieldErrors, field.Invalid(secretRefFldPath, resource.Spec.OpenAPIRef.SecretRef, "Secret not found"))
-			return nil, &helper.SpecFieldError{
+			return nil, "", nil, &helper.SpecFieldError{
 				ErrorType:      helper.InvalidError,
 				FieldErrorList: fieldErrors,
 			}
 		}
 
 		// unexpected error
-		return nil, err
+		return nil, "", nil, err
 	}
 
-	if len(openapiSecretObj.Data) != 1 {
-		fieldErrors = append(fieldErrors, field.Invalid(secretRefFldPath, resource.Spec.OpenAPIRef.SecretRef, "Secret was empty or contains too many fields. Only one is required."))
-		return nil, &helper.SpecFieldError{
+	return r.loadOpenAPIFromObjectData(resource, secretRefFldPath, resource.Spec.OpenAPIRef.SecretRef, "Secret", data)
+}
+
+func (r *OpenAPIReconciler) readOpenAPIConfigMap(resource *capabilitiesv1beta1.OpenAPI) (*openapi3.Swagger, string, []string, error) {
+	fieldErrors := field.ErrorList{}
+	specFldPath := field.NewPath("spec")
+	openapiRefFldPath := specFldPath.Child("openapiRef")
+	configMapRefFldPath := openapiRefFldPath.Child("configMapRef")
+
+	objectKey := types.NamespacedName{Name: resource.Spec.OpenAPIRef.ConfigMapRef.Name, Namespace: resource.Spec.OpenAPIRef.ConfigMapRef.Namespace}
+
+	// Read configmap
+	data, err := r.readConfigMapData(objectKey)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			fieldErrors = append(fieldErrors, field.Invalid(configMapRefFldPath, resource.Spec.OpenAPIRef.ConfigMapRef, "ConfigMap not found"))
+			return nil, "", nil, &helper.SpecFieldError{
+				ErrorType:      helper.InvalidError,
+				FieldErrorList: fieldErrors,
+			}
+		}
+
+		// unexpected error
+		return nil, "", nil, err
+	}
+
+	return r.loadOpenAPIFromObjectData(resource, configMapRefFldPath, resource.Spec.OpenAPIRef.ConfigMapRef, "ConfigMap", data)
+}
+
+// loadOpenAPIFromObjectData loads the OpenAPI document from the secret or configmap data.
+// The other keys of the object are available as files to resolve external references.
+func (r *OpenAPIReconciler) loadOpenAPIFromObjectData(resource *capabilitiesv1beta1.OpenAPI, fldPath *field.Path, fldValue interface{}, kind string, data map[string][]byte) (*openapi3.Swagger, string, []string, error) {
+	fieldErrors := field.ErrorList{}
+	specFldPath := field.NewPath("spec")
+	documentKeyFldPath := specFldPath.Child("openapiRef").Child("documentKey")
+
+	var documentKey string
+	if resource.Spec.OpenAPIRef.DocumentKey != nil {
+		documentKey = *resource.Spec.OpenAPIRef.DocumentKey
+		if _, ok := data[documentKey]; !ok {
+			fieldErrors = append(fieldErrors, field.Invalid(documentKeyFldPath, documentKey, fmt.Sprintf("%s does not contain the key", kind)))
+			return nil, "", nil, &helper.SpecFieldError{
+				ErrorType:      helper.InvalidError,
+				FieldErrorList: fieldErrors,
+			}
+		}
+	} else {
+		if len(data) != 1 {
+			fieldErrors = append(fieldErrors, field.Invalid(fldPath, fldValue, fmt.Sprintf("%s was empty or contains too many fields. Only one is required when documentKey is not set.", kind)))
+			return nil, "", nil, &helper.SpecFieldError{
+				ErrorType:      helper.InvalidError,
+				FieldErrorList: fieldErrors,
+			}
+		}
+
+		for key := range data {
+			documentKey = key
+		}
+	}
+
+	vfs := helper.OpenAPIVirtualFS{}
+	for key, value := range data {
+		vfs.Add("/", key, value)
+	}
+
+	return r.loadOpenAPI(resource, fldPath, fldValue, data[documentKey], documentKey, vfs)
+}
+
+// loadOpenAPI resolves the external references from the virtual filesystem, converts OpenAPI 2.0 documents,
+// then parses and validates the OpenAPI document.
+// The digest is calculated from the resolved document content.
+func (r *OpenAPIReconciler) loadOpenAPI(resource *capabilitiesv1beta1.OpenAPI, fldPath *field.Path, fldValue interface{}, data []byte, documentPath string, vfs helper.OpenAPIVirtualFS) (*openapi3.Swagger, string, []string, error) {
+	fieldErrors := field.ErrorList{}
+
+	err := r.addOpenAPIExternalRefSources(resource, vfs)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	data, err = helper.ResolveOpenAPIExternalRefs(data, documentPath, vfs)
+	if err != nil {
+		fieldErrors = append(fieldErrors, field.Invalid(fldPath, fldValue, err.Error()))
+		return nil, "", nil, &helper.SpecFieldError{
 			ErrorType:      helper.InvalidError,
 			FieldErrorList: fieldErrors,
 		}
 	}
 
-	// Get key value
-	dataByteArray := func(secret *corev1.Secret) []byte {
-		for _, v := range secret.Data {
-			return v
+	// OpenAPI 2.0 documents are converted to OpenAPI 3.0
+	openapiData, conversionWarnings, err := helper.ConvertOpenAPIv2(data)
+	if err != nil {
+		fieldErrors = append(fieldErrors, field.Invalid(fldPath, fldValue, err.Error()))
+		return nil, "", nil, &helper.SpecFieldError{
+			ErrorType:      helper.InvalidError,
+			FieldErrorList: fieldErrors,
 		}
-		return nil
-	}(openapiSecretObj)
+	}
 
-	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData(dataByteArray)
+	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData(openapiData)
 	if err != nil {
-		fieldErrors = append(fieldErrors, field.Invalid(secretRefFldPath, resource.Spec.OpenAPIRef.SecretRef, err.Error()))
-		return nil, &helper.SpecFieldError{
+		fieldErrors = append(fieldErrors, field.Invalid(fldPath, fldValue, err.Error()))
+		return nil, "", nil, &helper.SpecFieldError{
 			ErrorType:      helper.InvalidError,
 			FieldErrorList: fieldErrors,
 		}
 	}
 
-	err = openapiObj.Validate(r.Context())
+	err = helper.ValidateOpenAPI(r.Context(), openapiObj)
 	if err != nil {
-		fieldErrors = append(fieldErrors, field.Invalid(secretRefFldPath, resource.Spec.OpenAPIRef.SecretRef, err.Error()))
-		return nil, &helper.SpecFieldError{
+		fieldErrors = append(fieldErrors, field.Invalid(fldPath, fldValue, err.Error()))
+		return nil, "", nil, &helper.SpecFieldError{
 			ErrorType:      helper.InvalidError,
 			FieldErrorList: fieldErrors,
 		}
 	}
 
-	return openapiObj, nil
+	return openapiObj, helper.OpenAPIDigest(data), conversionWarnings, nil
 }
 
-func (r *OpenAPIReconciler) validateOpenAPIAs3scaleProduct(openapiCR *capabilitiesv1beta1.OpenAPI, openapiObj *openapi3.Swagger) error {
+// addOpenAPIExternalRefSources adds the keys of the external reference sources to the virtual filesystem
+func (r *OpenAPIReconciler) addOpenAPIExternalRefSources(resource *capabilitiesv1beta1.OpenAPI, vfs helper.OpenAPIVirtualFS) error {
 	fieldErrors := field.ErrorList{}
 	specFldPath := field.NewPath("spec")
-	openapiRefFldPath := specFldPath.Child("openapiRef")
+	externalRefSourcesFldPath := specFldPath.Child("openapiRef").Child("externalRefSources")
 
-	// Multiple sec requirements
-	globalSecRequirements := helper.OpenAPIGlobalSecurityRequirements(openapiObj)
-	if len(globalSecRequirements) > 1 {
-		fieldErrors = append(fieldErrors, field.Invalid(openapiRefFldPath, openapiCR.Spec.OpenAPIRef, "Invalid OAS: multiple security requirements"))
-		return &helper.SpecFieldError{
-			ErrorType:      helper.InvalidError,
-			FieldErrorList: fieldErrors,
+	for idx, source := range resource.Spec.OpenAPIRef.ExternalRefSources {
+		var data map[string][]byte
+		var err error
+
+		if source.SecretRef != nil {
+			data, err = r.readSecretData(types.NamespacedName{Name: source.SecretRef.Name, Namespace: resource.Namespace})
+		} else {
+			data, err = r.readConfigMapData(types.NamespacedName{Name: source.ConfigMapRef.Name, Namespace: resource.Namespace})
 		}
-	}
 
-	if len(globalSecRequirements) == 1 {
-		// Validate supported types
-		switch globalSecRequirements[0].Value.Type {
-		case "apiKey":
-			break
-		default:
-			fieldErrors = append(fieldErrors, field.Invalid(openapiRefFldPath, openapiCR.Spec.OpenAPIRef, fmt.Sprintf("Unexpected security schema type: %s", globalSecRequirements[0].Value.Type)))
-			return &helper.SpecFieldError{
-				ErrorType:      helper.InvalidError,
-				FieldErrorList: fieldErrors,
+		if err != nil {
+			if errors.IsNotFound(err) {
+				fieldErrors = append(fieldErrors, field.Invalid(externalRefSourcesFldPath.Index(idx), source, "Not found"))
+				return &helper.SpecFieldError{
+					ErrorType:      helper.InvalidError,
+					FieldErrorList: fieldErrors,
+				}
 			}
+
+			// unexpected error
+			return err
+		}
+
+		for key, value := range data {
+			vfs.Add(source.MountPath, key, value)
 		}
 	}
 
 	return nil
 }
 
-func (r *OpenAPIReconciler) readOpenAPIFromURL(resource *capabilitiesv1beta1.OpenAPI) (*openapi3.Swagger, error) {
+func (r *OpenAPIReconciler) readSecretData(objectKey types.NamespacedName) (map[string][]byte, error) {
+	secret := &corev1.Secret{}
+	if err := r.Client().Get(r.Context(), objectKey, secret); err != nil {
+		return nil, err
+	}
+
+	return secret.Data, nil
+}
+
+func (r *OpenAPIReconciler) readConfigMapData(objectKey types.NamespacedName) (map[string][]byte, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := r.Client().Get(r.Context(), objectKey, configMap); err != nil {
+		return nil, err
+	}
+
+	data := map[string][]byte{}
+	for key, value := range configMap.BinaryData {
+		data[key] = value
+	}
+	for key, value := range configMap.Data {
+		data[key] = []byte(value)
+	}
+
+	return data, nil
+}
+
+// validateOpenAPIAs3scaleProduct returns the OpenAPI document features that cannot be imported as warnings
+func (r *OpenAPIReconciler) validateOpenAPIAs3scaleProduct(openapiCR *capabilitiesv1beta1.OpenAPI, openapiObj *openapi3.Swagger) ([]string, error) {
 	fieldErrors := field.ErrorList{}
 	specFldPath := field.NewPath("spec")
 	openapiRefFldPath := specFldPath.Child("openapiRef")
-	urlRefFldPath := openapiRefFldPath.Child("url")
 
-	openAPIURL, err := url.Parse(*resource.Spec.OpenAPIRef.URL)
+	globalSecRequirements := helper.OpenAPIGlobalSecurityRequirements(openapiObj)
+	secRequirement, err := helper.SelectOpenAPISecurityRequirement(globalSecRequirements, openapiCR.Spec.SecuritySchemeName)
 	if err != nil {
-		fieldErrors = append(fieldErrors, field.Invalid(urlRefFldPath, resource.Spec.OpenAPIRef.URL, err.Error()))
+		if openapiCR.Spec.SecuritySchemeName != nil {
+			fieldErrors = append(fieldErrors, field.Invalid(specFldPath.Child("securitySchemeName"), openapiCR.Spec.SecuritySchemeName, err.Error()))
+		} else {
+			fieldErrors = append(fieldErrors, field.Invalid(openapiRefFldPath, openapiCR.Spec.OpenAPIRef, err.Error()))
+		}
 		return nil, &helper.SpecFieldError{
 			ErrorType:      helper.InvalidError,
 			FieldErrorList: fieldErrors,
 		}
 	}
 
-	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromURI(openAPIURL)
-	if err != nil {
-		fieldErrors = append(fieldErrors, field.Invalid(urlRefFldPath, resource.Spec.OpenAPIRef.URL, err.Error()))
-		return nil, &helper.SpecFieldError{
-			ErrorType:      helper.InvalidError,
-			FieldErrorList: fieldErrors,
+	if secRequirement != nil {
+		switch secRequirement.Value.Type {
+		case helper.OpenAPISecuritySchemeTypeOAuth2, helper.OpenAPISecuritySchemeTypeOpenIDConnect:
+			if openapiCR.Spec.OIDC == nil {
+				fieldErrors = append(fieldErrors, field.Required(specFldPath.Child("oidc"), fmt.Sprintf("OIDC configuration required for security schema type: %s", secRequirement.Value.Type)))
+				return nil, &helper.SpecFieldError{
+					ErrorType:      helper.InvalidError,
+					FieldErrorList: fieldErrors,
+				}
+			}
 		}
 	}
 
-	err = openapiObj.Validate(r.Context())
+	return helper.OpenAPISecurityWarnings(openapiObj, secRequirement), nil
+}
+
+func (r *OpenAPIReconciler) readOpenAPIFromURL(resource *capabilitiesv1beta1.OpenAPI) (*openapi3.Swagger, string, []string, error) {
+	fieldErrors := field.ErrorList{}
+	specFldPath := field.NewPath("spec")
+	openapiRefFldPath := specFldPath.Child("openapiRef")
+	urlRefFldPath := openapiRefFldPath.Child("url")
+
+	openAPIURL, err := url.Parse(*resource.Spec.OpenAPIRef.URL)
 	if err != nil {
 		fieldErrors = append(fieldErrors, field.Invalid(urlRefFldPath, resource.Spec.OpenAPIRef.URL, err.Error()))
-		return nil, &helper.SpecFieldError{
+		return nil, "", nil, &helper.SpecFieldError{
 			ErrorType:      helper.InvalidError,
 			FieldErrorList: fieldErrors,
 		}
 	}
 
-	return openapiObj, nil
+	// fetch errors might be transient, retry
+	data, err := controllerhelper.FetchOpenAPIURL(r.Context(), r.Client(), resource.Namespace, openAPIURL, resource.Spec.OpenAPIRef.URLOptions)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("Failed to fetch OpenAPI document: %w", err)
+	}
+
+	return r.loadOpenAPI(resource, urlRefFldPath, resource.Spec.OpenAPIRef.URL, data, path.Base(openAPIURL.Path), helper.OpenAPIVirtualFS{})
 }
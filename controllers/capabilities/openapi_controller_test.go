@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newTestSourcesOpenAPI() *capabilitiesv1beta1.OpenAPI {
+	return &capabilitiesv1beta1.OpenAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "myopenapi", Namespace: "myns"},
+		Spec: capabilitiesv1beta1.OpenAPISpec{
+			OpenAPIRef: capabilitiesv1beta1.OpenAPIRefSpec{
+				ConfigMapRef: &corev1.ObjectReference{Name: "mydocument", Namespace: "otherns"},
+				ExternalRefSources: []capabilitiesv1beta1.OpenAPIExternalRefSourceSpec{
+					{ConfigMapRef: &corev1.LocalObjectReference{Name: "myschemas"}},
+					{SecretRef: &corev1.LocalObjectReference{Name: "myexamples"}, MountPath: "examples"},
+				},
+			},
+		},
+	}
+}
+
+func TestOpenAPISourceIndexes(t *testing.T) {
+	openapiCR := newTestSourcesOpenAPI()
+
+	configMaps := openapiSourceConfigMaps(openapiCR)
+	if !reflect.DeepEqual([]string{"otherns/mydocument", "myns/myschemas"}, configMaps) {
+		t.Errorf("unexpected configmaps %v", configMaps)
+	}
+
+	secrets := openapiSourceSecrets(openapiCR)
+	if !reflect.DeepEqual([]string{"myns/myexamples"}, secrets) {
+		t.Errorf("unexpected secrets %v", secrets)
+	}
+
+	// URL sources
+	openapiCR.Spec.OpenAPIRef = capabilitiesv1beta1.OpenAPIRefSpec{}
+	if keys := append(openapiSourceSecrets(openapiCR), openapiSourceConfigMaps(openapiCR)...); len(keys) != 0 {
+		t.Errorf("unexpected sources %v", keys)
+	}
+}
+
+func TestOpenAPISourceEventMapper(t *testing.T) {
+	openapiCR := newTestSourcesOpenAPI()
+	r := &OpenAPIReconciler{BaseReconciler: newTestBaseReconciler(t, openapiCR)}
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "myschemas", Namespace: "myns"}}
+	requests := r.openapiSourceEventMapper(openapiSourceConfigMapsIndex)(handler.MapObject{Meta: configMap, Object: configMap})
+
+	expected := []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "myopenapi", Namespace: "myns"}}}
+	if !reflect.DeepEqual(expected, requests) {
+		t.Errorf("unexpected requests %v", requests)
+	}
+}
+
+func TestCustomPolicyDefinitionEventMapper(t *testing.T) {
+	otherOpenAPI := newTestSourcesOpenAPI()
+	otherOpenAPI.Namespace = "otherns"
+	r := &OpenAPIReconciler{BaseReconciler: newTestBaseReconciler(t, newTestSourcesOpenAPI(), otherOpenAPI)}
+
+	customPolicy := &capabilitiesv1beta1.CustomPolicyDefinition{ObjectMeta: metav1.ObjectMeta{Name: "mypolicy", Namespace: "myns"}}
+	requests := r.customPolicyDefinitionEventMapper(handler.MapObject{Meta: customPolicy, Object: customPolicy})
+
+	expected := []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "myopenapi", Namespace: "myns"}}}
+	if !reflect.DeepEqual(expected, requests) {
+		t.Errorf("unexpected requests %v", requests)
+	}
+}
@@ -2,10 +2,10 @@ package controllers
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 
 	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
@@ -17,16 +17,55 @@ import (
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// OpenAPIOIDCIssuerEndpointSecretField is the secret field with the OIDC issuer endpoint
+	OpenAPIOIDCIssuerEndpointSecretField = "issuerEndpoint"
 )
 
 var (
 	// LastSlashRegexp matches the last slash
 	LastSlashRegexp = regexp.MustCompile(`/$`)
+
+	// applicationPlanPriceRegexp matches the application plan prices
+	applicationPlanPriceRegexp = regexp.MustCompile(`^\d+(\.\d{2})?$`)
+
+	// applicationPlanLimitPeriods are the valid application plan limit periods
+	applicationPlanLimitPeriods = map[string]bool{
+		"eternity": true, "year": true, "month": true, "week": true, "day": true, "hour": true, "minute": true,
+	}
 )
 
+// openAPIOperationLimit is an item of the x-3scale-limits operation extension
+type openAPIOperationLimit struct {
+	Period string `json:"period"`
+	Value  int    `json:"value"`
+}
+
+// openAPIOperationPricingRule is an item of the x-3scale-pricing-rules operation extension
+type openAPIOperationPricingRule struct {
+	From         int    `json:"from"`
+	To           int    `json:"to"`
+	PricePerUnit string `json:"pricePerUnit"`
+}
+
+// openAPIPolicy is an item of the x-3scale-policies document extension
+type openAPIPolicy struct {
+	Name          string               `json:"name"`
+	Version       string               `json:"version,omitempty"`
+	Configuration runtime.RawExtension `json:"configuration,omitempty"`
+	Enabled       *bool                `json:"enabled,omitempty"`
+}
+
 type OpenAPIProductReconciler struct {
 	*reconcilers.BaseReconciler
 	openapiCR       *capabilitiesv1beta1.OpenAPI
@@ -110,41 +149,71 @@ func (p *OpenAPIProductReconciler) desired() (*capabilitiesv1beta1.Product, erro
 			Namespace: p.openapiCR.Namespace,
 		},
 		Spec: capabilitiesv1beta1.ProductSpec{
-			Name:               name,
-			SystemName:         systemName,
-			Description:        description,
-			ProviderAccountRef: p.openapiCR.Spec.ProviderAccountRef,
+			Name:                     name,
+			SystemName:               systemName,
+			Description:              description,
+			ProviderAccountRef:       p.openapiCR.Spec.ProviderAccountRef,
+			ProviderAccountNamespace: p.openapiCR.Spec.ProviderAccountNamespace,
+			TenantRef:                p.openapiCR.Spec.TenantRef,
 		},
 	}
 
 	// Deployment
-	product.Spec.Deployment = p.desiredDeployment()
+	deployment, err := p.desiredDeployment()
+	if err != nil {
+		return nil, err
+	}
+	product.Spec.Deployment = deployment
 
 	// Methods
 	product.Spec.Methods = p.desiredMethods()
 
+	// Backends
+	backendSplitter, err := newOpenAPIBackendSplitter(p.openapiCR, p.openapiObj)
+	if err != nil {
+		return nil, err
+	}
+
 	// Mapping rules
-	mappingRules, err := p.desiredMappingRules()
+	mappingRules, err := p.desiredMappingRules(backendSplitter)
 	if err != nil {
 		return nil, err
 	}
 	product.Spec.MappingRules = mappingRules
 
+	// Application plans
+	applicationPlans, err := p.desiredApplicationPlans()
+	if err != nil {
+		return nil, err
+	}
+	product.Spec.ApplicationPlans = applicationPlans
+
+	// Policy chain
+	policies, err := p.desiredPolicies()
+	if err != nil {
+		return nil, err
+	}
+	product.Spec.Policies = policies
+
 	// backend usages
-	// current implementation assumes same system name for backend and product
-	backendSystemName := p.desiredSystemName()
-	product.Spec.BackendUsages = map[string]capabilitiesv1beta1.BackendUsageSpec{
-		backendSystemName: capabilitiesv1beta1.BackendUsageSpec{
-			Path: "/",
-		},
+	product.Spec.BackendUsages = map[string]capabilitiesv1beta1.BackendUsageSpec{}
+	for _, backend := range backendSplitter.Backends() {
+		product.Spec.BackendUsages[backend.SystemName] = capabilitiesv1beta1.BackendUsageSpec{
+			Path: backend.Path,
+		}
 	}
 
 	product.SetDefaults(p.Logger())
 
 	// internal validation
+	// application plans come from the OpenAPI document vendor extensions, validation errors are spec errors
 	validationErrors := product.Validate()
 	if len(validationErrors) > 0 {
-		return nil, errors.New(validationErrors.ToAggregate().Error())
+		fieldErrors = append(fieldErrors, field.Invalid(openapiRefFldPath, p.openapiCR.Spec.OpenAPIRef, validationErrors.ToAggregate().Error()))
+		return nil, &helper.SpecFieldError{
+			ErrorType:      helper.InvalidError,
+			FieldErrorList: fieldErrors,
+		}
 	}
 
 	err = p.SetOwnerReference(p.openapiCR, product)
@@ -208,45 +277,58 @@ func (p *OpenAPIProductReconciler) desiredObjName() string {
 	return fmt.Sprintf("%s-%s", helper.K8sNameFromOpenAPITitle(p.openapiObj), string(p.openapiCR.UID))
 }
 
-func (p *OpenAPIProductReconciler) desiredDeployment() *capabilitiesv1beta1.ProductDeploymentSpec {
+func (p *OpenAPIProductReconciler) desiredDeployment() (*capabilitiesv1beta1.ProductDeploymentSpec, error) {
 	deployment := &capabilitiesv1beta1.ProductDeploymentSpec{}
 
+	authentication, err := p.desiredAuthentication()
+	if err != nil {
+		return nil, err
+	}
+
 	if p.openapiCR.Spec.ProductionPublicBaseURL != nil || p.openapiCR.Spec.StagingPublicBaseURL != nil {
 		// Self managed deployment
 		deployment.ApicastSelfManaged = &capabilitiesv1beta1.ApicastSelfManagedSpec{
 			StagingPublicBaseURL:    p.openapiCR.Spec.StagingPublicBaseURL,
 			ProductionPublicBaseURL: p.openapiCR.Spec.ProductionPublicBaseURL,
-			Authentication:          p.desiredAuthentication(),
+			Authentication:          authentication,
 		}
 	} else {
 		// Hosted deployment
 		deployment.ApicastHosted = &capabilitiesv1beta1.ApicastHostedSpec{
-			Authentication: p.desiredAuthentication(),
+			Authentication: authentication,
 		}
 	}
 
-	return deployment
+	return deployment, nil
 }
 
-func (p *OpenAPIProductReconciler) desiredAuthentication() *capabilitiesv1beta1.AuthenticationSpec {
+func (p *OpenAPIProductReconciler) desiredAuthentication() (*capabilitiesv1beta1.AuthenticationSpec, error) {
 	globalSecRequirements := helper.OpenAPIGlobalSecurityRequirements(p.openapiObj)
-	if len(globalSecRequirements) == 0 {
-		// if no security requirements are found, default to UserKey auth
-		return p.desiredUserKeyAuthentication(nil)
+	// Security requirement validated before
+	secRequirementExtended, err := helper.SelectOpenAPISecurityRequirement(globalSecRequirements, p.openapiCR.Spec.SecuritySchemeName)
+	if err != nil {
+		return nil, err
 	}
 
-	// Only the first one is used
-	secRequirementExtended := globalSecRequirements[0]
+	if secRequirementExtended == nil {
+		// if no security requirements are found, default to UserKey auth
+		return p.desiredUserKeyAuthentication(nil), nil
+	}
 
 	var authenticationSpec *capabilitiesv1beta1.AuthenticationSpec
 
 	switch secRequirementExtended.Value.Type {
-	// TODO types "oauth2", "openIdConnect"
-	case "apiKey":
+	case helper.OpenAPISecuritySchemeTypeAPIKey:
 		authenticationSpec = p.desiredUserKeyAuthentication(secRequirementExtended)
+	case helper.OpenAPISecuritySchemeTypeOAuth2, helper.OpenAPISecuritySchemeTypeOpenIDConnect:
+		oidcAuthenticationSpec, err := p.desiredOIDCAuthentication(secRequirementExtended)
+		if err != nil {
+			return nil, err
+		}
+		authenticationSpec = oidcAuthenticationSpec
 	}
 
-	return authenticationSpec
+	return authenticationSpec, nil
 }
 
 func (p *OpenAPIProductReconciler) desiredUserKeyAuthentication(secReq *helper.ExtendedSecurityRequirement) *capabilitiesv1beta1.AuthenticationSpec {
@@ -264,6 +346,85 @@ func (p *OpenAPIProductReconciler) desiredUserKeyAuthentication(secReq *helper.E
 	return authSpec
 }
 
+func (p *OpenAPIProductReconciler) desiredOIDCAuthentication(secReq *helper.ExtendedSecurityRequirement) (*capabilitiesv1beta1.AuthenticationSpec, error) {
+	// OIDC spec presence validated before
+	oidcSpec := p.openapiCR.Spec.OIDC
+
+	issuerEndpoint, err := p.desiredOIDCIssuerEndpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	authSpec := &capabilitiesv1beta1.AuthenticationSpec{
+		OIDC: &capabilitiesv1beta1.OIDCSpec{
+			IssuerType:               oidcSpec.IssuerType,
+			IssuerEndpoint:           issuerEndpoint,
+			AuthenticationFlow:       p.desiredOIDCAuthenticationFlow(secReq),
+			JwtClaimWithClientID:     oidcSpec.JwtClaimWithClientID,
+			JwtClaimWithClientIDType: oidcSpec.JwtClaimWithClientIDType,
+			Security:                 p.desiredPrivateAPISecurity(),
+		},
+	}
+
+	return authSpec, nil
+}
+
+func (p *OpenAPIProductReconciler) desiredOIDCIssuerEndpoint() (string, error) {
+	fieldErrors := field.ErrorList{}
+	specFldPath := field.NewPath("spec")
+	issuerEndpointRefFldPath := specFldPath.Child("oidc").Child("issuerEndpointRef")
+
+	issuerEndpointRef := p.openapiCR.Spec.OIDC.IssuerEndpointRef
+	objectKey := types.NamespacedName{Name: issuerEndpointRef.Name, Namespace: p.openapiCR.Namespace}
+	secret := &corev1.Secret{}
+
+	if err := p.Client().Get(p.Context(), objectKey, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			fieldErrors = append(fieldErrors, field.Invalid(issuerEndpointRefFldPath, issuerEndpointRef, "Secret not found"))
+			return "", &helper.SpecFieldError{
+				ErrorType:      helper.InvalidError,
+				FieldErrorList: fieldErrors,
+			}
+		}
+
+		// unexpected error
+		return "", err
+	}
+
+	issuerEndpoint := helper.GetSecretDataValueOrDefault(secret.Data, OpenAPIOIDCIssuerEndpointSecretField, "")
+	if issuerEndpoint == "" {
+		fieldErrors = append(fieldErrors, field.Invalid(issuerEndpointRefFldPath, issuerEndpointRef, fmt.Sprintf("Secret field '%s' is missing or empty", OpenAPIOIDCIssuerEndpointSecretField)))
+		return "", &helper.SpecFieldError{
+			ErrorType:      helper.InvalidError,
+			FieldErrorList: fieldErrors,
+		}
+	}
+
+	return issuerEndpoint, nil
+}
+
+// desiredOIDCAuthenticationFlow returns the authentication flow from the OIDC spec, if set.
+// Otherwise, the authentication flow is derived from the oauth2 security scheme flows.
+func (p *OpenAPIProductReconciler) desiredOIDCAuthenticationFlow(secReq *helper.ExtendedSecurityRequirement) *capabilitiesv1beta1.OIDCAuthenticationFlowSpec {
+	if p.openapiCR.Spec.OIDC.AuthenticationFlow != nil {
+		authenticationFlow := *p.openapiCR.Spec.OIDC.AuthenticationFlow
+		return &authenticationFlow
+	}
+
+	// openIdConnect security schemes do not define flows
+	flows := secReq.Value.Flows
+	if flows == nil {
+		return nil
+	}
+
+	return &capabilitiesv1beta1.OIDCAuthenticationFlowSpec{
+		StandardFlowEnabled:       flows.AuthorizationCode != nil,
+		ImplicitFlowEnabled:       flows.Implicit != nil,
+		ServiceAccountsEnabled:    flows.ClientCredentials != nil,
+		DirectAccessGrantsEnabled: flows.Password != nil,
+	}
+}
+
 func (p *OpenAPIProductReconciler) parseUserKeyCredentialsLoc(inField string) *string {
 	tmpQuery := "query"
 	tmpHeaders := "headers"
@@ -291,15 +452,35 @@ func (p *OpenAPIProductReconciler) desiredMethods() map[string]capabilitiesv1bet
 	return methods
 }
 
-func (p *OpenAPIProductReconciler) desiredMappingRules() ([]capabilitiesv1beta1.MappingRuleSpec, error) {
+// desiredMappingRules generates the mapping rules of the operations.
+// Mapping rules are partitioned by backend: the pattern of each mapping rule begins with the backend usage path
+// of the backend the operation is assigned to.
+func (p *OpenAPIProductReconciler) desiredMappingRules(backendSplitter *openAPIBackendSplitter) ([]capabilitiesv1beta1.MappingRuleSpec, error) {
+	publicBasePath, err := p.desiredPublicBasePath()
+	if err != nil {
+		return nil, err
+	}
+
 	mappingRules := make([]capabilitiesv1beta1.MappingRuleSpec, 0)
 	for path, pathItem := range p.openapiObj.Paths {
-		desiredPattern, err := p.desiredMappingRulesPattern(path)
-		if err != nil {
-			return nil, err
-		}
-
 		for opVerb, operation := range pathItem.Operations() {
+			backend, err := backendSplitter.OperationBackend(path, opVerb, pathItem, operation)
+			if err != nil {
+				return nil, err
+			}
+
+			basePath := publicBasePath
+			if backendSplitter.mode() == capabilitiesv1beta1.OpenAPIBackendSplitModeServers {
+				// paths are relative to the server URL
+				basePath = backend.Path
+			}
+
+			desiredPattern := p.desiredMappingRulesPattern(basePath, path)
+			if !patternInBackendPath(desiredPattern, backend.Path) {
+				return nil, p.invalidOpenAPIRefError(fmt.Sprintf("operation %s %s: mapping rule pattern %s does not begin with the backend path %s",
+					strings.ToUpper(opVerb), path, desiredPattern, backend.Path))
+			}
+
 			mappingRules = append(mappingRules, capabilitiesv1beta1.MappingRuleSpec{
 				HTTPMethod:      strings.ToUpper(opVerb),
 				Pattern:         desiredPattern,
@@ -311,23 +492,226 @@ func (p *OpenAPIProductReconciler) desiredMappingRules() ([]capabilitiesv1beta1.
 	return mappingRules, nil
 }
 
-func (p *OpenAPIProductReconciler) desiredMappingRulesPattern(path string) (string, error) {
-	publicBasePath, err := p.desiredPublicBasePath()
+// patternInBackendPath returns true when the mapping rule pattern begins with the backend path
+// at a path segment boundary, i.e. /v1 includes /v1/pets but not /v10/pets.
+// The exact match $ anchor of the pattern is ignored.
+func patternInBackendPath(pattern, backendPath string) bool {
+	path := strings.TrimSuffix(pattern, "$")
+	return path == backendPath || strings.HasPrefix(path, strings.TrimSuffix(backendPath, "/")+"/")
+}
+
+// desiredApplicationPlans reads the application plans from the x-3scale-plans document extension.
+// Limits and pricing rules from the x-3scale-limits and x-3scale-pricing-rules operation extensions
+// are added to the plans referencing the method generated for the operation.
+func (p *OpenAPIProductReconciler) desiredApplicationPlans() (map[string]capabilitiesv1beta1.ApplicationPlanSpec, error) {
+	plans := map[string]capabilitiesv1beta1.ApplicationPlanSpec{}
+	_, err := helper.DecodeOpenAPIExtension(p.openapiObj.ExtensionProps, helper.OpenAPIExtension3scalePlans, &plans)
 	if err != nil {
-		return "", err
+		return nil, p.invalidOpenAPIRefError(err.Error())
+	}
+
+	// sorted to keep the order of limits and pricing rules
+	paths := make([]string, 0, len(p.openapiObj.Paths))
+	for path := range p.openapiObj.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		operations := p.openapiObj.Paths[path].Operations()
+		opVerbs := make([]string, 0, len(operations))
+		for opVerb := range operations {
+			opVerbs = append(opVerbs, opVerb)
+		}
+		sort.Strings(opVerbs)
+
+		for _, opVerb := range opVerbs {
+			operation := operations[opVerb]
+			methodRef := capabilitiesv1beta1.MetricMethodRefSpec{
+				SystemName: helper.MethodSystemNameFromOpenAPIOperation(path, opVerb, operation),
+			}
+
+			limits := map[string][]openAPIOperationLimit{}
+			_, err := helper.DecodeOpenAPIExtension(operation.ExtensionProps, helper.OpenAPIExtension3scaleLimits, &limits)
+			if err != nil {
+				return nil, p.invalidOpenAPIRefError(fmt.Sprintf("operation %s: %s", methodRef.SystemName, err))
+			}
+
+			for planSystemName, planLimits := range limits {
+				plan, ok := plans[planSystemName]
+				if !ok {
+					return nil, p.invalidOpenAPIRefError(fmt.Sprintf("operation %s: %s: plan %s not found in %s",
+						methodRef.SystemName, helper.OpenAPIExtension3scaleLimits, planSystemName, helper.OpenAPIExtension3scalePlans))
+				}
+
+				for _, limit := range planLimits {
+					plan.Limits = append(plan.Limits, capabilitiesv1beta1.LimitSpec{
+						Period:          limit.Period,
+						Value:           limit.Value,
+						MetricMethodRef: methodRef,
+					})
+				}
+				plans[planSystemName] = plan
+			}
+
+			pricingRules := map[string][]openAPIOperationPricingRule{}
+			_, err = helper.DecodeOpenAPIExtension(operation.ExtensionProps, helper.OpenAPIExtension3scalePricingRules, &pricingRules)
+			if err != nil {
+				return nil, p.invalidOpenAPIRefError(fmt.Sprintf("operation %s: %s", methodRef.SystemName, err))
+			}
+
+			for planSystemName, planPricingRules := range pricingRules {
+				plan, ok := plans[planSystemName]
+				if !ok {
+					return nil, p.invalidOpenAPIRefError(fmt.Sprintf("operation %s: %s: plan %s not found in %s",
+						methodRef.SystemName, helper.OpenAPIExtension3scalePricingRules, planSystemName, helper.OpenAPIExtension3scalePlans))
+				}
+
+				for _, rule := range planPricingRules {
+					plan.PricingRules = append(plan.PricingRules, capabilitiesv1beta1.PricingRuleSpec{
+						From:            rule.From,
+						To:              rule.To,
+						PricePerUnit:    rule.PricePerUnit,
+						MetricMethodRef: methodRef,
+					})
+				}
+				plans[planSystemName] = plan
+			}
+		}
+	}
+
+	for planSystemName, plan := range plans {
+		if err := validateOpenAPIApplicationPlan(plan); err != nil {
+			return nil, p.invalidOpenAPIRefError(fmt.Sprintf("%s: plan %s: %s", helper.OpenAPIExtension3scalePlans, planSystemName, err))
+		}
+	}
+
+	if len(plans) == 0 {
+		return nil, nil
+	}
+
+	return plans, nil
+}
+
+// desiredPolicies reads the policy chain from the x-3scale-policies document extension.
+// Policies must be APIcast built-in policies or defined by CustomPolicyDefinition objects in the namespace.
+func (p *OpenAPIProductReconciler) desiredPolicies() ([]capabilitiesv1beta1.PolicyConfig, error) {
+	openapiPolicies := []openAPIPolicy{}
+	found, err := helper.DecodeOpenAPIExtension(p.openapiObj.ExtensionProps, helper.OpenAPIExtension3scalePolicies, &openapiPolicies)
+	if err != nil {
+		return nil, p.invalidOpenAPIRefError(err.Error())
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	var customPolicyList *capabilitiesv1beta1.CustomPolicyDefinitionList
+
+	policies := make([]capabilitiesv1beta1.PolicyConfig, 0, len(openapiPolicies))
+	for idx, openapiPolicy := range openapiPolicies {
+		if openapiPolicy.Name == "" {
+			return nil, p.invalidOpenAPIRefError(fmt.Sprintf("%s: policy %d: name is required", helper.OpenAPIExtension3scalePolicies, idx))
+		}
+
+		policy := capabilitiesv1beta1.PolicyConfig{
+			Name:          openapiPolicy.Name,
+			Version:       openapiPolicy.Version,
+			Configuration: openapiPolicy.Configuration,
+			Enabled:       openapiPolicy.Enabled == nil || *openapiPolicy.Enabled,
+		}
+
+		if policy.Version == "" {
+			policy.Version = capabilitiesv1beta1.BuiltinPolicyVersion
+		}
+
+		if len(policy.Configuration.Raw) == 0 {
+			policy.Configuration.Raw = []byte(`{}`)
+		}
+
+		if !capabilitiesv1beta1.IsBuiltinPolicy(policy.Name, policy.Version) {
+			if customPolicyList == nil {
+				customPolicyList = &capabilitiesv1beta1.CustomPolicyDefinitionList{}
+				err := p.Client().List(p.Context(), customPolicyList, client.InNamespace(p.openapiCR.Namespace))
+				if err != nil {
+					return nil, fmt.Errorf("Failed to list custom policy definitions: %w", err)
+				}
+			}
+
+			if !customPolicyDefined(customPolicyList, policy.Name, policy.Version) {
+				return nil, p.invalidOpenAPIRefError(fmt.Sprintf("%s: policy %s version %s is neither a built-in policy nor a CustomPolicyDefinition in the namespace",
+					helper.OpenAPIExtension3scalePolicies, policy.Name, policy.Version))
+			}
+		}
+
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+func customPolicyDefined(customPolicyList *capabilitiesv1beta1.CustomPolicyDefinitionList, name, version string) bool {
+	for idx := range customPolicyList.Items {
+		if customPolicyList.Items[idx].Spec.Name == name && customPolicyList.Items[idx].Spec.Version == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateOpenAPIApplicationPlan validates the fields constrained by the product CRD validation
+func validateOpenAPIApplicationPlan(plan capabilitiesv1beta1.ApplicationPlanSpec) error {
+	if plan.TrialPeriod != nil && *plan.TrialPeriod < 0 {
+		return fmt.Errorf("invalid trialPeriod %d", *plan.TrialPeriod)
 	}
 
-	// remove the last slash of the publicBasePath
-	publicBasePathSanitized := LastSlashRegexp.ReplaceAllString(publicBasePath, "")
+	if plan.SetupFee != nil && !applicationPlanPriceRegexp.MatchString(*plan.SetupFee) {
+		return fmt.Errorf("invalid setupFee %q", *plan.SetupFee)
+	}
+
+	if plan.CostMonth != nil && !applicationPlanPriceRegexp.MatchString(*plan.CostMonth) {
+		return fmt.Errorf("invalid costMonth %q", *plan.CostMonth)
+	}
+
+	for _, limit := range plan.Limits {
+		if !applicationPlanLimitPeriods[limit.Period] {
+			return fmt.Errorf("limit for %s: invalid period %q", limit.MetricMethodRef.String(), limit.Period)
+		}
+	}
+
+	for _, rule := range plan.PricingRules {
+		if !applicationPlanPriceRegexp.MatchString(rule.PricePerUnit) {
+			return fmt.Errorf("pricing rule for %s: invalid pricePerUnit %q", rule.MetricMethodRef.String(), rule.PricePerUnit)
+		}
+	}
+
+	return nil
+}
+
+func (p *OpenAPIProductReconciler) invalidOpenAPIRefError(msg string) error {
+	fieldErrors := field.ErrorList{}
+	specFldPath := field.NewPath("spec")
+	openapiRefFldPath := specFldPath.Child("openapiRef")
+	fieldErrors = append(fieldErrors, field.Invalid(openapiRefFldPath, p.openapiCR.Spec.OpenAPIRef, msg))
+	return &helper.SpecFieldError{
+		ErrorType:      helper.InvalidError,
+		FieldErrorList: fieldErrors,
+	}
+}
+
+func (p *OpenAPIProductReconciler) desiredMappingRulesPattern(basePath, path string) string {
+	// remove the last slash of the basePath
+	basePathSanitized := LastSlashRegexp.ReplaceAllString(basePath, "")
 
 	//  According OAS 3.0: path MUST begin with a slash
-	pattern := fmt.Sprintf("%s%s", publicBasePathSanitized, path)
+	pattern := fmt.Sprintf("%s%s", basePathSanitized, path)
 
 	if p.openapiCR.Spec.PrefixMatching == nil || !*p.openapiCR.Spec.PrefixMatching {
 		pattern = fmt.Sprintf("%s$", pattern)
 	}
 
-	return pattern, nil
+	return pattern
 }
 
 func (p *OpenAPIProductReconciler) desiredPublicBasePath() (string, error) {
@@ -0,0 +1,298 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	logrtesting "github.com/go-logr/logr/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestOpenAPIProductReconcilerTenantRef(t *testing.T) {
+	openapiCR := newTestOpenAPICR()
+	openapiCR.Spec.ProviderAccountRef = nil
+	openapiCR.Spec.TenantRef = &corev1.LocalObjectReference{Name: "mytenant"}
+
+	reconciler := NewOpenAPIProductReconciler(newTestBaseReconciler(t, openapiCR), openapiCR, newTestOpenAPIObj(t), nil, logrtesting.NullLogger{})
+	product, err := reconciler.desired()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if product.Spec.ProviderAccountRef != nil {
+		t.Fatalf("unexpected provider account reference %v", product.Spec.ProviderAccountRef)
+	}
+	if product.Spec.TenantRef == nil || product.Spec.TenantRef.Name != "mytenant" {
+		t.Fatalf("unexpected tenant reference %v", product.Spec.TenantRef)
+	}
+}
+
+const testOpenAPIPlansDoc = `
+openapi: 3.0.2
+info:
+  title: Petstore
+  version: "1.0"
+servers:
+  - url: https://petstore.example.com/v1
+x-3scale-plans:
+  basic:
+    name: Basic
+    published: true
+    limits:
+      - period: day
+        value: 1000
+        metricMethodRef:
+          systemName: hits
+  premium:
+    name: Premium
+    costMonth: "100.00"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      x-3scale-limits:
+        basic:
+          - period: minute
+            value: 10
+        premium:
+          - period: minute
+            value: 100
+      x-3scale-pricing-rules:
+        premium:
+          - from: 1
+            to: 1000
+            pricePerUnit: "0.01"
+      responses:
+        "200":
+          description: pets
+%s
+`
+
+// newTestOpenAPIPlansObj loads the plans document with the extra paths.
+// The replacer, if any, is applied to the document
+func newTestOpenAPIPlansObj(t *testing.T, extraPaths string, replacer *strings.Replacer) *openapi3.Swagger {
+	t.Helper()
+	doc := fmt.Sprintf(testOpenAPIPlansDoc, extraPaths)
+	if replacer != nil {
+		doc = replacer.Replace(doc)
+	}
+
+	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return openapiObj
+}
+
+func TestOpenAPIProductReconcilerApplicationPlans(t *testing.T) {
+	openapiCR := newTestOpenAPICR()
+	reconciler := NewOpenAPIProductReconciler(newTestBaseReconciler(t, openapiCR), openapiCR, newTestOpenAPIPlansObj(t, "", nil), nil, logrtesting.NullLogger{})
+	_, err := reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	product := &capabilitiesv1beta1.Product{}
+	err = reconciler.Client().Get(context.TODO(), types.NamespacedName{Name: "petstore-abcd", Namespace: "myns"}, product)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	methodRef := capabilitiesv1beta1.MetricMethodRefSpec{SystemName: "listpets"}
+	if _, ok := product.Spec.Methods[methodRef.SystemName]; !ok {
+		t.Fatalf("method %s not found: %v", methodRef.SystemName, product.Spec.Methods)
+	}
+
+	basic, ok := product.Spec.ApplicationPlans["basic"]
+	if !ok {
+		t.Fatalf("basic plan not found: %v", product.Spec.ApplicationPlans)
+	}
+	expectedBasicLimits := []capabilitiesv1beta1.LimitSpec{
+		{Period: "day", Value: 1000, MetricMethodRef: capabilitiesv1beta1.MetricMethodRefSpec{SystemName: "hits"}},
+		{Period: "minute", Value: 10, MetricMethodRef: methodRef},
+	}
+	if basic.Name == nil || *basic.Name != "Basic" || !reflect.DeepEqual(expectedBasicLimits, basic.Limits) {
+		t.Fatalf("unexpected basic plan %v", basic)
+	}
+	if len(basic.PricingRules) != 0 {
+		t.Fatalf("unexpected basic plan pricing rules %v", basic.PricingRules)
+	}
+
+	premium, ok := product.Spec.ApplicationPlans["premium"]
+	if !ok {
+		t.Fatalf("premium plan not found: %v", product.Spec.ApplicationPlans)
+	}
+	expectedPremiumLimits := []capabilitiesv1beta1.LimitSpec{
+		{Period: "minute", Value: 100, MetricMethodRef: methodRef},
+	}
+	expectedPremiumPricingRules := []capabilitiesv1beta1.PricingRuleSpec{
+		{From: 1, To: 1000, PricePerUnit: "0.01", MetricMethodRef: methodRef},
+	}
+	if premium.CostMonth == nil || *premium.CostMonth != "100.00" ||
+		!reflect.DeepEqual(expectedPremiumLimits, premium.Limits) ||
+		!reflect.DeepEqual(expectedPremiumPricingRules, premium.PricingRules) {
+		t.Fatalf("unexpected premium plan %v", premium)
+	}
+}
+
+func TestOpenAPIProductReconcilerApplicationPlanErrors(t *testing.T) {
+	cases := []struct {
+		name        string
+		extraPaths  string
+		replacer    *strings.Replacer
+		expectedMsg string
+	}{
+		{"unknown metric reference", "", strings.NewReplacer("systemName: hits", "systemName: unknown"),
+			"limit does not have valid local metric or method reference"},
+		{"limits of undeclared plan", `
+  /stores:
+    get:
+      operationId: listStores
+      x-3scale-limits:
+        gold:
+          - period: minute
+            value: 10
+      responses:
+        "200":
+          description: stores`, nil, "x-3scale-limits: plan gold not found"},
+		{"pricing rules of undeclared plan", `
+  /stores:
+    get:
+      operationId: listStores
+      x-3scale-pricing-rules:
+        gold:
+          - from: 1
+            to: 10
+            pricePerUnit: "1.00"
+      responses:
+        "200":
+          description: stores`, nil, "x-3scale-pricing-rules: plan gold not found"},
+		{"invalid limit period", `
+  /stores:
+    get:
+      operationId: listStores
+      x-3scale-limits:
+        basic:
+          - period: fortnight
+            value: 10
+      responses:
+        "200":
+          description: stores`, nil, "invalid period \"fortnight\""},
+		{"invalid price per unit", `
+  /stores:
+    get:
+      operationId: listStores
+      x-3scale-pricing-rules:
+        basic:
+          - from: 1
+            to: 10
+            pricePerUnit: "one"
+      responses:
+        "200":
+          description: stores`, nil, "invalid pricePerUnit \"one\""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			openapiObj := newTestOpenAPIPlansObj(subT, tc.extraPaths, tc.replacer)
+			openapiCR := newTestOpenAPICR()
+			reconciler := NewOpenAPIProductReconciler(newTestBaseReconciler(subT, openapiCR), openapiCR, openapiObj, nil, logrtesting.NullLogger{})
+			_, err := reconciler.Reconcile()
+			if !helper.IsInvalidSpecError(err) {
+				subT.Fatalf("invalid spec error expected, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tc.expectedMsg) {
+				subT.Fatalf("expected error %q, got %v", tc.expectedMsg, err)
+			}
+
+			productList := &capabilitiesv1beta1.ProductList{}
+			err = reconciler.Client().List(context.TODO(), productList)
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if len(productList.Items) != 0 {
+				subT.Fatalf("product created from invalid application plans: %v", productList.Items)
+			}
+		})
+	}
+}
+
+const testOpenAPIPoliciesDoc = `
+openapi: 3.0.2
+info:
+  title: Petstore
+  version: "1.0"
+x-3scale-policies:
+  - name: cors
+    configuration:
+      allow_origin: "*"
+  - name: my-custom-policy
+    version: "0.1"
+    enabled: false
+paths: {}
+`
+
+func TestOpenAPIProductReconcilerPolicies(t *testing.T) {
+	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testOpenAPIPoliciesDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name            string
+		customPolicyNS  string
+		expectedInvalid bool
+	}{
+		{"custom policy defined", "myns", false},
+		{"custom policy defined in other namespace", "otherns", true},
+		{"custom policy not defined", "", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			openapiCR := newTestOpenAPICR()
+			objs := []runtime.Object{openapiCR}
+			if tc.customPolicyNS != "" {
+				objs = append(objs, &capabilitiesv1beta1.CustomPolicyDefinition{
+					ObjectMeta: metav1.ObjectMeta{Name: "mypolicy", Namespace: tc.customPolicyNS},
+					Spec:       capabilitiesv1beta1.CustomPolicyDefinitionSpec{Name: "my-custom-policy", Version: "0.1"},
+				})
+			}
+
+			reconciler := NewOpenAPIProductReconciler(newTestBaseReconciler(subT, objs...), openapiCR, openapiObj, nil, logrtesting.NullLogger{})
+			policies, err := reconciler.desiredPolicies()
+			if helper.IsInvalidSpecError(err) != tc.expectedInvalid {
+				subT.Fatalf("invalid spec error expected %t, got %v", tc.expectedInvalid, err)
+			}
+			if tc.expectedInvalid {
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if len(policies) != 2 {
+				subT.Fatalf("unexpected policies %v", policies)
+			}
+			if policies[0].Name != "cors" || policies[0].Version != capabilitiesv1beta1.BuiltinPolicyVersion ||
+				!policies[0].Enabled || string(policies[0].Configuration.Raw) != `{"allow_origin":"*"}` {
+				subT.Errorf("unexpected built-in policy %v", policies[0])
+			}
+			if policies[1].Name != "my-custom-policy" || policies[1].Version != "0.1" ||
+				policies[1].Enabled || string(policies[1].Configuration.Raw) != `{}` {
+				subT.Errorf("unexpected custom policy %v", policies[1])
+			}
+		})
+	}
+}
@@ -19,16 +19,20 @@ type OpenAPIStatusReconciler struct {
 	*reconcilers.BaseReconciler
 	resource            *capabilitiesv1beta1.OpenAPI
 	providerAccountHost string
+	openapiDigest       string
+	warnings            []string
 	reconcileError      error
 	reconcileReady      bool
 	logger              logr.Logger
 }
 
-func NewOpenAPIStatusReconciler(b *reconcilers.BaseReconciler, resource *capabilitiesv1beta1.OpenAPI, providerAccountHost string, reconcileError error, reconcileReady bool) *OpenAPIStatusReconciler {
+func NewOpenAPIStatusReconciler(b *reconcilers.BaseReconciler, resource *capabilitiesv1beta1.OpenAPI, providerAccountHost string, openapiDigest string, warnings []string, reconcileError error, reconcileReady bool) *OpenAPIStatusReconciler {
 	return &OpenAPIStatusReconciler{
 		BaseReconciler:      b,
 		resource:            resource,
 		providerAccountHost: providerAccountHost,
+		openapiDigest:       openapiDigest,
+		warnings:            warnings,
 		reconcileError:      reconcileError,
 		reconcileReady:      reconcileReady,
 		logger:              b.Logger().WithValues("Status Reconciler", resource.Name),
@@ -79,6 +83,8 @@ func (s *OpenAPIStatusReconciler) calculateStatus() (*capabilitiesv1beta1.OpenAP
 
 	newStatus.ProviderAccountHost = s.providerAccountHost
 
+	newStatus.OpenAPIDigest = s.openapiDigest
+
 	productResourceName, err := s.getManagedProduct()
 	if err != nil {
 		return nil, err
@@ -91,6 +97,14 @@ func (s *OpenAPIStatusReconciler) calculateStatus() (*capabilitiesv1beta1.OpenAP
 	}
 	newStatus.BackendResourceNames = backendResourceNames
 
+	activeDocResourceName, err := s.getManagedActiveDoc()
+	if err != nil {
+		return nil, err
+	}
+	newStatus.ActiveDocResourceName = activeDocResourceName
+
+	newStatus.Warnings = s.warnings
+
 	newStatus.ObservedGeneration = s.resource.Status.ObservedGeneration
 
 	newStatus.Conditions = s.resource.Status.Conditions.Copy()
@@ -178,6 +192,11 @@ func (s *OpenAPIStatusReconciler) getManagedBackends() ([]corev1.LocalObjectRefe
 
 	var managedBackends []corev1.LocalObjectReference
 	for _, backend := range list.Items {
+		// backends being removed when no longer generated from the OpenAPI document
+		if backend.GetDeletionTimestamp() != nil {
+			continue
+		}
+
 		for _, ownerRef := range backend.GetOwnerReferences() {
 			if ownerRef.UID == s.resource.UID {
 				managedBackends = append(managedBackends, corev1.LocalObjectReference{
@@ -189,3 +208,31 @@ func (s *OpenAPIStatusReconciler) getManagedBackends() ([]corev1.LocalObjectRefe
 
 	return managedBackends, nil
 }
+
+func (s *OpenAPIStatusReconciler) getManagedActiveDoc() (*corev1.LocalObjectReference, error) {
+	listOps := []client.ListOption{
+		client.InNamespace(s.resource.Namespace),
+	}
+	activeDocList := &capabilitiesv1beta1.ActiveDocList{}
+	err := s.Client().List(s.Context(), activeDocList, listOps...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list activedocs: %w", err)
+	}
+
+	for _, activeDoc := range activeDocList.Items {
+		// activedoc being removed when the activeDoc section is disabled
+		if activeDoc.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		for _, ownerRef := range activeDoc.GetOwnerReferences() {
+			if ownerRef.UID == s.resource.UID {
+				return &corev1.LocalObjectReference{
+					Name: activeDoc.Name,
+				}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
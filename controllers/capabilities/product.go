@@ -3,9 +3,33 @@ package controllers
 import (
 	"fmt"
 
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+
 	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+	"github.com/go-logr/logr"
 )
 
+// findRemoteProduct looks up the 3scale product by the ID in the status field.
+// Products without status ID have never been synchronized by the custom resource,
+// hence, they are not managed and not looked up by system name. Returns nil when not found.
+func findRemoteProduct(resource *capabilitiesv1beta1.Product, threescaleAPIClient *threescaleapi.ThreeScaleClient, logger logr.Logger) (*controllerhelper.ProductEntity, error) {
+	if resource.Status.ID == nil {
+		return nil, nil
+	}
+
+	productObj, err := threescaleAPIClient.Product(*resource.Status.ID)
+	if err != nil {
+		if threescaleapi.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("find product [%s]: %w", resource.Spec.SystemName, err)
+	}
+
+	return controllerhelper.NewProductEntity(productObj, threescaleAPIClient, logger), nil
+}
+
 func (t *ProductThreescaleReconciler) syncProduct(_ interface{}) error {
 	params := threescaleapi.Params{}
 
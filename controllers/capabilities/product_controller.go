@@ -20,7 +20,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -34,9 +37,18 @@ import (
 	"github.com/3scale/3scale-operator/version"
 )
 
+const (
+	// productFinalizer removes the 3scale product when the custom resource is deleted
+	productFinalizer = "product.capabilities.3scale.net/finalizer"
+)
+
 // ProductReconciler reconciles a Product object
 type ProductReconciler struct {
 	*reconcilers.BaseReconciler
+
+	// ResyncPeriod is the period to synchronize again products that did not change.
+	// 3scale product objects modified outside the operator are corrected. Zero disables the periodic resync.
+	ResyncPeriod time.Duration
 }
 
 // blank assignment to verify that ProductReconciler implements reconcile.Reconciler
@@ -74,10 +86,19 @@ func (r *ProductReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		reqLogger.V(1).Info(string(jsonData))
 	}
 
-	// Ignore deleted Products, this can happen when foregroundDeletion is enabled
-	// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/#foreground-cascading-deletion
-	if product.DeletionTimestamp != nil {
-		return ctrl.Result{}, nil
+	if product.GetDeletionTimestamp() != nil {
+		return reconcileFinalizer(r.BaseReconciler, product, productFinalizer, func() error {
+			return r.removeProduct(product)
+		}, reqLogger)
+	}
+
+	finalizerAdded, err := ensureFinalizer(r.BaseReconciler, product, productFinalizer)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if finalizerAdded {
+		reqLogger.Info("finalizer added. Requeueing.")
+		return ctrl.Result{Requeue: true}, nil
 	}
 
 	if product.SetDefaults(reqLogger) {
@@ -124,7 +145,7 @@ func (r *ProductReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	}
 
 	reqLogger.Info("END", "error", reconcileErr)
-	return ctrl.Result{}, reconcileErr
+	return ctrl.Result{RequeueAfter: r.ResyncPeriod}, nil
 }
 
 func (r *ProductReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -142,7 +163,7 @@ func (r *ProductReconciler) reconcile(productResource *capabilitiesv1beta1.Produ
 		return statusReconciler, err
 	}
 
-	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), productResource.Namespace, productResource.Spec.ProviderAccountRef, logger)
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), productResource.Namespace, productResource.Spec.ProviderAccountRef, productResource.Spec.ProviderAccountNamespace, productResource.Spec.TenantRef, logger)
 	if err != nil {
 		statusReconciler := NewProductStatusReconciler(r.BaseReconciler, productResource, nil, "", err)
 		return statusReconciler, err
@@ -155,24 +176,96 @@ func (r *ProductReconciler) reconcile(productResource *capabilitiesv1beta1.Produ
 		return statusReconciler, err
 	}
 
-	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	threescaleAPIClient, changeRecorder, err := controllerhelper.PortaClientWithChangeRecorder(providerAccount)
 	if err != nil {
 		statusReconciler := NewProductStatusReconciler(r.BaseReconciler, productResource, nil, providerAccount.AdminURLStr, err)
 		return statusReconciler, err
 	}
 
+	// In dry-run mode, changes to 3scale objects are computed but not applied
+	var dryRun *controllerhelper.DryRunRecorder
+	if capabilitiesv1beta1.IsDryRun(productResource) {
+		dryRun = controllerhelper.NewDryRunRecorder()
+	}
+
+	// Changes to 3scale objects of a spec already synchronized are reported as drift
+	var drift *driftDetector
+	if driftCheckRequired(productResource.Generation, productResource.Status.ObservedGeneration, productResource.Status.Conditions, capabilitiesv1beta1.ProductSyncedConditionType) {
+		drift = newDriftDetector(changeRecorder)
+	}
+
 	backendRemoteIndex, err := controllerhelper.NewBackendAPIRemoteIndex(threescaleAPIClient, logger)
 	if err != nil {
 		statusReconciler := NewProductStatusReconciler(r.BaseReconciler, productResource, nil, providerAccount.AdminURLStr, err)
 		return statusReconciler, err
 	}
 
-	reconciler := NewProductThreescaleReconciler(r.BaseReconciler, productResource, threescaleAPIClient, backendRemoteIndex)
+	reconciler := NewProductThreescaleReconciler(r.BaseReconciler, productResource, threescaleAPIClient, backendRemoteIndex, drift, dryRun)
 	productEntity, err := reconciler.Reconcile()
+	if err == nil {
+		err = reconcileDryRunConfigMap(r.BaseReconciler, productResource, "product", dryRun)
+	}
 	statusReconciler := NewProductStatusReconciler(r.BaseReconciler, productResource, productEntity, providerAccount.AdminURLStr, err)
+	statusReconciler.driftedTasks = drift.Tasks()
+	statusReconciler.dryRun = dryRun
+	if len(statusReconciler.driftedTasks) > 0 {
+		logger.Info("3scale product drift corrected", "tasks", statusReconciler.driftedTasks)
+		r.EventRecorder().Eventf(productResource, corev1.EventTypeWarning, DriftedReason, "3scale product modified outside the operator corrected by tasks: %s", strings.Join(statusReconciler.driftedTasks, ", "))
+	}
 	return statusReconciler, err
 }
 
+// removeProduct removes the 3scale product and reports the progress in the product status
+func (r *ProductReconciler) removeProduct(product *capabilitiesv1beta1.Product) error {
+	statusReconciler, deletionErr := r.removeProductFrom3scale(product)
+	_, statusUpdateErr := statusReconciler.Reconcile()
+	if statusUpdateErr != nil {
+		if deletionErr != nil {
+			return fmt.Errorf("Failed to remove product from 3scale: %v. Failed to update product status: %w", deletionErr, statusUpdateErr)
+		}
+
+		return fmt.Errorf("Failed to update product status: %w", statusUpdateErr)
+	}
+
+	return deletionErr
+}
+
+func (r *ProductReconciler) removeProductFrom3scale(product *capabilitiesv1beta1.Product) (*ProductStatusReconciler, error) {
+	logger := r.Logger().WithValues("product", product.Name)
+
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), product.Namespace, product.Spec.ProviderAccountRef, product.Spec.ProviderAccountNamespace, product.Spec.TenantRef, logger)
+	if err != nil {
+		statusReconciler := NewProductStatusReconciler(r.BaseReconciler, product, nil, "", err)
+		return statusReconciler, err
+	}
+
+	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	if err != nil {
+		statusReconciler := NewProductStatusReconciler(r.BaseReconciler, product, nil, providerAccount.AdminURLStr, err)
+		return statusReconciler, err
+	}
+
+	productEntity, err := findRemoteProduct(product, threescaleAPIClient, logger)
+	if err != nil {
+		statusReconciler := NewProductStatusReconciler(r.BaseReconciler, product, nil, providerAccount.AdminURLStr, err)
+		return statusReconciler, err
+	}
+
+	if productEntity != nil {
+		// 3scale removes product's metrics, methods, mapping rules, application plans and backend usages
+		err = threescaleAPIClient.DeleteProduct(productEntity.ID())
+		if err != nil && !threescaleapi.IsNotFound(err) {
+			err = fmt.Errorf("Error deleting product [%s;%d]: %w", product.Spec.SystemName, productEntity.ID(), err)
+			statusReconciler := NewProductStatusReconciler(r.BaseReconciler, product, productEntity, providerAccount.AdminURLStr, err)
+			return statusReconciler, err
+		}
+		logger.Info("product removed from 3scale", "ID", productEntity.ID())
+	}
+
+	statusReconciler := NewProductStatusReconciler(r.BaseReconciler, product, productEntity, providerAccount.AdminURLStr, nil)
+	return statusReconciler, nil
+}
+
 func (r *ProductReconciler) validateSpec(resource *capabilitiesv1beta1.Product) error {
 	errors := field.ErrorList{}
 	errors = append(errors, resource.Validate()...)
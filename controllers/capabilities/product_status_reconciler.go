@@ -21,7 +21,11 @@ type ProductStatusReconciler struct {
 	entity              *controllerhelper.ProductEntity
 	providerAccountHost string
 	syncError           error
-	logger              logr.Logger
+	// driftedTasks are the tasks that corrected 3scale objects modified outside the operator
+	driftedTasks []string
+	// dryRun holds the changes computed in dry-run mode
+	dryRun *controllerhelper.DryRunRecorder
+	logger logr.Logger
 }
 
 func NewProductStatusReconciler(b *reconcilers.BaseReconciler, resource *capabilitiesv1beta1.Product, entity *controllerhelper.ProductEntity, providerAccountHost string, syncError error) *ProductStatusReconciler {
@@ -80,6 +84,8 @@ func (s *ProductStatusReconciler) calculateStatus() *capabilitiesv1beta1.Product
 		newStatus.State = &tmpState
 	}
 
+	newStatus.StagingConfigVersion = s.stagingConfigVersion()
+
 	newStatus.ProviderAccountHost = s.providerAccountHost
 
 	newStatus.ObservedGeneration = s.resource.Status.ObservedGeneration
@@ -89,17 +95,41 @@ func (s *ProductStatusReconciler) calculateStatus() *capabilitiesv1beta1.Product
 	newStatus.Conditions.SetCondition(s.orphanCondition())
 	newStatus.Conditions.SetCondition(s.invalidCondition())
 	newStatus.Conditions.SetCondition(s.failedCondition())
+	newStatus.Conditions.SetCondition(s.deletingCondition())
+	newStatus.Conditions.SetCondition(driftedCondition(capabilitiesv1beta1.ProductDriftedConditionType, s.driftedTasks))
+	newStatus.Conditions.SetCondition(dryRunCondition(capabilitiesv1beta1.ProductDryRunConditionType, s.dryRun, dryRunConfigMapName("product", s.resource.Name)))
 
 	return newStatus
 }
 
+// stagingConfigVersion keeps the last known version when the deployment to staging failed
+func (s *ProductStatusReconciler) stagingConfigVersion() *int64 {
+	if !s.resource.Spec.DeployToStaging {
+		return nil
+	}
+
+	if s.entity == nil {
+		return s.resource.Status.StagingConfigVersion
+	}
+
+	// Already read when deployed
+	stagingConfig, err := s.entity.StagingProxyConfig()
+	if err != nil || stagingConfig == nil {
+		return s.resource.Status.StagingConfigVersion
+	}
+
+	version := int64(stagingConfig.Version)
+	return &version
+}
+
 func (s *ProductStatusReconciler) syncCondition() common.Condition {
 	condition := common.Condition{
 		Type:   capabilitiesv1beta1.ProductSyncedConditionType,
 		Status: corev1.ConditionFalse,
 	}
 
-	if s.syncError == nil {
+	// Changes are not applied in dry-run mode
+	if s.syncError == nil && s.dryRun == nil {
 		condition.Status = corev1.ConditionTrue
 	}
 
@@ -148,3 +178,17 @@ func (s *ProductStatusReconciler) failedCondition() common.Condition {
 
 	return condition
 }
+
+func (s *ProductStatusReconciler) deletingCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.ProductDeletingConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if s.resource.GetDeletionTimestamp() != nil {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = "3scale product is being removed"
+	}
+
+	return condition
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+	logrtesting "github.com/go-logr/logr/testing"
+)
+
+func TestFindRemoteProduct(t *testing.T) {
+	var productID int64 = 3
+
+	httpClient := newTestHTTPClient(func(req *http.Request) *http.Response {
+		if req.Method == http.MethodGet && req.URL.Path == "/admin/api/services/3.json" {
+			return newTestJSONResponse(t, http.StatusOK, &threescaleapi.Product{
+				Element: threescaleapi.ProductItem{ID: productID, SystemName: "myproduct"},
+			})
+		}
+
+		// products are not looked up by system name
+		t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		return newTestJSONResponse(t, http.StatusNotFound, map[string]string{})
+	})
+
+	adminPortal, err := threescaleapi.NewAdminPortal("https", "www.example.com", 443)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := threescaleapi.NewThreeScale(adminPortal, "12345", httpClient)
+
+	product := &capabilitiesv1beta1.Product{
+		Spec: capabilitiesv1beta1.ProductSpec{SystemName: "myproduct"},
+	}
+
+	productEntity, err := findRemoteProduct(product, client, logrtesting.NullLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if productEntity != nil {
+		t.Fatalf("product not synchronized by the custom resource found: %d", productEntity.ID())
+	}
+
+	product.Status.ID = &productID
+	productEntity, err = findRemoteProduct(product, client, logrtesting.NullLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if productEntity == nil || productEntity.ID() != productID {
+		t.Fatalf("product [%d] expected", productID)
+	}
+}
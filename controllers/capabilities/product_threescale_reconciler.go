@@ -18,15 +18,19 @@ type ProductThreescaleReconciler struct {
 	productEntity       *controllerhelper.ProductEntity
 	backendRemoteIndex  *controllerhelper.BackendAPIRemoteIndex
 	threescaleAPIClient *threescaleapi.ThreeScaleClient
+	drift               *driftDetector
+	dryRun              *controllerhelper.DryRunRecorder
 	logger              logr.Logger
 }
 
-func NewProductThreescaleReconciler(b *reconcilers.BaseReconciler, resource *capabilitiesv1beta1.Product, threescaleAPIClient *threescaleapi.ThreeScaleClient, backendRemoteIndex *controllerhelper.BackendAPIRemoteIndex) *ProductThreescaleReconciler {
+func NewProductThreescaleReconciler(b *reconcilers.BaseReconciler, resource *capabilitiesv1beta1.Product, threescaleAPIClient *threescaleapi.ThreeScaleClient, backendRemoteIndex *controllerhelper.BackendAPIRemoteIndex, drift *driftDetector, dryRun *controllerhelper.DryRunRecorder) *ProductThreescaleReconciler {
 	return &ProductThreescaleReconciler{
 		BaseReconciler:      b,
 		resource:            resource,
 		threescaleAPIClient: threescaleAPIClient,
 		backendRemoteIndex:  backendRemoteIndex,
+		drift:               drift,
+		dryRun:              dryRun,
 		logger:              b.Logger().WithValues("3scale Reconciler", resource.Name),
 	}
 }
@@ -37,27 +41,38 @@ func (t *ProductThreescaleReconciler) Reconcile() (*controllerhelper.ProductEnti
 		return nil, err
 	}
 	t.productEntity = productEntity
+	t.productEntity.SetDryRun(t.dryRun)
 
 	taskRunner := helper.NewTaskRunner(nil, t.logger)
-	taskRunner.AddTask("SyncProduct", t.syncProduct)
-	taskRunner.AddTask("SyncBackendUsage", t.syncBackendUsage)
-	taskRunner.AddTask("SyncProxy", t.syncProxy)
+	taskRunner.AddTask("SyncProduct", t.trackTask("SyncProduct", t.syncProduct))
+	taskRunner.AddTask("SyncBackendUsage", t.trackTask("SyncBackendUsage", t.syncBackendUsage))
+	taskRunner.AddTask("SyncProxy", t.trackTask("SyncProxy", t.syncProxy))
 	// First methods and metrics, then mapping rules.
 	// Mapping rules reference methods and metrics.
 	// When a method/metric is deleted,
 	// any orphan mapping rule will be deleted automatically by 3scale
-	taskRunner.AddTask("SyncMethods", t.syncMethods)
-	taskRunner.AddTask("SyncMetrics", t.syncMetrics)
-	taskRunner.AddTask("SyncMappingRules", t.syncMappingRules)
-	taskRunner.AddTask("SyncApplicationPlans", t.syncApplicationPlans)
-	taskRunner.AddTask("SyncPolicies", t.syncPolicies)
-	taskRunner.AddTask("SyncOIDCConfiguration", t.syncOIDCConfiguration)
+	taskRunner.AddTask("SyncMethods", t.trackTask("SyncMethods", t.syncMethods))
+	taskRunner.AddTask("SyncMetrics", t.trackTask("SyncMetrics", t.syncMetrics))
+	taskRunner.AddTask("SyncMappingRules", t.trackTask("SyncMappingRules", t.syncMappingRules))
+	taskRunner.AddTask("SyncApplicationPlans", t.trackTask("SyncApplicationPlans", t.syncApplicationPlans))
+	taskRunner.AddTask("SyncPolicies", t.trackTask("SyncPolicies", t.syncPolicies))
+	taskRunner.AddTask("SyncOIDCConfiguration", t.trackTask("SyncOIDCConfiguration", t.syncOIDCConfiguration))
+	// Deploy only when everything else has been synchronized.
+	// Not tracked for drift, the deployment is requested on every synchronization
+	if t.resource.Spec.DeployToStaging {
+		taskRunner.AddTask("DeployProxyToStaging", t.dryRun.Track("DeployProxyToStaging", t.deployProxyToStaging))
+	}
 
 	err = taskRunner.Run()
 	if err != nil {
 		return nil, err
 	}
 
+	if t.productEntity.IsPlanned() {
+		// The product does not exist yet, there is no product to report in the status
+		return nil, nil
+	}
+
 	return t.productEntity, nil
 }
 
@@ -86,6 +101,20 @@ func (t *ProductThreescaleReconciler) reconcile3scaleProduct() (*controllerhelpe
 		params := threescaleapi.Params{
 			"system_name": t.resource.Spec.SystemName,
 		}
+		if t.dryRun != nil {
+			t.dryRun.Create("product", threescaleapi.Params{
+				"system_name": t.resource.Spec.SystemName,
+				"name":        t.resource.Spec.Name,
+			})
+			// The rest of the changes are computed against an empty product
+			return controllerhelper.NewProductEntity(&threescaleapi.Product{
+				Element: threescaleapi.ProductItem{
+					ID:         controllerhelper.DryRunPlannedID,
+					Name:       t.resource.Spec.Name,
+					SystemName: t.resource.Spec.SystemName,
+				},
+			}, t.threescaleAPIClient, t.logger), nil
+		}
 		product, err := t.threescaleAPIClient.CreateProduct(t.resource.Spec.Name, params)
 		if err != nil {
 			return nil, fmt.Errorf("reconcile3scaleProduct product [%s]: %w", t.resource.Spec.SystemName, err)
@@ -96,3 +125,8 @@ func (t *ProductThreescaleReconciler) reconcile3scaleProduct() (*controllerhelpe
 
 	return controllerhelper.NewProductEntity(productObj, t.threescaleAPIClient, t.logger), nil
 }
+
+// trackTask wraps the task to record the task name of the drifted and dry-run changes
+func (t *ProductThreescaleReconciler) trackTask(name string, task func(interface{}) error) func(interface{}) error {
+	return t.dryRun.Track(name, t.drift.Track(name, task))
+}
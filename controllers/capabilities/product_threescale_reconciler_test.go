@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+	logrtesting "github.com/go-logr/logr/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestProductThreescaleReconcilerDryRunNewProduct(t *testing.T) {
+	httpClient := newTestHTTPClient(func(req *http.Request) *http.Response {
+		switch {
+		case req.Method == http.MethodGet && req.URL.Path == "/admin/api/services.json":
+			return newTestJSONResponse(t, http.StatusOK, &threescaleapi.ProductList{})
+		case req.Method == http.MethodGet && req.URL.Path == "/admin/api/backend_apis.json":
+			return newTestJSONResponse(t, http.StatusOK, &threescaleapi.BackendApiList{})
+		}
+
+		// the product does not exist, nothing else is read and nothing is modified
+		t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		return newTestJSONResponse(t, http.StatusNotFound, map[string]string{})
+	})
+
+	adminPortal, err := threescaleapi.NewAdminPortal("https", "www.example.com", 443)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := threescaleapi.NewThreeScale(adminPortal, "12345", httpClient)
+
+	product := &capabilitiesv1beta1.Product{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "myproduct",
+			Namespace:   "myns",
+			Annotations: map[string]string{capabilitiesv1beta1.DryRunAnnotation: "true"},
+		},
+		Spec: capabilitiesv1beta1.ProductSpec{
+			Name:       "My Product",
+			SystemName: "myproduct",
+			Methods: map[string]capabilitiesv1beta1.MethodSpec{
+				"method01": {Name: "Method 01"},
+			},
+			MappingRules: []capabilitiesv1beta1.MappingRuleSpec{
+				{HTTPMethod: "GET", Pattern: "/pets", MetricMethodRef: "method01", Increment: 1},
+			},
+			ApplicationPlans: map[string]capabilitiesv1beta1.ApplicationPlanSpec{
+				"basic": {
+					Limits: []capabilitiesv1beta1.LimitSpec{
+						{Period: "day", Value: 10, MetricMethodRef: capabilitiesv1beta1.MetricMethodRefSpec{SystemName: "hits"}},
+					},
+				},
+			},
+		},
+	}
+	product.SetDefaults(logrtesting.NullLogger{})
+
+	baseReconciler := newTestBaseReconciler(t, product)
+	backendRemoteIndex, err := controllerhelper.NewBackendAPIRemoteIndex(client, logrtesting.NullLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dryRun := controllerhelper.NewDryRunRecorder()
+	reconciler := NewProductThreescaleReconciler(baseReconciler, product, client, backendRemoteIndex, nil, dryRun)
+	productEntity, err := reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if productEntity != nil {
+		t.Fatalf("planned product reported: %d", productEntity.ID())
+	}
+
+	// the whole product is planned, not only its creation
+	planned := map[string]bool{}
+	for _, change := range dryRun.Changes() {
+		if change.Action == controllerhelper.DryRunActionCreate {
+			planned[change.Object] = true
+		}
+	}
+	for _, object := range []string{"product", "method", "mapping rule", "application plan", "limit"} {
+		if !planned[object] {
+			t.Errorf("%s creation not planned: %v", object, dryRun.Changes())
+		}
+	}
+}
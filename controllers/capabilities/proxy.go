@@ -210,3 +210,23 @@ func (t *ProductThreescaleReconciler) syncProxyOIDC(params threescaleapi.Params,
 		params["jwt_claim_with_client_id_type"] = *oidcSpec.JwtClaimWithClientIDType
 	}
 }
+
+func (t *ProductThreescaleReconciler) deployProxyToStaging(_ interface{}) error {
+	// 3scale does not create new staging version when configuration has not changed
+	err := t.productEntity.PromoteProxyToStaging()
+	if err != nil {
+		return fmt.Errorf("Error deploying product [%s] proxy to staging: %w", t.resource.Spec.SystemName, err)
+	}
+
+	stagingConfig, err := t.productEntity.StagingProxyConfig()
+	if err != nil {
+		return fmt.Errorf("Error deploying product [%s] proxy to staging: %w", t.resource.Spec.SystemName, err)
+	}
+
+	if stagingConfig == nil {
+		return fmt.Errorf("Error deploying product [%s] proxy to staging: staging proxy config not found", t.resource.Spec.SystemName)
+	}
+
+	t.logger.V(1).Info("proxy deployed to staging", "version", stagingConfig.Version)
+	return nil
+}
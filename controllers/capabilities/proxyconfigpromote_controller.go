@@ -0,0 +1,209 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+	"github.com/3scale/3scale-operator/version"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ProxyConfigPromoteReconciler reconciles a ProxyConfigPromote object
+type ProxyConfigPromoteReconciler struct {
+	*reconcilers.BaseReconciler
+}
+
+// blank assignment to verify that ProxyConfigPromoteReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ProxyConfigPromoteReconciler{}
+
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=proxyconfigpromotes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=proxyconfigpromotes/status,verbs=get;update;patch
+
+func (r *ProxyConfigPromoteReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Logger().WithValues("proxyconfigpromote", req.NamespacedName)
+	reqLogger.Info("Reconcile ProxyConfigPromote", "Operator version", version.Version)
+
+	// Fetch the instance
+	proxyConfigPromoteCR := &capabilitiesv1beta1.ProxyConfigPromote{}
+	err := r.Client().Get(context.TODO(), req.NamespacedName, proxyConfigPromoteCR)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			reqLogger.Info("resource not found. Ignoring since object must have been deleted")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	if reqLogger.V(1).Enabled() {
+		jsonData, err := json.MarshalIndent(proxyConfigPromoteCR, "", "  ")
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		reqLogger.V(1).Info(string(jsonData))
+	}
+
+	// Ignore deleted resource, nothing to be removed from 3scale
+	if proxyConfigPromoteCR.GetDeletionTimestamp() != nil {
+		reqLogger.Info("marked to be deleted")
+		return ctrl.Result{}, nil
+	}
+
+	// The promotion runs once per generation.
+	// Otherwise, later product changes deployed to staging would keep being promoted to production.
+	if proxyConfigPromoteCR.Status.ObservedGeneration == proxyConfigPromoteCR.Generation &&
+		proxyConfigPromoteCR.Status.Conditions.IsTrueFor(capabilitiesv1beta1.ProxyConfigPromoteReadyConditionType) {
+		reqLogger.Info("proxy config already promoted for the current generation")
+		return ctrl.Result{}, nil
+	}
+
+	statusReconciler, reconcileErr := r.reconcileSpec(proxyConfigPromoteCR, reqLogger)
+	statusResult, statusUpdateErr := statusReconciler.Reconcile()
+	if statusUpdateErr != nil {
+		if reconcileErr != nil {
+			return ctrl.Result{}, fmt.Errorf("Failed to reconcile proxyconfigpromote: %v. Failed to update status: %w", reconcileErr, statusUpdateErr)
+		}
+
+		return ctrl.Result{}, fmt.Errorf("Failed to update proxyconfigpromote status: %w", statusUpdateErr)
+	}
+
+	if statusResult.Requeue {
+		return statusResult, nil
+	}
+
+	if reconcileErr != nil {
+		if helper.IsInvalidSpecError(reconcileErr) {
+			// On Validation error, no need to retry as spec is not valid and needs to be changed
+			reqLogger.Info("ERROR", "spec validation error", reconcileErr)
+			r.EventRecorder().Eventf(proxyConfigPromoteCR, corev1.EventTypeWarning, "Invalid proxyconfigpromote spec", "%v", reconcileErr)
+			return ctrl.Result{}, nil
+		}
+
+		if helper.IsOrphanSpecError(reconcileErr) {
+			// On Orphan spec error, retry
+			reqLogger.Info("orphan", "message", reconcileErr)
+			return ctrl.Result{Requeue: true}, nil
+		}
+
+		reqLogger.Error(reconcileErr, "Failed to reconcile")
+		r.EventRecorder().Eventf(proxyConfigPromoteCR, corev1.EventTypeWarning, "ReconcileError", "%v", reconcileErr)
+		return ctrl.Result{}, reconcileErr
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *ProxyConfigPromoteReconciler) reconcileSpec(proxyConfigPromoteCR *capabilitiesv1beta1.ProxyConfigPromote, logger logr.Logger) (*ProxyConfigPromoteStatusReconciler, error) {
+	err := r.validateSpec(proxyConfigPromoteCR)
+	if err != nil {
+		statusReconciler := NewProxyConfigPromoteStatusReconciler(r.BaseReconciler, proxyConfigPromoteCR, "", nil, err)
+		return statusReconciler, err
+	}
+
+	productCR, err := r.findProduct(proxyConfigPromoteCR)
+	if err != nil {
+		statusReconciler := NewProxyConfigPromoteStatusReconciler(r.BaseReconciler, proxyConfigPromoteCR, "", nil, err)
+		return statusReconciler, err
+	}
+
+	// The proxy config belongs to the tenant of the product
+	providerAccount, err := controllerhelper.LookupProviderAccount(r.Client(), productCR.Namespace, productCR.Spec.ProviderAccountRef, productCR.Spec.ProviderAccountNamespace, productCR.Spec.TenantRef, logger)
+	if err != nil {
+		statusReconciler := NewProxyConfigPromoteStatusReconciler(r.BaseReconciler, proxyConfigPromoteCR, "", nil, err)
+		return statusReconciler, err
+	}
+
+	threescaleAPIClient, err := controllerhelper.PortaClient(providerAccount)
+	if err != nil {
+		statusReconciler := NewProxyConfigPromoteStatusReconciler(r.BaseReconciler, proxyConfigPromoteCR, providerAccount.AdminURLStr, nil, err)
+		return statusReconciler, err
+	}
+
+	reconciler := NewProxyConfigPromoteThreescaleReconciler(r.BaseReconciler, proxyConfigPromoteCR, productCR, threescaleAPIClient, providerAccount.AdminURLStr, logger)
+	versions, err := reconciler.Reconcile()
+
+	statusReconciler := NewProxyConfigPromoteStatusReconciler(r.BaseReconciler, proxyConfigPromoteCR, providerAccount.AdminURLStr, versions, err)
+	return statusReconciler, err
+}
+
+func (r *ProxyConfigPromoteReconciler) validateSpec(resource *capabilitiesv1beta1.ProxyConfigPromote) error {
+	errors := field.ErrorList{}
+	errors = append(errors, resource.Validate()...)
+
+	if len(errors) == 0 {
+		return nil
+	}
+
+	return &helper.SpecFieldError{
+		ErrorType:      helper.InvalidError,
+		FieldErrorList: errors,
+	}
+}
+
+func (r *ProxyConfigPromoteReconciler) findProduct(proxyConfigPromoteCR *capabilitiesv1beta1.ProxyConfigPromote) (*capabilitiesv1beta1.Product, error) {
+	productFldPath := field.NewPath("spec").Child("productRef")
+
+	productCR := &capabilitiesv1beta1.Product{}
+	productKey := types.NamespacedName{Name: proxyConfigPromoteCR.Spec.ProductRef.Name, Namespace: proxyConfigPromoteCR.Namespace}
+	if err := r.Client().Get(r.Context(), productKey, productCR); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, &helper.SpecFieldError{
+				ErrorType: helper.OrphanError,
+				FieldErrorList: field.ErrorList{
+					field.Invalid(productFldPath, proxyConfigPromoteCR.Spec.ProductRef, "product resource not found"),
+				},
+			}
+		}
+
+		return nil, err
+	}
+
+	// Promote only configuration already sync'ed with 3scale
+	if !productCR.IsSynced() || productCR.Status.ID == nil || productCR.Status.ObservedGeneration != productCR.Generation {
+		return nil, &helper.SpecFieldError{
+			ErrorType: helper.OrphanError,
+			FieldErrorList: field.ErrorList{
+				field.Invalid(productFldPath, proxyConfigPromoteCR.Spec.ProductRef, "product resource not synced"),
+			},
+		}
+	}
+
+	return productCR, nil
+}
+
+func (r *ProxyConfigPromoteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&capabilitiesv1beta1.ProxyConfigPromote{}).
+		Complete(r)
+}
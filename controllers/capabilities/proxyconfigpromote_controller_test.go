@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/common"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// proxyConfigServer fakes the 3scale proxy config endpoints of product 3.
+// Every deployment creates a new staging version
+type proxyConfigServer struct {
+	mu                sync.Mutex
+	stagingVersion    int
+	productionVersion int
+	promotions        []string
+}
+
+func (s *proxyConfigServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case req.Method == http.MethodPost && req.URL.Path == "/admin/api/services/3/proxy/deploy.json":
+		s.stagingVersion++
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"proxy":{"service_id":3}}`)
+	case req.Method == http.MethodGet && req.URL.Path == "/admin/api/services/3/proxy/configs/sandbox/latest.json":
+		fmt.Fprintf(w, `{"proxy_config":{"version":%d,"environment":"sandbox"}}`, s.stagingVersion)
+	case req.Method == http.MethodGet && req.URL.Path == "/admin/api/services/3/proxy/configs/production/latest.json":
+		if s.productionVersion == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"status":"Not found"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"proxy_config":{"version":%d,"environment":"production"}}`, s.productionVersion)
+	case req.Method == http.MethodPost && req.URL.Path == fmt.Sprintf("/admin/api/services/3/proxy/configs/sandbox/%d/promote.json", s.stagingVersion):
+		s.productionVersion = s.stagingVersion
+		s.promotions = append(s.promotions, req.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"proxy_config":{"version":%d,"environment":"production"}}`, s.productionVersion)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"status":"Not found"}`)
+	}
+}
+
+func newTestSyncedProduct() *capabilitiesv1beta1.Product {
+	var productID int64 = 3
+
+	return &capabilitiesv1beta1.Product{
+		ObjectMeta: metav1.ObjectMeta{Name: "myproduct", Namespace: "myns", Generation: 1},
+		Spec:       capabilitiesv1beta1.ProductSpec{Name: "My Product", SystemName: "myproduct"},
+		Status: capabilitiesv1beta1.ProductStatus{
+			ID:                 &productID,
+			ObservedGeneration: 1,
+			Conditions: common.Conditions{
+				{Type: capabilitiesv1beta1.ProductSyncedConditionType, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+}
+
+func testProxyConfigPromoteReconcile(t *testing.T, r *ProxyConfigPromoteReconciler, key types.NamespacedName) *capabilitiesv1beta1.ProxyConfigPromote {
+	t.Helper()
+	_, err := r.Reconcile(reconcile.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reconciled := &capabilitiesv1beta1.ProxyConfigPromote{}
+	err = r.Client().Get(context.TODO(), key, reconciled)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return reconciled
+}
+
+func TestProxyConfigPromoteReconcilerOncePerGeneration(t *testing.T) {
+	proxyServer := &proxyConfigServer{}
+	server, secret := newTestThreescaleServer(t, "myns", proxyServer.ServeHTTP)
+	defer server.Close()
+
+	proxyConfigPromoteCR := &capabilitiesv1beta1.ProxyConfigPromote{
+		ObjectMeta: metav1.ObjectMeta{Name: "mypromote", Namespace: "myns", Generation: 1},
+		Spec: capabilitiesv1beta1.ProxyConfigPromoteSpec{
+			ProductRef: corev1.LocalObjectReference{Name: "myproduct"},
+			Production: true,
+		},
+	}
+
+	r := &ProxyConfigPromoteReconciler{BaseReconciler: newTestBaseReconciler(t, proxyConfigPromoteCR, newTestSyncedProduct(), secret)}
+	key := types.NamespacedName{Name: "mypromote", Namespace: "myns"}
+
+	reconciled := testProxyConfigPromoteReconcile(t, r, key)
+	if !reconciled.Status.Conditions.IsTrueFor(capabilitiesv1beta1.ProxyConfigPromoteReadyConditionType) {
+		t.Fatalf("ready condition expected: %v", reconciled.Status.Conditions)
+	}
+	if reconciled.Status.LatestProductionVersion == nil || *reconciled.Status.LatestProductionVersion != 1 {
+		t.Fatalf("unexpected production version %v", reconciled.Status.LatestProductionVersion)
+	}
+	if len(proxyServer.promotions) != 1 {
+		t.Fatalf("unexpected promotions %v", proxyServer.promotions)
+	}
+
+	// the same generation is not deployed nor promoted again
+	reconciled = testProxyConfigPromoteReconcile(t, r, key)
+	if proxyServer.stagingVersion != 1 || len(proxyServer.promotions) != 1 {
+		t.Fatalf("promoted again: staging version %d, promotions %v", proxyServer.stagingVersion, proxyServer.promotions)
+	}
+
+	// new generation
+	reconciled.Generation = 2
+	err := r.Client().Update(context.TODO(), reconciled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reconciled = testProxyConfigPromoteReconcile(t, r, key)
+	if len(proxyServer.promotions) != 2 {
+		t.Fatalf("new generation not promoted: %v", proxyServer.promotions)
+	}
+	if reconciled.Status.ObservedGeneration != 2 || reconciled.Status.LatestProductionVersion == nil || *reconciled.Status.LatestProductionVersion != 2 {
+		t.Fatalf("unexpected status %v", reconciled.Status)
+	}
+}
+
+func TestProxyConfigPromoteReconcilerRetriesFailedGeneration(t *testing.T) {
+	proxyServer := &proxyConfigServer{}
+	server, secret := newTestThreescaleServer(t, "myns", proxyServer.ServeHTTP)
+	defer server.Close()
+
+	// the previous promotion of the current generation failed
+	proxyConfigPromoteCR := &capabilitiesv1beta1.ProxyConfigPromote{
+		ObjectMeta: metav1.ObjectMeta{Name: "mypromote", Namespace: "myns", Generation: 1},
+		Spec: capabilitiesv1beta1.ProxyConfigPromoteSpec{
+			ProductRef: corev1.LocalObjectReference{Name: "myproduct"},
+			Production: true,
+		},
+		Status: capabilitiesv1beta1.ProxyConfigPromoteStatus{
+			ObservedGeneration: 1,
+			Conditions: common.Conditions{
+				{Type: capabilitiesv1beta1.ProxyConfigPromoteReadyConditionType, Status: corev1.ConditionFalse},
+				{Type: capabilitiesv1beta1.ProxyConfigPromoteFailedConditionType, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+
+	r := &ProxyConfigPromoteReconciler{BaseReconciler: newTestBaseReconciler(t, proxyConfigPromoteCR, newTestSyncedProduct(), secret)}
+	reconciled := testProxyConfigPromoteReconcile(t, r, types.NamespacedName{Name: "mypromote", Namespace: "myns"})
+	if len(proxyServer.promotions) != 1 {
+		t.Fatalf("failed generation not retried: %v", proxyServer.promotions)
+	}
+	if !reconciled.Status.Conditions.IsTrueFor(capabilitiesv1beta1.ProxyConfigPromoteReadyConditionType) {
+		t.Fatalf("ready condition expected: %v", reconciled.Status.Conditions)
+	}
+}
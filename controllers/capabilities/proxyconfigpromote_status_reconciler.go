@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"fmt"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/common"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type ProxyConfigPromoteStatusReconciler struct {
+	*reconcilers.BaseReconciler
+	proxyConfigPromoteCR *capabilitiesv1beta1.ProxyConfigPromote
+	providerAccountHost  string
+	proxyConfigVersions  *proxyConfigVersions
+	reconcileError       error
+	logger               logr.Logger
+}
+
+func NewProxyConfigPromoteStatusReconciler(b *reconcilers.BaseReconciler,
+	proxyConfigPromoteCR *capabilitiesv1beta1.ProxyConfigPromote,
+	providerAccountHost string,
+	proxyConfigVersions *proxyConfigVersions,
+	reconcileError error,
+) *ProxyConfigPromoteStatusReconciler {
+	return &ProxyConfigPromoteStatusReconciler{
+		BaseReconciler:       b,
+		proxyConfigPromoteCR: proxyConfigPromoteCR,
+		providerAccountHost:  providerAccountHost,
+		proxyConfigVersions:  proxyConfigVersions,
+		reconcileError:       reconcileError,
+		logger:               b.Logger().WithValues("Status Reconciler", proxyConfigPromoteCR.Name),
+	}
+}
+
+func (s *ProxyConfigPromoteStatusReconciler) Reconcile() (reconcile.Result, error) {
+	s.logger.V(1).Info("START")
+
+	newStatus, err := s.calculateStatus()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	equalStatus := s.proxyConfigPromoteCR.Status.Equals(newStatus, s.logger)
+	s.logger.V(1).Info("Status", "status is different", !equalStatus)
+	s.logger.V(1).Info("Status", "generation is different", s.proxyConfigPromoteCR.Generation != s.proxyConfigPromoteCR.Status.ObservedGeneration)
+	if equalStatus && s.proxyConfigPromoteCR.Generation == s.proxyConfigPromoteCR.Status.ObservedGeneration {
+		// Steady state
+		s.logger.V(1).Info("Status steady state, status was not updated")
+		return reconcile.Result{}, nil
+	}
+
+	// Save the generation number we acted on, otherwise we might wrongfully indicate
+	// that we've seen a spec update when we retry.
+	// TODO: This can clobber an update if we allow multiple agents to write to the
+	// same status.
+	newStatus.ObservedGeneration = s.proxyConfigPromoteCR.Generation
+
+	s.logger.V(1).Info("Updating Status", "sequence no:", fmt.Sprintf("sequence No: %v->%v", s.proxyConfigPromoteCR.Status.ObservedGeneration, newStatus.ObservedGeneration))
+
+	s.proxyConfigPromoteCR.Status = *newStatus
+	updateErr := s.Client().Status().Update(s.Context(), s.proxyConfigPromoteCR)
+	if updateErr != nil {
+		// Ignore conflicts, resource might just be outdated.
+		if errors.IsConflict(updateErr) {
+			s.logger.Info("Failed to update status: resource might just be outdated")
+			return reconcile.Result{Requeue: true}, nil
+		}
+
+		return reconcile.Result{}, fmt.Errorf("Failed to update status: %w", updateErr)
+	}
+	return reconcile.Result{}, nil
+}
+
+func (s *ProxyConfigPromoteStatusReconciler) calculateStatus() (*capabilitiesv1beta1.ProxyConfigPromoteStatus, error) {
+	// If there is an error and s.proxyConfigVersions is nil, do not change status fields read from it
+	// Initialize with existing data for data coming from 3scale
+	// just in case in this reconciliation loop something goes wrong and avoid replacing right data with nil
+	newStatus := &capabilitiesv1beta1.ProxyConfigPromoteStatus{
+		ProductID:               s.proxyConfigPromoteCR.Status.ProductID,
+		LatestStagingVersion:    s.proxyConfigPromoteCR.Status.LatestStagingVersion,
+		LatestProductionVersion: s.proxyConfigPromoteCR.Status.LatestProductionVersion,
+		ProviderAccountHost:     s.proxyConfigPromoteCR.Status.ProviderAccountHost,
+		Conditions:              s.proxyConfigPromoteCR.Status.Conditions.Copy(),
+		ObservedGeneration:      s.proxyConfigPromoteCR.Status.ObservedGeneration,
+	}
+
+	if s.proxyConfigVersions != nil {
+		newStatus.ProductID = &s.proxyConfigVersions.productID
+		newStatus.LatestStagingVersion = s.proxyConfigVersions.stagingVersion
+		newStatus.LatestProductionVersion = s.proxyConfigVersions.productionVersion
+	}
+
+	if s.providerAccountHost != "" {
+		newStatus.ProviderAccountHost = s.providerAccountHost
+	}
+
+	newStatus.Conditions.SetCondition(s.invalidCondition())
+	newStatus.Conditions.SetCondition(s.readyCondition())
+	newStatus.Conditions.SetCondition(s.orphanCondition())
+	newStatus.Conditions.SetCondition(s.failedCondition())
+
+	return newStatus, nil
+}
+
+func (s *ProxyConfigPromoteStatusReconciler) readyCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.ProxyConfigPromoteReadyConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if s.reconcileError == nil {
+		condition.Status = corev1.ConditionTrue
+	}
+
+	return condition
+}
+
+func (s *ProxyConfigPromoteStatusReconciler) invalidCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.ProxyConfigPromoteInvalidConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if helper.IsInvalidSpecError(s.reconcileError) {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = s.reconcileError.Error()
+	}
+
+	return condition
+}
+
+func (s *ProxyConfigPromoteStatusReconciler) failedCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.ProxyConfigPromoteFailedConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if s.reconcileError != nil {
+		// only activate this condition when others are false and still there is an error
+
+		otherConditionsFalse := []bool{
+			s.invalidCondition().IsFalse(),
+			s.orphanCondition().IsFalse(),
+		}
+
+		if helper.All(otherConditionsFalse) {
+			condition.Status = corev1.ConditionTrue
+			condition.Message = s.reconcileError.Error()
+		}
+	}
+
+	return condition
+}
+
+func (s *ProxyConfigPromoteStatusReconciler) orphanCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.ProxyConfigPromoteOrphanConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if helper.IsOrphanSpecError(s.reconcileError) {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = s.reconcileError.Error()
+	}
+
+	return condition
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+	"github.com/go-logr/logr"
+)
+
+const (
+	proxyConfigStagingEnv    = "sandbox"
+	proxyConfigProductionEnv = "production"
+)
+
+// proxyConfigVersions holds the latest proxy configuration versions of a 3scale product
+type proxyConfigVersions struct {
+	productID         int64
+	stagingVersion    *int64
+	productionVersion *int64
+}
+
+type ProxyConfigPromoteThreescaleReconciler struct {
+	*reconcilers.BaseReconciler
+	proxyConfigPromoteCR *capabilitiesv1beta1.ProxyConfigPromote
+	productCR            *capabilitiesv1beta1.Product
+	threescaleAPIClient  *threescaleapi.ThreeScaleClient
+	providerAccountHost  string
+	logger               logr.Logger
+}
+
+func NewProxyConfigPromoteThreescaleReconciler(b *reconcilers.BaseReconciler,
+	proxyConfigPromoteCR *capabilitiesv1beta1.ProxyConfigPromote,
+	productCR *capabilitiesv1beta1.Product,
+	threescaleAPIClient *threescaleapi.ThreeScaleClient,
+	providerAccountHost string,
+	logger logr.Logger,
+) *ProxyConfigPromoteThreescaleReconciler {
+	return &ProxyConfigPromoteThreescaleReconciler{
+		BaseReconciler:       b,
+		proxyConfigPromoteCR: proxyConfigPromoteCR,
+		productCR:            productCR,
+		threescaleAPIClient:  threescaleAPIClient,
+		providerAccountHost:  providerAccountHost,
+		logger:               logger.WithValues("3scale Reconciler", providerAccountHost),
+	}
+}
+
+func (s *ProxyConfigPromoteThreescaleReconciler) Reconcile() (*proxyConfigVersions, error) {
+	s.logger.V(1).Info("START")
+
+	// product resource is synced, hence ID is not nil
+	productID := *s.productCR.Status.ID
+
+	// 3scale does not create new staging version when configuration has not changed
+	_, err := s.threescaleAPIClient.DeployProductProxy(productID)
+	if err != nil {
+		return nil, fmt.Errorf("Error deploying product [%s] proxy config to staging: %w", s.productCR.Spec.SystemName, err)
+	}
+
+	stagingConfig, err := s.latestProxyConfig(productID, proxyConfigStagingEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	if stagingConfig == nil {
+		return nil, fmt.Errorf("Error reading product [%s] staging proxy config: not found", s.productCR.Spec.SystemName)
+	}
+
+	productionConfig, err := s.latestProxyConfig(productID, proxyConfigProductionEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.proxyConfigPromoteCR.Spec.Production && (productionConfig == nil || productionConfig.Version != stagingConfig.Version) {
+		s.logger.Info("promoting proxy config to production", "version", stagingConfig.Version)
+		promoted, err := s.threescaleAPIClient.PromoteProxyConfig(
+			strconv.FormatInt(productID, 10), proxyConfigStagingEnv,
+			strconv.Itoa(stagingConfig.Version), proxyConfigProductionEnv,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("Error promoting product [%s] proxy config version [%d] to production: %w", s.productCR.Spec.SystemName, stagingConfig.Version, err)
+		}
+		productionConfig = &promoted.ProxyConfig
+	}
+
+	versions := &proxyConfigVersions{productID: productID}
+	stagingVersion := int64(stagingConfig.Version)
+	versions.stagingVersion = &stagingVersion
+	if productionConfig != nil {
+		productionVersion := int64(productionConfig.Version)
+		versions.productionVersion = &productionVersion
+	}
+
+	return versions, nil
+}
+
+// latestProxyConfig returns nil when the environment does not have any proxy config
+func (s *ProxyConfigPromoteThreescaleReconciler) latestProxyConfig(productID int64, env string) (*threescaleapi.ProxyConfig, error) {
+	proxyConfigElement, err := s.threescaleAPIClient.GetLatestProxyConfig(strconv.FormatInt(productID, 10), env)
+	if err != nil {
+		if threescaleapi.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Error reading product [%s] latest %s proxy config: %w", s.productCR.Spec.SystemName, env, err)
+	}
+
+	return &proxyConfigElement.ProxyConfig, nil
+}
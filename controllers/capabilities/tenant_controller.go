@@ -19,19 +19,24 @@ package controllers
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
+	porta_client_pkg "github.com/3scale/3scale-porta-go-client/client"
 	"github.com/go-logr/logr"
-	v1 "k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	capabilitiesv1alpha1 "github.com/3scale/3scale-operator/apis/capabilities/v1alpha1"
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
 	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
 )
 
 // Secret field name with Tenant's admin user password
@@ -43,24 +48,41 @@ const TenantProviderKeySecretField = "token"
 // Tenant's credentials secret field name for admin domain url
 const TenantAdminDomainKeySecretField = "adminURL"
 
+// Tenant's credentials secret annotations with the latest access token rotation.
+// The status is recovered from the secret when the status update fails after the rotation
+const (
+	tenantSecretAccessTokenIDAnnotation              = "tenant.capabilities.3scale.net/access-token-id"
+	tenantSecretAccessTokenRotationTimeAnnotation    = "tenant.capabilities.3scale.net/access-token-rotation-time"
+	tenantSecretAccessTokenRotationRequestAnnotation = "tenant.capabilities.3scale.net/access-token-rotation-request"
+	// tenantSecretProviderKeyRegeneratedAnnotation is set once the provider key of the initial secret is replaced
+	tenantSecretProviderKeyRegeneratedAnnotation = "tenant.capabilities.3scale.net/provider-key-regenerated"
+)
+
+// tenantFinalizer schedules the 3scale tenant deletion when the custom resource is deleted
+const tenantFinalizer = "tenant.capabilities.3scale.net/finalizer"
+
 // TenantReconciler reconciles a Tenant object
 type TenantReconciler struct {
-	Client client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	*reconcilers.BaseReconciler
+
+	// ResyncPeriod is the period to synchronize again tenants that did not change.
+	// Zero disables the periodic resync.
+	ResyncPeriod time.Duration
 }
 
+// blank assignment to verify that TenantReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &TenantReconciler{}
+
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=tenants,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=tenants/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=capabilities.3scale.net,namespace=placeholder,resources=tenants/finalizers,verbs=get;list;watch;create;update;patch;delete
 
 func (r *TenantReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	_ = context.Background()
-	reqLogger := r.Log.WithValues("tenant", req.NamespacedName)
+	reqLogger := r.Logger().WithValues("tenant", req.NamespacedName)
 
 	// Fetch the Tenant instance
-	tenantR := &capabilitiesv1alpha1.Tenant{}
-	err := r.Client.Get(context.TODO(), req.NamespacedName, tenantR)
+	tenantR := &capabilitiesv1beta1.Tenant{}
+	err := r.Client().Get(context.TODO(), req.NamespacedName, tenantR)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
@@ -73,9 +95,32 @@ func (r *TenantReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
+	if reqLogger.V(1).Enabled() {
+		jsonData, err := json.MarshalIndent(tenantR, "", "  ")
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		reqLogger.V(1).Info(string(jsonData))
+	}
+
+	if tenantR.GetDeletionTimestamp() != nil {
+		return reconcileFinalizer(r.BaseReconciler, tenantR, tenantFinalizer, func() error {
+			return r.removeTenantFrom3scale(tenantR, reqLogger)
+		}, reqLogger)
+	}
+
+	finalizerAdded, err := ensureFinalizer(r.BaseReconciler, tenantR, tenantFinalizer)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if finalizerAdded {
+		reqLogger.Info("finalizer added. Requeueing.")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	changed := tenantR.SetDefaults()
 	if changed {
-		err = r.Client.Update(context.TODO(), tenantR)
+		err = r.UpdateResource(tenantR)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
@@ -84,43 +129,147 @@ func (r *TenantReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, nil
 	}
 
-	masterAccessToken, err := r.FetchMasterCredentials(r.Client, tenantR)
+	statusReconciler, reconcileErr := r.reconcileSpec(tenantR, reqLogger)
+	statusResult, statusUpdateErr := statusReconciler.Reconcile()
+	if statusUpdateErr != nil {
+		if reconcileErr != nil {
+			return ctrl.Result{}, fmt.Errorf("Failed to reconcile tenant: %v. Failed to update status: %w", reconcileErr, statusUpdateErr)
+		}
+
+		return ctrl.Result{}, fmt.Errorf("Failed to update tenant status: %w", statusUpdateErr)
+	}
+
+	if statusResult.Requeue {
+		return statusResult, nil
+	}
+
+	if reconcileErr != nil {
+		if helper.IsInvalidSpecError(reconcileErr) {
+			// On Validation error, no need to retry as spec is not valid and needs to be changed
+			reqLogger.Info("ERROR", "spec validation error", reconcileErr)
+			r.EventRecorder().Eventf(tenantR, corev1.EventTypeWarning, "Invalid tenant spec", "%v", reconcileErr)
+			return ctrl.Result{}, nil
+		}
+
+		reqLogger.Error(reconcileErr, "Error in tenant reconciliation")
+		r.EventRecorder().Eventf(tenantR, corev1.EventTypeWarning, "ReconcileError", "%v", reconcileErr)
+		return ctrl.Result{}, reconcileErr
+	}
+
+	reqLogger.Info("Tenant reconciled successfully")
+
+	if nextRotation, enabled := tenantR.NextAccessTokenRotation(time.Now()); enabled {
+		reqLogger.V(1).Info("Next access token rotation", "after", nextRotation)
+		if nextRotation <= 0 {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		if r.ResyncPeriod == 0 || nextRotation < r.ResyncPeriod {
+			return ctrl.Result{RequeueAfter: nextRotation}, nil
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: r.ResyncPeriod}, nil
+}
+
+func (r *TenantReconciler) reconcileSpec(tenantR *capabilitiesv1beta1.Tenant, logger logr.Logger) (*TenantStatusReconciler, error) {
+	err := r.validateSpec(tenantR)
 	if err != nil {
-		reqLogger.Error(err, "Error fetching master credentials secret")
-		// Error reading the object - requeue the request.
-		return ctrl.Result{}, err
+		return NewTenantStatusReconciler(r.BaseReconciler, tenantR, nil, nil, nil, nil, err), err
+	}
+
+	masterAccessToken, err := r.FetchMasterCredentials(tenantR)
+	if err != nil {
+		err = fmt.Errorf("Error fetching master credentials secret: %w", err)
+		return NewTenantStatusReconciler(r.BaseReconciler, tenantR, nil, nil, nil, nil, err), err
 	}
 
 	portaClient, err := controllerhelper.PortaClientFromURLString(tenantR.Spec.SystemMasterUrl, masterAccessToken)
 	if err != nil {
-		reqLogger.Error(err, "Error creating porta client object")
-		// Error reading the object - requeue the request.
-		return ctrl.Result{}, err
+		err = fmt.Errorf("Error creating porta client object: %w", err)
+		return NewTenantStatusReconciler(r.BaseReconciler, tenantR, nil, nil, nil, nil, err), err
 	}
 
-	internalReconciler := NewTenantInternalReconciler(r.Client, tenantR, portaClient, reqLogger)
-	err = internalReconciler.Run()
+	restClient, err := controllerhelper.RESTClient(&controllerhelper.ProviderAccount{
+		AdminURLStr: tenantR.Spec.SystemMasterUrl,
+		Token:       masterAccessToken,
+	})
 	if err != nil {
-		reqLogger.Error(err, "Error in tenant reconciliation")
-		// Error reading the object - requeue the request.
-		return ctrl.Result{}, err
+		err = fmt.Errorf("Error creating 3scale REST client object: %w", err)
+		return NewTenantStatusReconciler(r.BaseReconciler, tenantR, nil, nil, nil, nil, err), err
 	}
 
-	reqLogger.Info("Tenant reconciled successfully")
-	return ctrl.Result{}, nil
+	internalReconciler := NewTenantInternalReconciler(r.BaseReconciler, tenantR, portaClient, restClient, logger)
+	tenantDef, adminUserDef, err := internalReconciler.Run()
+
+	return NewTenantStatusReconciler(r.BaseReconciler, tenantR, tenantDef, adminUserDef, internalReconciler.AccessTokenRotation(), internalReconciler.PendingAccessTokenRevocations(), err), err
+}
+
+func (r *TenantReconciler) validateSpec(tenantR *capabilitiesv1beta1.Tenant) error {
+	errors := field.ErrorList{}
+	errors = append(errors, tenantR.Validate()...)
+
+	if len(errors) == 0 {
+		return nil
+	}
+
+	return &helper.SpecFieldError{
+		ErrorType:      helper.InvalidError,
+		FieldErrorList: errors,
+	}
+}
+
+// removeTenantFrom3scale schedules the deletion of the 3scale tenant referenced in the status.
+// 3scale deletes the tenant permanently after the grace period.
+func (r *TenantReconciler) removeTenantFrom3scale(tenantR *capabilitiesv1beta1.Tenant, logger logr.Logger) error {
+	if tenantR.Status.TenantId == 0 {
+		// 3scale tenant has not been created
+		return nil
+	}
+
+	masterAccessToken, err := r.FetchMasterCredentials(tenantR)
+	if err != nil {
+		return fmt.Errorf("Error fetching master credentials secret: %w", err)
+	}
+
+	portaClient, err := controllerhelper.PortaClientFromURLString(tenantR.Spec.SystemMasterUrl, masterAccessToken)
+	if err != nil {
+		return fmt.Errorf("Error creating porta client object: %w", err)
+	}
+
+	tenantDef, err := portaClient.ShowTenant(tenantR.Status.TenantId)
+	if err != nil {
+		if porta_client_pkg.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Error reading tenant [%d]: %w", tenantR.Status.TenantId, err)
+	}
+
+	if tenantDef.Signup.Account.State == controllerhelper.AccountStateScheduledForDeletion {
+		logger.Info("tenant already scheduled for deletion", "TenantId", tenantR.Status.TenantId)
+		return nil
+	}
+
+	err = portaClient.DeleteTenant(tenantR.Status.TenantId)
+	if err != nil && !porta_client_pkg.IsNotFound(err) {
+		return fmt.Errorf("Error deleting tenant [%d]: %w", tenantR.Status.TenantId, err)
+	}
+
+	logger.Info("tenant scheduled for deletion in 3scale", "TenantId", tenantR.Status.TenantId)
+	return nil
 }
 
 func (r *TenantReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&capabilitiesv1alpha1.Tenant{}).
+		For(&capabilitiesv1beta1.Tenant{}).
 		Complete(r)
 }
 
 // FetchMasterCredentials get secret using k8s client
-func (r *TenantReconciler) FetchMasterCredentials(k8sClient client.Client, tenantR *capabilitiesv1alpha1.Tenant) (string, error) {
-	masterCredentialsSecret := &v1.Secret{}
+func (r *TenantReconciler) FetchMasterCredentials(tenantR *capabilitiesv1beta1.Tenant) (string, error) {
+	masterCredentialsSecret := &corev1.Secret{}
 
-	err := k8sClient.Get(context.TODO(),
+	err := r.Client().Get(context.TODO(),
 		types.NamespacedName{
 			Name:      tenantR.Spec.MasterCredentialsRef.Name,
 			Namespace: tenantR.Spec.MasterCredentialsRef.Namespace,
@@ -3,63 +3,98 @@ package controllers
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
-	"reflect"
+	"strconv"
+	"time"
 
-	apiv1alpha1 "github.com/3scale/3scale-operator/apis/capabilities/v1alpha1"
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
 	porta_client_pkg "github.com/3scale/3scale-porta-go-client/client"
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// tenantAccessTokenScopes are the scopes of the admin access tokens created on rotation
+var tenantAccessTokenScopes = []string{
+	controllerhelper.AccessTokenScopeAccountManagement,
+	controllerhelper.AccessTokenScopePolicyRegistry,
+}
+
+// TenantAccessTokenRotation describes a tenant access token rotation
+type TenantAccessTokenRotation struct {
+	AccessTokenID int64
+	RotationTime  metav1.Time
+	Request       string
+}
+
 // TenantInternalReconciler reconciles a Tenant object
 type TenantInternalReconciler struct {
-	k8sClient   client.Client
-	tenantR     *apiv1alpha1.Tenant
-	portaClient *porta_client_pkg.ThreeScaleClient
-	logger      logr.Logger
+	*reconcilers.BaseReconciler
+	tenantR             *capabilitiesv1beta1.Tenant
+	portaClient         *porta_client_pkg.ThreeScaleClient
+	restClient          *controllerhelper.ThreescaleRESTClient
+	accessTokenRotation *TenantAccessTokenRotation
+	// pendingAccessTokenRevocations are the IDs of previous access tokens not revoked yet
+	pendingAccessTokenRevocations []int64
+	logger                        logr.Logger
 }
 
 // NewTenantInternalReconciler constructs InternalReconciler object
-func NewTenantInternalReconciler(k8sClient client.Client, tenantR *apiv1alpha1.Tenant,
-	portaClient *porta_client_pkg.ThreeScaleClient, log logr.Logger) *TenantInternalReconciler {
+func NewTenantInternalReconciler(b *reconcilers.BaseReconciler, tenantR *capabilitiesv1beta1.Tenant,
+	portaClient *porta_client_pkg.ThreeScaleClient, restClient *controllerhelper.ThreescaleRESTClient,
+	log logr.Logger) *TenantInternalReconciler {
 	return &TenantInternalReconciler{
-		k8sClient:   k8sClient,
-		tenantR:     tenantR,
-		portaClient: portaClient,
-		logger:      log,
+		BaseReconciler:                b,
+		tenantR:                       tenantR,
+		portaClient:                   portaClient,
+		restClient:                    restClient,
+		pendingAccessTokenRevocations: append([]int64{}, tenantR.Status.PreviousAccessTokenIDs...),
+		logger:                        log,
 	}
 }
 
 // Run tenant reconciliation logic
 // Facts to reconcile:
 // - Have 3scale Tenant Account
+// - Have tenant account suspended or active as desired
 // - Have active admin user
-// - Have secret with tenant's access_token
-func (r *TenantInternalReconciler) Run() error {
+// - Have secret with tenant's access_token, rotated when required
+// Tenant and admin user objects are returned when available, even on error
+func (r *TenantInternalReconciler) Run() (*porta_client_pkg.Tenant, *porta_client_pkg.User, error) {
 	tenantDef, err := r.reconcileTenant()
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	adminUserDef, err := r.reconcileAdminUser(tenantDef)
 	if err != nil {
-		return err
+		return tenantDef, nil, err
 	}
 
-	err = r.reconcileAccessTokenSecret(tenantDef)
+	err = r.reconcileAccessTokenSecret(tenantDef, adminUserDef)
 	if err != nil {
-		return err
+		return tenantDef, adminUserDef, err
 	}
 
-	tenantStatus := r.getTenantStatus(tenantDef, adminUserDef)
+	return tenantDef, adminUserDef, nil
+}
+
+// AccessTokenRotation returns the access token rotation performed by Run, if any.
+// The rotation is available even when Run returns error
+func (r *TenantInternalReconciler) AccessTokenRotation() *TenantAccessTokenRotation {
+	return r.accessTokenRotation
+}
 
-	return r.updateTenantStatus(tenantStatus)
+// PendingAccessTokenRevocations returns the IDs of the previous access tokens not revoked yet.
+// The list is available even when Run returns error
+func (r *TenantInternalReconciler) PendingAccessTokenRevocations() []int64 {
+	return r.pendingAccessTokenRevocations
 }
 
 // This method makes sure that tenant exists, otherwise it will create one
@@ -87,6 +122,11 @@ func (r *TenantInternalReconciler) reconcileTenant() (*porta_client_pkg.Tenant,
 		}
 	}
 
+	err = r.reconcileTenantState(tenantDef)
+	if err != nil {
+		return nil, err
+	}
+
 	return tenantDef, nil
 }
 
@@ -137,6 +177,30 @@ func (r *TenantInternalReconciler) syncTenant(tenantDef *porta_client_pkg.Tenant
 	return nil
 }
 
+// This method makes sure tenant account is suspended or active as desired
+func (r *TenantInternalReconciler) reconcileTenantState(tenantDef *porta_client_pkg.Tenant) error {
+	account := &tenantDef.Signup.Account
+	isSuspended := account.State == controllerhelper.AccountStateSuspended
+
+	if r.tenantR.Spec.Suspended && !isSuspended {
+		r.logger.Info("Suspending tenant", "TenantId", account.ID)
+		accountDef, err := r.restClient.SuspendAccount(account.ID)
+		if err != nil {
+			return fmt.Errorf("Error suspending tenant [%d]: %w", account.ID, err)
+		}
+		account.State = accountDef.Account.State
+	} else if !r.tenantR.Spec.Suspended && isSuspended {
+		r.logger.Info("Resuming tenant", "TenantId", account.ID)
+		accountDef, err := r.restClient.ResumeAccount(account.ID)
+		if err != nil {
+			return fmt.Errorf("Error resuming tenant [%d]: %w", account.ID, err)
+		}
+		account.State = accountDef.Account.State
+	}
+
+	return nil
+}
+
 ////
 //
 // This method makes sure admin user:
@@ -156,8 +220,9 @@ func (r *TenantInternalReconciler) reconcileAdminUser(tenantDef *porta_client_pk
 	return adminUserDef, nil
 }
 
-// This method makes sure secret with tenant's access_token exists
-func (r *TenantInternalReconciler) reconcileAccessTokenSecret(tenantDef *porta_client_pkg.Tenant) error {
+// This method makes sure secret with tenant's access_token exists,
+// revokes previous access tokens and rotates the access token when required
+func (r *TenantInternalReconciler) reconcileAccessTokenSecret(tenantDef *porta_client_pkg.Tenant, adminUserDef *porta_client_pkg.User) error {
 	tenantProviderKeySecretNN := types.NamespacedName{
 		Name:      r.tenantR.Spec.TenantSecretRef.Name,
 		Namespace: r.tenantR.Spec.TenantSecretRef.Namespace,
@@ -168,15 +233,229 @@ func (r *TenantInternalReconciler) reconcileAccessTokenSecret(tenantDef *porta_c
 	}
 
 	if tenantProviderKeySecret == nil {
-		err = r.createTenantProviderKeySecret(tenantDef, tenantProviderKeySecretNN)
+		return r.createTenantProviderKeySecret(tenantDef, tenantProviderKeySecretNN)
+	}
+
+	r.logger.Info("Admin user access token secret already exists",
+		"Secret NS", tenantProviderKeySecretNN.Namespace, "Secret name", tenantProviderKeySecretNN.Name)
+
+	// The status may not have been updated after the latest rotation.
+	// The secret holds the rotation data, no rotation takes place until the status has caught up.
+	secretRotation := accessTokenRotationFromSecret(tenantProviderKeySecret)
+	statusTokenID := r.tenantR.Status.AccessTokenID
+	if secretRotation != nil && (statusTokenID == nil || *statusTokenID != secretRotation.AccessTokenID) {
+		r.logger.Info("Status outdated, recovering access token rotation from secret", "ID", secretRotation.AccessTokenID)
+		r.accessTokenRotation = secretRotation
+		if statusTokenID != nil {
+			r.addPendingAccessTokenRevocation(*statusTokenID)
+		}
+	}
+
+	currentToken, ok := tenantProviderKeySecret.Data[TenantProviderKeySecretField]
+	if !ok {
+		return fmt.Errorf("Not found tenant secret (ns: %s, name: %s) attribute: %s",
+			tenantProviderKeySecret.Namespace, tenantProviderKeySecret.Name, TenantProviderKeySecretField)
+	}
+
+	// Access tokens can only be revoked by the owner, hence, the current token is used for revocations
+	revokeErr := r.revokePendingAccessTokens(tenantDef, string(currentToken))
+
+	if r.accessTokenRotation == nil && r.tenantR.AccessTokenRotationRequired(time.Now()) {
+		err = r.rotateAccessToken(tenantDef, adminUserDef, tenantProviderKeySecret, string(currentToken))
 		if err != nil {
 			return err
 		}
-	} else {
-		r.logger.Info("Admin user access token secret already exists",
-			"Secret NS", tenantProviderKeySecretNN.Namespace, "Secret name", tenantProviderKeySecretNN.Name)
 	}
-	return nil
+
+	// The initial secret holds the provider key, it is not an access token and cannot be revoked.
+	// It is regenerated once the secret holds a rotated access token.
+	if accessTokenRotationFromSecret(tenantProviderKeySecret) != nil &&
+		tenantProviderKeySecret.GetAnnotations()[tenantSecretProviderKeyRegeneratedAnnotation] != "true" {
+		err = r.regenerateTenantProviderKey(tenantDef, tenantProviderKeySecret)
+		if err != nil {
+			return err
+		}
+	}
+
+	return revokeErr
+}
+
+// regenerateTenantProviderKey replaces the tenant provider key with a random one unknown to anyone.
+// The secret records the regeneration, failures are retried on the next reconciliation
+func (r *TenantInternalReconciler) regenerateTenantProviderKey(tenantDef *porta_client_pkg.Tenant, secret *v1.Secret) error {
+	providerApplication, err := r.findTenantProviderApplication(tenantDef)
+	if err != nil {
+		return err
+	}
+
+	keyBytes := make([]byte, 16)
+	_, err = rand.Read(keyBytes)
+	if err != nil {
+		return err
+	}
+
+	r.logger.Info("Regenerating tenant provider key", "TenantId", tenantDef.Signup.Account.ID, "ApplicationID", providerApplication.ID)
+	_, err = r.restClient.ChangeApplicationUserKey(tenantDef.Signup.Account.ID, providerApplication.ID, hex.EncodeToString(keyBytes))
+	if err != nil {
+		return fmt.Errorf("Error regenerating tenant provider key: %w", err)
+	}
+
+	annotations := secret.GetAnnotations()
+	annotations[tenantSecretProviderKeyRegeneratedAnnotation] = "true"
+	secret.SetAnnotations(annotations)
+	return r.UpdateResource(secret)
+}
+
+// This method replaces the access token in the tenant secret with a new admin access token.
+// The secret is updated with a single request, it is not modified when any step fails.
+// The previous access token is scheduled for revocation once the secret holds the new one.
+// The provider key of the initial secret is not an access token, it is regenerated instead.
+func (r *TenantInternalReconciler) rotateAccessToken(tenantDef *porta_client_pkg.Tenant, adminUserDef *porta_client_pkg.User, secret *v1.Secret, currentToken string) error {
+	adminURL, err := controllerhelper.URLFromDomain(tenantDef.Signup.Account.AdminDomain)
+	if err != nil {
+		return err
+	}
+
+	tenantClient, err := controllerhelper.RESTClient(&controllerhelper.ProviderAccount{
+		AdminURLStr: adminURL.String(),
+		Token:       currentToken,
+	})
+	if err != nil {
+		return err
+	}
+
+	rotationTime := metav1.Now()
+	tokenName := fmt.Sprintf("3scale-operator-%s", rotationTime.UTC().Format("20060102T150405Z"))
+	r.logger.Info("Rotating tenant access token", "TenantId", tenantDef.Signup.Account.ID, "UserID", adminUserDef.ID, "name", tokenName)
+	newToken, err := tenantClient.CreateAccessToken(adminUserDef.ID, tokenName,
+		controllerhelper.AccessTokenPermissionReadWrite, tenantAccessTokenScopes)
+	if err != nil {
+		return fmt.Errorf("Error creating tenant access token: %w", err)
+	}
+
+	rotation := &TenantAccessTokenRotation{
+		AccessTokenID: newToken.ID,
+		RotationTime:  rotationTime,
+		Request:       r.tenantR.GetAnnotations()[capabilitiesv1beta1.TenantRotateAccessTokenAnnotation],
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[TenantProviderKeySecretField] = []byte(newToken.Value)
+	secret.Data[TenantAdminDomainKeySecretField] = []byte(adminURL.String())
+	setAccessTokenRotationToSecret(secret, rotation)
+	err = r.UpdateResource(secret)
+	if err != nil {
+		// The secret still holds the current token, the new one would be unused.
+		// Access tokens can only be revoked by the owner, hence, the new token is used
+		newTokenClient, clientErr := controllerhelper.RESTClient(&controllerhelper.ProviderAccount{
+			AdminURLStr: adminURL.String(),
+			Token:       newToken.Value,
+		})
+		if clientErr == nil {
+			clientErr = newTokenClient.DeleteAccessToken(newToken.ID)
+		}
+		if clientErr != nil {
+			r.logger.Error(clientErr, "Failed to revoke unused tenant access token", "ID", newToken.ID)
+		}
+		return fmt.Errorf("Error updating tenant secret with rotated access token: %w", err)
+	}
+
+	previousTokenID := r.tenantR.Status.AccessTokenID
+	r.accessTokenRotation = rotation
+	r.logger.Info("Tenant access token rotated", "TenantId", tenantDef.Signup.Account.ID, "ID", newToken.ID)
+
+	if previousTokenID == nil || *previousTokenID == newToken.ID {
+		return nil
+	}
+
+	r.addPendingAccessTokenRevocation(*previousTokenID)
+	return r.revokePendingAccessTokens(tenantDef, newToken.Value)
+}
+
+func (r *TenantInternalReconciler) addPendingAccessTokenRevocation(id int64) {
+	for _, pendingID := range r.pendingAccessTokenRevocations {
+		if pendingID == id {
+			return
+		}
+	}
+
+	r.pendingAccessTokenRevocations = append(r.pendingAccessTokenRevocations, id)
+}
+
+// revokePendingAccessTokens revokes the previous access tokens using the given access token.
+// Revocations failing are kept pending to be retried
+func (r *TenantInternalReconciler) revokePendingAccessTokens(tenantDef *porta_client_pkg.Tenant, token string) error {
+	if len(r.pendingAccessTokenRevocations) == 0 {
+		return nil
+	}
+
+	adminURL, err := controllerhelper.URLFromDomain(tenantDef.Signup.Account.AdminDomain)
+	if err != nil {
+		return err
+	}
+
+	tenantClient, err := controllerhelper.RESTClient(&controllerhelper.ProviderAccount{
+		AdminURLStr: adminURL.String(),
+		Token:       token,
+	})
+	if err != nil {
+		return err
+	}
+
+	var revokeErr error
+	pending := []int64{}
+	for _, id := range r.pendingAccessTokenRevocations {
+		err := tenantClient.DeleteAccessToken(id)
+		if err != nil && !controllerhelper.IsThreescaleNotFound(err) {
+			r.logger.Error(err, "Failed to revoke previous tenant access token", "ID", id)
+			revokeErr = fmt.Errorf("Error revoking previous tenant access token [%d]: %w", id, err)
+			pending = append(pending, id)
+			continue
+		}
+
+		r.logger.Info("Previous tenant access token revoked", "ID", id)
+	}
+
+	r.pendingAccessTokenRevocations = pending
+	return revokeErr
+}
+
+// accessTokenRotationFromSecret returns the access token rotation recorded in the tenant secret annotations.
+// Returns nil when the secret holds no rotated access token
+func accessTokenRotationFromSecret(secret *v1.Secret) *TenantAccessTokenRotation {
+	annotations := secret.GetAnnotations()
+	accessTokenID, err := strconv.ParseInt(annotations[tenantSecretAccessTokenIDAnnotation], 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	rotationTime := metav1.Time{}
+	err = rotationTime.UnmarshalQueryParameter(annotations[tenantSecretAccessTokenRotationTimeAnnotation])
+	if err != nil {
+		return nil
+	}
+
+	return &TenantAccessTokenRotation{
+		AccessTokenID: accessTokenID,
+		RotationTime:  rotationTime,
+		Request:       annotations[tenantSecretAccessTokenRotationRequestAnnotation],
+	}
+}
+
+// setAccessTokenRotationToSecret records the access token rotation in the tenant secret annotations
+func setAccessTokenRotationToSecret(secret *v1.Secret, rotation *TenantAccessTokenRotation) {
+	annotations := secret.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	rotationTime, _ := rotation.RotationTime.MarshalQueryParameter()
+	annotations[tenantSecretAccessTokenIDAnnotation] = strconv.FormatInt(rotation.AccessTokenID, 10)
+	annotations[tenantSecretAccessTokenRotationTimeAnnotation] = rotationTime
+	annotations[tenantSecretAccessTokenRotationRequestAnnotation] = rotation.Request
+	secret.SetAnnotations(annotations)
 }
 
 // Create Tenant using porta client
@@ -200,7 +479,7 @@ func (r *TenantInternalReconciler) getAdminPassword() (string, error) {
 	// Get tenant admin password from secret reference
 	tenantAdminSecret := &v1.Secret{}
 
-	err := r.k8sClient.Get(context.TODO(),
+	err := r.Client().Get(context.TODO(),
 		types.NamespacedName{
 			Name:      r.tenantR.Spec.PasswordCredentialsRef.Name,
 			Namespace: r.tenantR.Namespace,
@@ -301,7 +580,7 @@ func (r *TenantInternalReconciler) activateAdminUser(tenantDef *porta_client_pkg
 func (r *TenantInternalReconciler) findAccessTokenSecret(nn types.NamespacedName) (*v1.Secret, error) {
 	adminAccessTokenSecret := &v1.Secret{}
 
-	err := r.k8sClient.Get(context.TODO(), nn, adminAccessTokenSecret)
+	err := r.Client().Get(context.TODO(), nn, adminAccessTokenSecret)
 
 	if err != nil && errors.IsNotFound(err) {
 		return nil, nil
@@ -343,54 +622,33 @@ func (r *TenantInternalReconciler) createTenantProviderKeySecret(tenantDef *port
 		},
 		Type: v1.SecretTypeOpaque,
 	}
-	r.addOwnerRefToObject(secret, r.asOwner(r.tenantR))
-	return r.k8sClient.Create(context.TODO(), secret)
+	err = r.SetOwnerReference(r.tenantR, secret)
+	if err != nil {
+		return err
+	}
+
+	return r.CreateResource(secret)
 }
 
 func (r *TenantInternalReconciler) findTenantProviderKey(tenantDef *porta_client_pkg.Tenant) (string, error) {
-	// Tenant Provider Key is available on provider application list
-	appList, err := r.portaClient.ListApplications(tenantDef.Signup.Account.ID)
+	providerApplication, err := r.findTenantProviderApplication(tenantDef)
 	if err != nil {
 		return "", err
 	}
 
-	if len(appList.Applications) != 1 {
-		return "", fmt.Errorf("Unexpected application list. TenantId: %d", tenantDef.Signup.Account.ID)
-	}
-
-	return appList.Applications[0].Application.UserKey, nil
+	return providerApplication.UserKey, nil
 }
 
-func (r *TenantInternalReconciler) getTenantStatus(tenantDef *porta_client_pkg.Tenant, adminUserDef *porta_client_pkg.User) *apiv1alpha1.TenantStatus {
-	return &apiv1alpha1.TenantStatus{
-		TenantId: tenantDef.Signup.Account.ID,
-		AdminId:  adminUserDef.ID,
+func (r *TenantInternalReconciler) findTenantProviderApplication(tenantDef *porta_client_pkg.Tenant) (*porta_client_pkg.Application, error) {
+	// Tenant Provider Key is available on provider application list
+	appList, err := r.portaClient.ListApplications(tenantDef.Signup.Account.ID)
+	if err != nil {
+		return nil, err
 	}
-}
 
-func (r *TenantInternalReconciler) updateTenantStatus(tenantStatus *apiv1alpha1.TenantStatus) error {
-	// don't update the status if there aren't any changes.
-	if reflect.DeepEqual(r.tenantR.Status, *tenantStatus) {
-		return nil
+	if len(appList.Applications) != 1 {
+		return nil, fmt.Errorf("Unexpected application list. TenantId: %d", tenantDef.Signup.Account.ID)
 	}
-	r.logger.Info("update tenant status", "status", tenantStatus)
-	r.tenantR.Status = *tenantStatus
-	return r.k8sClient.Status().Update(context.TODO(), r.tenantR)
-}
-
-// addOwnerRefToObject appends the desired OwnerReference to the object
-func (r *TenantInternalReconciler) addOwnerRefToObject(o metav1.Object, ref metav1.OwnerReference) {
-	o.SetOwnerReferences(append(o.GetOwnerReferences(), ref))
-}
 
-// asOwner returns an owner reference set as the tenant CR
-func (r *TenantInternalReconciler) asOwner(t *apiv1alpha1.Tenant) metav1.OwnerReference {
-	trueVar := true
-	return metav1.OwnerReference{
-		APIVersion: apiv1alpha1.GroupVersion.String(),
-		Kind:       apiv1alpha1.TenantKind,
-		Name:       t.Name,
-		UID:        t.UID,
-		Controller: &trueVar,
-	}
+	return &appList.Applications[0].Application, nil
 }
@@ -0,0 +1,255 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+
+	porta_client_pkg "github.com/3scale/3scale-porta-go-client/client"
+	logrtesting "github.com/go-logr/logr/testing"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// tenantAccessTokenServer fakes the 3scale access token endpoints
+// and the master endpoints of the provider application 7 of tenant 3.
+// Revocations of the IDs in failingRevocations fail
+type tenantAccessTokenServer struct {
+	mu                 sync.Mutex
+	createdTokens      int
+	revokedTokens      []int64
+	failingRevocations map[int64]bool
+	providerKeys       []string
+}
+
+func (s *tenantAccessTokenServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var id int64
+	switch {
+	case req.Method == http.MethodPost && req.URL.Path == "/admin/api/users/2/access_tokens.json":
+		s.createdTokens++
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"access_token":{"id":%d,"value":"newtoken"}}`, 100+s.createdTokens)
+	case req.Method == http.MethodDelete:
+		if _, err := fmt.Sscanf(req.URL.Path, "/admin/api/personal/access_tokens/%d.json", &id); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if s.failingRevocations[id] {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		s.revokedTokens = append(s.revokedTokens, id)
+		w.WriteHeader(http.StatusOK)
+	case req.Method == http.MethodGet && req.URL.Path == "/admin/api/accounts/3/applications.json":
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"applications":[{"application":{"id":7,"user_key":"providerkey"}}]}`)
+	case req.Method == http.MethodPut && req.URL.Path == "/admin/api/accounts/3/applications/7.json":
+		if err := req.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.providerKeys = append(s.providerKeys, req.PostForm.Get("user_key"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"application":{"id":7}}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newTestTenantInternalReconciler returns a reconciler with the master clients pointing to masterURL
+func newTestTenantInternalReconciler(t *testing.T, b *reconcilers.BaseReconciler, tenantR *capabilitiesv1beta1.Tenant, masterURL string) *TenantInternalReconciler {
+	t.Helper()
+	portaClient, err := controllerhelper.PortaClientFromURLString(masterURL, "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restClient, err := controllerhelper.RESTClient(&controllerhelper.ProviderAccount{AdminURLStr: masterURL, Token: "master"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewTenantInternalReconciler(b, tenantR, portaClient, restClient, logrtesting.NullLogger{})
+}
+
+func newTestTenantDef(adminURL string) *porta_client_pkg.Tenant {
+	tenantDef := &porta_client_pkg.Tenant{}
+	tenantDef.Signup.Account.ID = 3
+	tenantDef.Signup.Account.AdminDomain = adminURL
+	return tenantDef
+}
+
+func newTestTenant(accessTokenID *int64) *capabilitiesv1beta1.Tenant {
+	return &capabilitiesv1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "mytenant",
+			Namespace:   "myns",
+			Annotations: map[string]string{capabilitiesv1beta1.TenantRotateAccessTokenAnnotation: "1"},
+		},
+		Spec: capabilitiesv1beta1.TenantSpec{
+			TenantSecretRef: v1.SecretReference{Name: "mytenant-secret", Namespace: "myns"},
+		},
+		Status: capabilitiesv1beta1.TenantStatus{AccessTokenID: accessTokenID},
+	}
+}
+
+func newTestTenantSecret() *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mytenant-secret", Namespace: "myns"},
+		Data:       map[string][]byte{TenantProviderKeySecretField: []byte("currenttoken")},
+	}
+}
+
+func TestTenantAccessTokenRotationPendingRevocation(t *testing.T) {
+	server := &tenantAccessTokenServer{failingRevocations: map[int64]bool{10: true}}
+	httpServer := httptest.NewTLSServer(server)
+	defer httpServer.Close()
+
+	tenantDef := newTestTenantDef(httpServer.URL)
+	adminUserDef := &porta_client_pkg.User{ID: 2}
+
+	var previousTokenID int64 = 10
+	tenantR := newTestTenant(&previousTokenID)
+	reconciler := newTestTenantInternalReconciler(t, newTestBaseReconciler(t, tenantR, newTestTenantSecret()), tenantR, httpServer.URL)
+
+	// token rotated, previous token revocation fails
+	err := reconciler.reconcileAccessTokenSecret(tenantDef, adminUserDef)
+	if err == nil {
+		t.Fatal("revocation error expected")
+	}
+	if reconciler.AccessTokenRotation() == nil || reconciler.AccessTokenRotation().AccessTokenID != 101 {
+		t.Fatalf("unexpected rotation %v", reconciler.AccessTokenRotation())
+	}
+	if !reflect.DeepEqual([]int64{10}, reconciler.PendingAccessTokenRevocations()) {
+		t.Fatalf("unexpected pending revocations %v", reconciler.PendingAccessTokenRevocations())
+	}
+
+	secret := &v1.Secret{}
+	err = reconciler.Client().Get(reconciler.Context(), types.NamespacedName{Name: "mytenant-secret", Namespace: "myns"}, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data[TenantProviderKeySecretField]) != "newtoken" || secret.Annotations[tenantSecretAccessTokenIDAnnotation] != "101" {
+		t.Fatalf("unexpected secret %v", secret)
+	}
+
+	// status updated, revocation retried on the next reconciliation without rotating again
+	tenantR.Status.AccessTokenID = &reconciler.AccessTokenRotation().AccessTokenID
+	tenantR.Status.AccessTokenRotationTime = &reconciler.AccessTokenRotation().RotationTime
+	tenantR.Status.AccessTokenRotationRequest = reconciler.AccessTokenRotation().Request
+	tenantR.Status.PreviousAccessTokenIDs = reconciler.PendingAccessTokenRevocations()
+	server.failingRevocations = nil
+
+	reconciler = newTestTenantInternalReconciler(t, reconciler.BaseReconciler, tenantR, httpServer.URL)
+	err = reconciler.reconcileAccessTokenSecret(tenantDef, adminUserDef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reconciler.PendingAccessTokenRevocations()) != 0 {
+		t.Fatalf("unexpected pending revocations %v", reconciler.PendingAccessTokenRevocations())
+	}
+	if !reflect.DeepEqual([]int64{10}, server.revokedTokens) || server.createdTokens != 1 {
+		t.Fatalf("unexpected revoked tokens %v, created tokens %d", server.revokedTokens, server.createdTokens)
+	}
+}
+
+func TestTenantAccessTokenRotationOutdatedStatus(t *testing.T) {
+	server := &tenantAccessTokenServer{}
+	httpServer := httptest.NewTLSServer(server)
+	defer httpServer.Close()
+
+	tenantDef := newTestTenantDef(httpServer.URL)
+	adminUserDef := &porta_client_pkg.User{ID: 2}
+
+	// the secret holds token 20, but the status update failed after the rotation
+	var statusTokenID int64 = 10
+	tenantR := newTestTenant(&statusTokenID)
+	secret := newTestTenantSecret()
+	setAccessTokenRotationToSecret(secret, &TenantAccessTokenRotation{
+		AccessTokenID: 20,
+		RotationTime:  metav1.Now(),
+		Request:       "1",
+	})
+
+	reconciler := newTestTenantInternalReconciler(t, newTestBaseReconciler(t, tenantR, secret), tenantR, httpServer.URL)
+	err := reconciler.reconcileAccessTokenSecret(tenantDef, adminUserDef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if server.createdTokens != 0 {
+		t.Fatal("access token rotated while the status is outdated")
+	}
+	rotation := reconciler.AccessTokenRotation()
+	if rotation == nil || rotation.AccessTokenID != 20 || rotation.Request != "1" {
+		t.Fatalf("rotation not recovered from secret: %v", rotation)
+	}
+	if !reflect.DeepEqual([]int64{10}, server.revokedTokens) {
+		t.Fatalf("unexpected revoked tokens %v", server.revokedTokens)
+	}
+
+	statusReconciler := NewTenantStatusReconciler(reconciler.BaseReconciler, tenantR, tenantDef, adminUserDef,
+		rotation, reconciler.PendingAccessTokenRevocations(), nil)
+	newStatus := statusReconciler.calculateStatus()
+	if newStatus.AccessTokenID == nil || *newStatus.AccessTokenID != 20 || newStatus.PreviousAccessTokenIDs != nil {
+		t.Fatalf("unexpected status %v", newStatus)
+	}
+}
+
+func TestTenantAccessTokenFirstRotationRegeneratesProviderKey(t *testing.T) {
+	server := &tenantAccessTokenServer{}
+	httpServer := httptest.NewTLSServer(server)
+	defer httpServer.Close()
+
+	tenantDef := newTestTenantDef(httpServer.URL)
+	adminUserDef := &porta_client_pkg.User{ID: 2}
+
+	// the secret holds the provider key created with the tenant
+	tenantR := newTestTenant(nil)
+	reconciler := newTestTenantInternalReconciler(t, newTestBaseReconciler(t, tenantR, newTestTenantSecret()), tenantR, httpServer.URL)
+	err := reconciler.reconcileAccessTokenSecret(tenantDef, adminUserDef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if server.createdTokens != 1 || len(server.revokedTokens) != 0 {
+		t.Fatalf("unexpected created tokens %d, revoked tokens %v", server.createdTokens, server.revokedTokens)
+	}
+	if len(server.providerKeys) != 1 || server.providerKeys[0] == "" || server.providerKeys[0] == "currenttoken" {
+		t.Fatalf("provider key not regenerated: %v", server.providerKeys)
+	}
+
+	secret := &v1.Secret{}
+	err = reconciler.Client().Get(reconciler.Context(), types.NamespacedName{Name: "mytenant-secret", Namespace: "myns"}, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data[TenantProviderKeySecretField]) != "newtoken" || secret.Annotations[tenantSecretProviderKeyRegeneratedAnnotation] != "true" {
+		t.Fatalf("unexpected secret %v", secret)
+	}
+
+	// the provider key is regenerated only once
+	tenantR.Status.AccessTokenID = &reconciler.AccessTokenRotation().AccessTokenID
+	tenantR.Status.AccessTokenRotationTime = &reconciler.AccessTokenRotation().RotationTime
+	tenantR.Status.AccessTokenRotationRequest = reconciler.AccessTokenRotation().Request
+
+	reconciler = newTestTenantInternalReconciler(t, reconciler.BaseReconciler, tenantR, httpServer.URL)
+	err = reconciler.reconcileAccessTokenSecret(tenantDef, adminUserDef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(server.providerKeys) != 1 || server.createdTokens != 1 {
+		t.Fatalf("unexpected provider keys %v, created tokens %d", server.providerKeys, server.createdTokens)
+	}
+}
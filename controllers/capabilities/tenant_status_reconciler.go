@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"fmt"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/common"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/reconcilers"
+
+	porta_client_pkg "github.com/3scale/3scale-porta-go-client/client"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type TenantStatusReconciler struct {
+	*reconcilers.BaseReconciler
+	tenantR             *capabilitiesv1beta1.Tenant
+	tenantDef           *porta_client_pkg.Tenant
+	adminUserDef        *porta_client_pkg.User
+	accessTokenRotation *TenantAccessTokenRotation
+	// pendingAccessTokenRevocations is nil when unknown, i.e. the tenant has not been reconciled
+	pendingAccessTokenRevocations []int64
+	reconcileError                error
+	logger                        logr.Logger
+}
+
+func NewTenantStatusReconciler(b *reconcilers.BaseReconciler,
+	tenantR *capabilitiesv1beta1.Tenant,
+	tenantDef *porta_client_pkg.Tenant,
+	adminUserDef *porta_client_pkg.User,
+	accessTokenRotation *TenantAccessTokenRotation,
+	pendingAccessTokenRevocations []int64,
+	reconcileError error,
+) *TenantStatusReconciler {
+	return &TenantStatusReconciler{
+		BaseReconciler:                b,
+		tenantR:                       tenantR,
+		tenantDef:                     tenantDef,
+		adminUserDef:                  adminUserDef,
+		accessTokenRotation:           accessTokenRotation,
+		pendingAccessTokenRevocations: pendingAccessTokenRevocations,
+		reconcileError:                reconcileError,
+		logger:                        b.Logger().WithValues("Status Reconciler", tenantR.Name),
+	}
+}
+
+func (s *TenantStatusReconciler) Reconcile() (reconcile.Result, error) {
+	s.logger.V(1).Info("START")
+
+	newStatus := s.calculateStatus()
+
+	equalStatus := s.tenantR.Status.Equals(newStatus, s.logger)
+	s.logger.V(1).Info("Status", "status is different", !equalStatus)
+	s.logger.V(1).Info("Status", "generation is different", s.tenantR.Generation != s.tenantR.Status.ObservedGeneration)
+	if equalStatus && s.tenantR.Generation == s.tenantR.Status.ObservedGeneration {
+		// Steady state
+		s.logger.V(1).Info("Status steady state, status was not updated")
+		return reconcile.Result{}, nil
+	}
+
+	// Save the generation number we acted on, otherwise we might wrongfully indicate
+	// that we've seen a spec update when we retry.
+	// TODO: This can clobber an update if we allow multiple agents to write to the
+	// same status.
+	newStatus.ObservedGeneration = s.tenantR.Generation
+
+	s.logger.V(1).Info("Updating Status", "sequence no:", fmt.Sprintf("sequence No: %v->%v", s.tenantR.Status.ObservedGeneration, newStatus.ObservedGeneration))
+
+	s.tenantR.Status = *newStatus
+	updateErr := s.Client().Status().Update(s.Context(), s.tenantR)
+	if updateErr != nil {
+		// Ignore conflicts, resource might just be outdated.
+		if errors.IsConflict(updateErr) {
+			s.logger.Info("Failed to update status: resource might just be outdated")
+			return reconcile.Result{Requeue: true}, nil
+		}
+
+		return reconcile.Result{}, fmt.Errorf("Failed to update status: %w", updateErr)
+	}
+	return reconcile.Result{}, nil
+}
+
+func (s *TenantStatusReconciler) calculateStatus() *capabilitiesv1beta1.TenantStatus {
+	// Initialize with existing data for data coming from 3scale
+	// just in case in this reconciliation loop something goes wrong and avoid replacing right data with empty values
+	newStatus := &capabilitiesv1beta1.TenantStatus{
+		TenantId:            s.tenantR.Status.TenantId,
+		AdminId:             s.tenantR.Status.AdminId,
+		ProviderAccountHost: s.tenantR.Status.ProviderAccountHost,
+		ObservedGeneration:  s.tenantR.Status.ObservedGeneration,
+		Conditions:          s.tenantR.Status.Conditions.Copy(),
+		// Keep access token rotation data
+		AccessTokenID:              s.tenantR.Status.AccessTokenID,
+		AccessTokenRotationTime:    s.tenantR.Status.AccessTokenRotationTime,
+		AccessTokenRotationRequest: s.tenantR.Status.AccessTokenRotationRequest,
+		PreviousAccessTokenIDs:     s.tenantR.Status.PreviousAccessTokenIDs,
+	}
+
+	if s.tenantDef != nil {
+		newStatus.TenantId = s.tenantDef.Signup.Account.ID
+		adminURL, err := controllerhelper.URLFromDomain(s.tenantDef.Signup.Account.AdminDomain)
+		if err == nil {
+			newStatus.ProviderAccountHost = adminURL.String()
+		}
+	}
+
+	if s.adminUserDef != nil {
+		newStatus.AdminId = s.adminUserDef.ID
+	}
+
+	if s.accessTokenRotation != nil {
+		accessTokenID := s.accessTokenRotation.AccessTokenID
+		rotationTime := s.accessTokenRotation.RotationTime
+		newStatus.AccessTokenID = &accessTokenID
+		newStatus.AccessTokenRotationTime = &rotationTime
+		newStatus.AccessTokenRotationRequest = s.accessTokenRotation.Request
+	}
+
+	if s.pendingAccessTokenRevocations != nil {
+		newStatus.PreviousAccessTokenIDs = nil
+		if len(s.pendingAccessTokenRevocations) > 0 {
+			newStatus.PreviousAccessTokenIDs = s.pendingAccessTokenRevocations
+		}
+	}
+
+	newStatus.Conditions.SetCondition(s.readyCondition())
+	newStatus.Conditions.SetCondition(s.invalidCondition())
+	newStatus.Conditions.SetCondition(s.failedCondition())
+
+	return newStatus
+}
+
+func (s *TenantStatusReconciler) readyCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.TenantReadyConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if s.reconcileError == nil {
+		condition.Status = corev1.ConditionTrue
+	}
+
+	return condition
+}
+
+func (s *TenantStatusReconciler) invalidCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.TenantInvalidConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	if helper.IsInvalidSpecError(s.reconcileError) {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = s.reconcileError.Error()
+	}
+
+	return condition
+}
+
+func (s *TenantStatusReconciler) failedCondition() common.Condition {
+	condition := common.Condition{
+		Type:   capabilitiesv1beta1.TenantFailedConditionType,
+		Status: corev1.ConditionFalse,
+	}
+
+	// only activate this condition when others are false and still there is an error
+	if s.reconcileError != nil && s.invalidCondition().IsFalse() {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = s.reconcileError.Error()
+	}
+
+	return condition
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+
+	porta_client_pkg "github.com/3scale/3scale-porta-go-client/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func newTestStatusTenant() *capabilitiesv1beta1.Tenant {
+	return &capabilitiesv1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "mytenant", Namespace: "myns", Generation: 2},
+		Status: capabilitiesv1beta1.TenantStatus{
+			TenantId:           3,
+			AdminId:            4,
+			ObservedGeneration: 1,
+		},
+	}
+}
+
+func testTenantStatusReconcile(t *testing.T, tenantR *capabilitiesv1beta1.Tenant, reconciler *TenantStatusReconciler) *capabilitiesv1beta1.Tenant {
+	t.Helper()
+	_, err := reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reconciled := &capabilitiesv1beta1.Tenant{}
+	err = reconciler.Client().Get(context.TODO(), types.NamespacedName{Name: tenantR.Name, Namespace: tenantR.Namespace}, reconciled)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return reconciled
+}
+
+func TestTenantStatusReconcilerReady(t *testing.T) {
+	tenantR := newTestStatusTenant()
+	b := newTestBaseReconciler(t, tenantR)
+
+	tenantDef := &porta_client_pkg.Tenant{}
+	tenantDef.Signup.Account.ID = 5
+	tenantDef.Signup.Account.AdminDomain = "mytenant-admin.example.com"
+	adminUserDef := &porta_client_pkg.User{ID: 6}
+
+	reconciled := testTenantStatusReconcile(t, tenantR, NewTenantStatusReconciler(b, tenantR, tenantDef, adminUserDef, nil, nil, nil))
+	if reconciled.Status.ObservedGeneration != 2 {
+		t.Fatalf("unexpected observed generation %d", reconciled.Status.ObservedGeneration)
+	}
+	if reconciled.Status.TenantId != 5 || reconciled.Status.AdminId != 6 {
+		t.Fatalf("unexpected tenant ids %d, %d", reconciled.Status.TenantId, reconciled.Status.AdminId)
+	}
+	if reconciled.Status.ProviderAccountHost != "https://mytenant-admin.example.com" {
+		t.Fatalf("unexpected provider account host %s", reconciled.Status.ProviderAccountHost)
+	}
+	if !reconciled.Status.Conditions.IsTrueFor(capabilitiesv1beta1.TenantReadyConditionType) {
+		t.Fatalf("ready condition expected: %v", reconciled.Status.Conditions)
+	}
+	if !reconciled.Status.Conditions.IsFalseFor(capabilitiesv1beta1.TenantInvalidConditionType) ||
+		!reconciled.Status.Conditions.IsFalseFor(capabilitiesv1beta1.TenantFailedConditionType) {
+		t.Fatalf("unexpected conditions: %v", reconciled.Status.Conditions)
+	}
+}
+
+func TestTenantStatusReconcilerErrors(t *testing.T) {
+	cases := []struct {
+		name            string
+		reconcileError  error
+		expectedInvalid bool
+		expectedFailed  bool
+	}{
+		{"invalid spec", &helper.SpecFieldError{
+			ErrorType:      helper.InvalidError,
+			FieldErrorList: field.ErrorList{field.Required(field.NewPath("spec").Child("email"), "admin email required")},
+		}, true, false},
+		{"sync error", errors.New("3scale unavailable"), false, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			tenantR := newTestStatusTenant()
+			b := newTestBaseReconciler(subT, tenantR)
+
+			reconciled := testTenantStatusReconcile(subT, tenantR, NewTenantStatusReconciler(b, tenantR, nil, nil, nil, nil, tc.reconcileError))
+			if reconciled.Status.ObservedGeneration != 2 {
+				subT.Fatalf("unexpected observed generation %d", reconciled.Status.ObservedGeneration)
+			}
+			// ids from previous reconciliations are kept
+			if reconciled.Status.TenantId != 3 || reconciled.Status.AdminId != 4 {
+				subT.Fatalf("unexpected tenant ids %d, %d", reconciled.Status.TenantId, reconciled.Status.AdminId)
+			}
+			if !reconciled.Status.Conditions.IsFalseFor(capabilitiesv1beta1.TenantReadyConditionType) {
+				subT.Fatalf("ready condition not expected: %v", reconciled.Status.Conditions)
+			}
+			if reconciled.Status.Conditions.IsTrueFor(capabilitiesv1beta1.TenantInvalidConditionType) != tc.expectedInvalid {
+				subT.Fatalf("unexpected invalid condition: %v", reconciled.Status.Conditions)
+			}
+			if reconciled.Status.Conditions.IsTrueFor(capabilitiesv1beta1.TenantFailedConditionType) != tc.expectedFailed {
+				subT.Fatalf("unexpected failed condition: %v", reconciled.Status.Conditions)
+			}
+		})
+	}
+}
@@ -46,6 +46,7 @@ import (
 	appscontroller "github.com/3scale/3scale-operator/controllers/apps"
 	capabilitiescontroller "github.com/3scale/3scale-operator/controllers/capabilities"
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
 	"github.com/3scale/3scale-operator/pkg/reconcilers"
 	"github.com/3scale/3scale-operator/version"
 	// +kubebuilder:scaffold:imports
@@ -104,6 +105,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	capabilitiesResyncPeriod, err := capabilitiescontroller.CapabilitiesResyncPeriod()
+	if err != nil {
+		setupLog.Error(err, "Failed to get capabilities resync period")
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Namespace:          namespace,
 		Scheme:             scheme,
@@ -111,6 +118,8 @@ func main() {
 		Port:               9443,
 		LeaderElection:     enableLeaderElection,
 		LeaderElectionID:   "82355b9c.3scale.net",
+		// Provider account secrets may be referenced from namespaces other than the watched namespace
+		NewClient: controllerhelper.NewCrossNamespaceClientFunc(namespace),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -165,15 +174,32 @@ func main() {
 		os.Exit(1)
 	}
 
+	discoveryClientTenant, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
 	if err = (&capabilitiescontroller.TenantReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Tenant"),
-		Scheme: mgr.GetScheme(),
+		BaseReconciler: reconcilers.NewBaseReconciler(
+			context.Background(), mgr.GetClient(), mgr.GetScheme(), mgr.GetAPIReader(),
+			ctrl.Log.WithName("controllers").WithName("Tenant"),
+			discoveryClientTenant,
+			mgr.GetEventRecorderFor("Tenant")),
+		ResyncPeriod: capabilitiesResyncPeriod,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Tenant")
 		os.Exit(1)
 	}
 
+	// Webhooks can be disabled when running the operator locally without serving certificates.
+	// Tenant v1alpha1 resources cannot be converted when webhooks are disabled
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&capabilitiesv1beta1.Tenant{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Tenant")
+			os.Exit(1)
+		}
+	}
+
 	discoveryClientBackend, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
 	if err != nil {
 		setupLog.Error(err, "unable to create discovery client")
@@ -185,6 +211,7 @@ func main() {
 			ctrl.Log.WithName("controllers").WithName("Backend"),
 			discoveryClientBackend,
 			mgr.GetEventRecorderFor("Backend")),
+		ResyncPeriod: capabilitiesResyncPeriod,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Backend")
 		os.Exit(1)
@@ -201,6 +228,7 @@ func main() {
 			ctrl.Log.WithName("controllers").WithName("Product"),
 			discoveryClientProduct,
 			mgr.GetEventRecorderFor("Product")),
+		ResyncPeriod: capabilitiesResyncPeriod,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Product")
 		os.Exit(1)
@@ -249,6 +277,7 @@ func main() {
 			ctrl.Log.WithName("controllers").WithName("ActiveDoc"),
 			discoveryClientActiveDoc,
 			mgr.GetEventRecorderFor("ActiveDoc")),
+		ResyncPeriod: capabilitiesResyncPeriod,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ActiveDoc")
 		os.Exit(1)
@@ -266,6 +295,7 @@ func main() {
 			ctrl.Log.WithName("controllers").WithName("CustomPolicyDefinition"),
 			discoveryClientCustomPolicyDefinition,
 			mgr.GetEventRecorderFor("CustomPolicyDefinition")),
+		ResyncPeriod: capabilitiesResyncPeriod,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "CustomPolicyDefinition")
 		os.Exit(1)
@@ -283,6 +313,7 @@ func main() {
 			ctrl.Log.WithName("controllers").WithName("DeveloperAccount"),
 			discoveryClientDeveloperAccount,
 			mgr.GetEventRecorderFor("DeveloperAccount")),
+		ResyncPeriod: capabilitiesResyncPeriod,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DeveloperAccount")
 		os.Exit(1)
@@ -300,11 +331,47 @@ func main() {
 			ctrl.Log.WithName("controllers").WithName("DeveloperUser"),
 			discoveryClientDeveloperUser,
 			mgr.GetEventRecorderFor("DeveloperUser")),
+		ResyncPeriod: capabilitiesResyncPeriod,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DeveloperUser")
 		os.Exit(1)
 	}
 
+	discoveryClientApplication, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+
+	if err = (&capabilitiescontroller.ApplicationReconciler{
+		BaseReconciler: reconcilers.NewBaseReconciler(
+			context.Background(), mgr.GetClient(), mgr.GetScheme(), mgr.GetAPIReader(),
+			ctrl.Log.WithName("controllers").WithName("Application"),
+			discoveryClientApplication,
+			mgr.GetEventRecorderFor("Application")),
+		ResyncPeriod: capabilitiesResyncPeriod,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Application")
+		os.Exit(1)
+	}
+
+	discoveryClientProxyConfigPromote, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+
+	if err = (&capabilitiescontroller.ProxyConfigPromoteReconciler{
+		BaseReconciler: reconcilers.NewBaseReconciler(
+			context.Background(), mgr.GetClient(), mgr.GetScheme(), mgr.GetAPIReader(),
+			ctrl.Log.WithName("controllers").WithName("ProxyConfigPromote"),
+			discoveryClientProxyConfigPromote,
+			mgr.GetEventRecorderFor("ProxyConfigPromote")),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ProxyConfigPromote")
+		os.Exit(1)
+	}
+
 	registerThreescaleMetricsIntoControllerRuntimeMetricsRegistry()
 
 	// +kubebuilder:scaffold:builder
@@ -0,0 +1,150 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	controllerhelper "github.com/3scale/3scale-operator/pkg/controller/helper"
+)
+
+// importAccessTokenEnvVar is the env var with the 3scale access token.
+// The access token is not read from a command line flag, as flags are exposed in process listings and shell history
+const importAccessTokenEnvVar = "THREESCALE_ACCESS_TOKEN"
+
+var (
+	importAdminURL           string
+	importAccessTokenFile    string
+	importNamespace          string
+	importProviderAccountRef string
+	importDeletionPolicy     string
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   getImportUsage(),
+	Short: getImportShortDescription(),
+	Long:  getImportLongDescription(),
+	RunE:  runImportCommand,
+}
+
+func getImportUsage() string {
+	return "import [product-system-name...]"
+}
+
+func getImportShortDescription() string {
+	return "generate product and backend custom resources from existing 3scale products"
+}
+
+func getImportLongDescription() string {
+	return `generate product and backend custom resources from existing 3scale products.
+All the products of the 3scale account are imported when no product system name is given.
+Backends used by the imported products are imported as well.
+The custom resources are written to the standard output in YAML format.
+The 3scale access token is read from the file given in --access-token-file
+or from the ` + importAccessTokenEnvVar + ` environment variable.`
+}
+
+// importAccessToken reads the 3scale access token from the access token file, if any,
+// otherwise from the environment
+func importAccessToken() (string, error) {
+	if importAccessTokenFile != "" {
+		data, err := ioutil.ReadFile(importAccessTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("reading access token file: %w", err)
+		}
+
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("access token file %s is empty", importAccessTokenFile)
+		}
+		return token, nil
+	}
+
+	token := os.Getenv(importAccessTokenEnvVar)
+	if token == "" {
+		return "", fmt.Errorf("access token required: set the %s environment variable or the --access-token-file flag", importAccessTokenEnvVar)
+	}
+
+	return token, nil
+}
+
+func runImportCommand(cmd *cobra.Command, args []string) error {
+	if importDeletionPolicy != capabilitiesv1beta1.DeletionPolicyOrphan && importDeletionPolicy != capabilitiesv1beta1.DeletionPolicyDelete {
+		return fmt.Errorf("invalid deletion policy %q: valid values are %s and %s", importDeletionPolicy,
+			capabilitiesv1beta1.DeletionPolicyOrphan, capabilitiesv1beta1.DeletionPolicyDelete)
+	}
+
+	accessToken, err := importAccessToken()
+	if err != nil {
+		return err
+	}
+
+	client, err := controllerhelper.PortaClientFromURLString(importAdminURL, accessToken)
+	if err != nil {
+		return err
+	}
+
+	var providerAccountRef *corev1.LocalObjectReference
+	if importProviderAccountRef != "" {
+		providerAccountRef = &corev1.LocalObjectReference{Name: importProviderAccountRef}
+	}
+
+	importer, err := controllerhelper.NewProductImporter(client, importNamespace, providerAccountRef, importDeletionPolicy, zap.New(zap.WriteTo(os.Stderr)))
+	if err != nil {
+		return err
+	}
+
+	products, backends, err := importer.Import(args)
+	if err != nil {
+		return err
+	}
+
+	// Backends first, products reference them
+	objects := make([]interface{}, 0, len(backends)+len(products))
+	for _, backend := range backends {
+		objects = append(objects, backend)
+	}
+	for _, product := range products {
+		objects = append(objects, product)
+	}
+
+	for _, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "---\n%s", data)
+	}
+
+	return nil
+}
+
+func init() {
+	importCmd.PersistentFlags().StringVar(&importAdminURL, "admin-url", "", "3scale admin portal URL of the account with the products")
+	importCmd.PersistentFlags().StringVar(&importAccessTokenFile, "access-token-file", "", "File with the 3scale access token with read access to the account management API. Defaults to the "+importAccessTokenEnvVar+" environment variable")
+	importCmd.PersistentFlags().StringVar(&importNamespace, "namespace", "", "Namespace of the generated custom resources")
+	importCmd.PersistentFlags().StringVar(&importProviderAccountRef, "provider-account-ref", "", "Name of the provider account secret referenced by the generated custom resources")
+	importCmd.PersistentFlags().StringVar(&importDeletionPolicy, "deletion-policy", capabilitiesv1beta1.DeletionPolicyOrphan, "Deletion policy annotation of the generated custom resources: orphan or delete")
+	importCmd.MarkPersistentFlagRequired("admin-url")
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,57 @@
+package helper
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+)
+
+const (
+	// AccessTokenPermissionReadWrite grants read and write access to the token scopes
+	AccessTokenPermissionReadWrite = "rw"
+
+	// AccessTokenScopeAccountManagement grants access to the Account Management API
+	AccessTokenScopeAccountManagement = "account_management"
+
+	// AccessTokenScopePolicyRegistry grants access to the Policy Registry API
+	AccessTokenScopePolicyRegistry = "policy_registry"
+
+	accessTokenCreateEndpoint   = "/admin/api/users/%d/access_tokens.json"
+	personalAccessTokenEndpoint = "/admin/api/personal/access_tokens/%d.json"
+
+	accessTokenNameParam       = "name"
+	accessTokenPermissionParam = "permission"
+	accessTokenScopesParam     = "scopes[]"
+)
+
+// AccessTokenElem is the 3scale access token serialized in json format
+type AccessTokenElem struct {
+	AccessToken threescaleapi.AccessToken `json:"access_token"`
+}
+
+// CreateAccessToken creates an access token owned by the given user.
+// The token value is only available in the creation response
+func (c *ThreescaleRESTClient) CreateAccessToken(userID int64, name, permission string, scopes []string) (*threescaleapi.AccessToken, error) {
+	values := url.Values{}
+	values.Set(accessTokenNameParam, name)
+	values.Set(accessTokenPermissionParam, permission)
+	for _, scope := range scopes {
+		values.Add(accessTokenScopesParam, scope)
+	}
+
+	obj := &AccessTokenElem{}
+	err := c.RequestValues(http.MethodPost, fmt.Sprintf(accessTokenCreateEndpoint, userID), values, http.StatusCreated, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return &obj.AccessToken, nil
+}
+
+// DeleteAccessToken revokes an access token.
+// Only tokens owned by the user of the client credentials can be revoked
+func (c *ThreescaleRESTClient) DeleteAccessToken(id int64) error {
+	return c.Request(http.MethodDelete, fmt.Sprintf(personalAccessTokenEndpoint, id), nil, http.StatusOK, nil)
+}
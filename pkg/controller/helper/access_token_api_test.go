@@ -0,0 +1,47 @@
+package helper
+
+import (
+	"net/http"
+	"testing"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+)
+
+func TestThreescaleRESTClientCreateAccessToken(t *testing.T) {
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		equals(t, http.MethodPost, req.Method)
+		equals(t, "/admin/api/users/7/access_tokens.json", req.URL.Path)
+
+		err := req.ParseForm()
+		ok(t, err)
+		equals(t, "mytoken", req.PostForm.Get("name"))
+		equals(t, AccessTokenPermissionReadWrite, req.PostForm.Get("permission"))
+		equals(t, []string{AccessTokenScopeAccountManagement, AccessTokenScopePolicyRegistry}, req.PostForm["scopes[]"])
+
+		return applicationTestResponse(http.StatusCreated, &AccessTokenElem{
+			AccessToken: threescaleapi.AccessToken{ID: 9, Name: "mytoken", Value: "secret"},
+		})
+	})
+
+	restClient, err := NewThreescaleRESTClient("https://tenant-admin.example.com", "12345", httpClient)
+	ok(t, err)
+
+	token, err := restClient.CreateAccessToken(7, "mytoken", AccessTokenPermissionReadWrite,
+		[]string{AccessTokenScopeAccountManagement, AccessTokenScopePolicyRegistry})
+	ok(t, err)
+	equals(t, int64(9), token.ID)
+	equals(t, "secret", token.Value)
+}
+
+func TestThreescaleRESTClientDeleteAccessToken(t *testing.T) {
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		equals(t, http.MethodDelete, req.Method)
+		equals(t, "/admin/api/personal/access_tokens/9.json", req.URL.Path)
+		return applicationTestResponse(http.StatusOK, nil)
+	})
+
+	restClient, err := NewThreescaleRESTClient("https://tenant-admin.example.com", "12345", httpClient)
+	ok(t, err)
+
+	ok(t, restClient.DeleteAccessToken(9))
+}
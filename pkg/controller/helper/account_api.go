@@ -0,0 +1,34 @@
+package helper
+
+import (
+	"fmt"
+	"net/http"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+)
+
+const (
+	// AccountStateSuspended is the state of suspended 3scale accounts
+	AccountStateSuspended = "suspended"
+
+	// AccountStateScheduledForDeletion is the state of 3scale accounts waiting to be permanently deleted
+	AccountStateScheduledForDeletion = "scheduled_for_deletion"
+
+	accountSuspendEndpoint = "/admin/api/accounts/%d/suspend.json"
+	accountResumeEndpoint  = "/admin/api/accounts/%d/resume.json"
+)
+
+// SuspendAccount changes the state of the account to suspended.
+// Tenants can be suspended using master admin portal URL and master token.
+func (c *ThreescaleRESTClient) SuspendAccount(id int64) (*threescaleapi.AccountElem, error) {
+	obj := &threescaleapi.AccountElem{}
+	err := c.Request(http.MethodPut, fmt.Sprintf(accountSuspendEndpoint, id), nil, http.StatusOK, obj)
+	return obj, err
+}
+
+// ResumeAccount changes the state of a suspended account to approved
+func (c *ThreescaleRESTClient) ResumeAccount(id int64) (*threescaleapi.AccountElem, error) {
+	obj := &threescaleapi.AccountElem{}
+	err := c.Request(http.MethodPut, fmt.Sprintf(accountResumeEndpoint, id), nil, http.StatusOK, obj)
+	return obj, err
+}
@@ -0,0 +1,42 @@
+package helper
+
+import (
+	"net/http"
+	"testing"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+)
+
+func TestThreescaleRESTClientSuspendAccount(t *testing.T) {
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		equals(t, http.MethodPut, req.Method)
+		equals(t, "/admin/api/accounts/3/suspend.json", req.URL.Path)
+		return applicationTestResponse(http.StatusOK, &threescaleapi.AccountElem{
+			Account: threescaleapi.Account{ID: 3, State: AccountStateSuspended},
+		})
+	})
+
+	restClient, err := NewThreescaleRESTClient("https://master.example.com", "12345", httpClient)
+	ok(t, err)
+
+	account, err := restClient.SuspendAccount(3)
+	ok(t, err)
+	equals(t, AccountStateSuspended, account.Account.State)
+}
+
+func TestThreescaleRESTClientResumeAccount(t *testing.T) {
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		equals(t, http.MethodPut, req.Method)
+		equals(t, "/admin/api/accounts/3/resume.json", req.URL.Path)
+		return applicationTestResponse(http.StatusOK, &threescaleapi.AccountElem{
+			Account: threescaleapi.Account{ID: 3, State: "approved"},
+		})
+	})
+
+	restClient, err := NewThreescaleRESTClient("https://master.example.com", "12345", httpClient)
+	ok(t, err)
+
+	account, err := restClient.ResumeAccount(3)
+	ok(t, err)
+	equals(t, "approved", account.Account.State)
+}
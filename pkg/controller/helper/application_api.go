@@ -0,0 +1,134 @@
+package helper
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+)
+
+const (
+	// ApplicationStateSuspended is the state of suspended 3scale applications
+	ApplicationStateSuspended = "suspended"
+
+	applicationEndpoint           = "/admin/api/accounts/%d/applications/%d.json"
+	applicationChangePlanEndpoint = "/admin/api/accounts/%d/applications/%d/change_plan.json"
+	applicationSuspendEndpoint    = "/admin/api/accounts/%d/applications/%d/suspend.json"
+	applicationResumeEndpoint     = "/admin/api/accounts/%d/applications/%d/resume.json"
+	applicationKeyListEndpoint    = "/admin/api/accounts/%d/applications/%d/keys.json"
+
+	applicationPlanIDParam      = "plan_id"
+	applicationNameParam        = "name"
+	applicationDescriptionParam = "description"
+	applicationUserKeyParam     = "user_key"
+	applicationKeyParam         = "key"
+)
+
+// ApplicationItem is the 3scale application object
+type ApplicationItem struct {
+	ID            int64  `json:"id"`
+	State         string `json:"state"`
+	AccountID     int64  `json:"account_id"`
+	ProductID     int64  `json:"service_id"`
+	PlanID        int64  `json:"plan_id"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	UserKey       string `json:"user_key,omitempty"`
+	ApplicationID string `json:"application_id,omitempty"`
+}
+
+// Application is the 3scale application serialized in json format
+type Application struct {
+	Element ApplicationItem `json:"application"`
+}
+
+// ApplicationKey is the 3scale application key serialized in json format
+type ApplicationKey struct {
+	Element struct {
+		Value string `json:"value"`
+	} `json:"key"`
+}
+
+// ApplicationKeyList is a list of 3scale application keys serialized in json format
+type ApplicationKeyList struct {
+	Keys []ApplicationKey `json:"keys"`
+}
+
+// Application reads a developer account's application
+func (c *ThreescaleRESTClient) Application(accountID, id int64) (*Application, error) {
+	obj := &Application{}
+	err := c.Request(http.MethodGet, fmt.Sprintf(applicationEndpoint, accountID, id), nil, http.StatusOK, obj)
+	return obj, err
+}
+
+// UpdateApplication updates name and description of a developer account's application
+func (c *ThreescaleRESTClient) UpdateApplication(accountID, id int64, name, description string) (*Application, error) {
+	params := threescaleapi.Params{
+		applicationNameParam:        name,
+		applicationDescriptionParam: description,
+	}
+
+	obj := &Application{}
+	err := c.Request(http.MethodPut, fmt.Sprintf(applicationEndpoint, accountID, id), params, http.StatusOK, obj)
+	return obj, err
+}
+
+// ChangeApplicationUserKey replaces the user key of the application
+func (c *ThreescaleRESTClient) ChangeApplicationUserKey(accountID, id int64, userKey string) (*Application, error) {
+	params := threescaleapi.Params{applicationUserKeyParam: userKey}
+
+	obj := &Application{}
+	err := c.Request(http.MethodPut, fmt.Sprintf(applicationEndpoint, accountID, id), params, http.StatusOK, obj)
+	return obj, err
+}
+
+// ChangeApplicationPlan moves the application to the given plan
+func (c *ThreescaleRESTClient) ChangeApplicationPlan(accountID, id, planID int64) (*Application, error) {
+	params := threescaleapi.Params{applicationPlanIDParam: strconv.FormatInt(planID, 10)}
+
+	obj := &Application{}
+	err := c.Request(http.MethodPut, fmt.Sprintf(applicationChangePlanEndpoint, accountID, id), params, http.StatusOK, obj)
+	return obj, err
+}
+
+// SuspendApplication changes the state of the application to suspended
+func (c *ThreescaleRESTClient) SuspendApplication(accountID, id int64) (*Application, error) {
+	obj := &Application{}
+	err := c.Request(http.MethodPut, fmt.Sprintf(applicationSuspendEndpoint, accountID, id), nil, http.StatusOK, obj)
+	return obj, err
+}
+
+// ResumeApplication changes the state of a suspended application to live
+func (c *ThreescaleRESTClient) ResumeApplication(accountID, id int64) (*Application, error) {
+	obj := &Application{}
+	err := c.Request(http.MethodPut, fmt.Sprintf(applicationResumeEndpoint, accountID, id), nil, http.StatusOK, obj)
+	return obj, err
+}
+
+// DeleteApplication deletes a developer account's application
+func (c *ThreescaleRESTClient) DeleteApplication(accountID, id int64) error {
+	return c.Request(http.MethodDelete, fmt.Sprintf(applicationEndpoint, accountID, id), nil, http.StatusOK, nil)
+}
+
+// ApplicationKeys lists the application keys
+func (c *ThreescaleRESTClient) ApplicationKeys(accountID, id int64) ([]string, error) {
+	list := &ApplicationKeyList{}
+	err := c.Request(http.MethodGet, fmt.Sprintf(applicationKeyListEndpoint, accountID, id), nil, http.StatusOK, list)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(list.Keys))
+	for idx := range list.Keys {
+		keys = append(keys, list.Keys[idx].Element.Value)
+	}
+
+	return keys, nil
+}
+
+// CreateApplicationKey adds a key to the application
+func (c *ThreescaleRESTClient) CreateApplicationKey(accountID, id int64, key string) error {
+	params := threescaleapi.Params{applicationKeyParam: key}
+	return c.Request(http.MethodPost, fmt.Sprintf(applicationKeyListEndpoint, accountID, id), params, http.StatusCreated, nil)
+}
@@ -0,0 +1,118 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func applicationTestResponse(statusCode int, obj interface{}) *http.Response {
+	responseBodyBytes, _ := json.Marshal(obj)
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       ioutil.NopCloser(bytes.NewBuffer(responseBodyBytes)),
+		Header:     make(http.Header),
+	}
+}
+
+func TestThreescaleRESTClientUpdateApplication(t *testing.T) {
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		equals(t, http.MethodPut, req.Method)
+		equals(t, "/admin/api/accounts/3/applications/5.json", req.URL.Path)
+		user, pass, hasAuth := req.BasicAuth()
+		assert(t, hasAuth, "basic auth not set")
+		equals(t, "", user)
+		equals(t, "12345", pass)
+
+		err := req.ParseForm()
+		ok(t, err)
+		equals(t, "myapp", req.PostForm.Get("name"))
+		equals(t, "my description", req.PostForm.Get("description"))
+
+		return applicationTestResponse(http.StatusOK, &Application{
+			Element: ApplicationItem{ID: 5, AccountID: 3, PlanID: 4, Name: "myapp", State: "live"},
+		})
+	})
+
+	restClient, err := NewThreescaleRESTClient("https://example.com/", "12345", httpClient)
+	ok(t, err)
+
+	application, err := restClient.UpdateApplication(3, 5, "myapp", "my description")
+	ok(t, err)
+	equals(t, int64(5), application.Element.ID)
+	equals(t, "live", application.Element.State)
+}
+
+func TestThreescaleRESTClientChangeApplicationUserKey(t *testing.T) {
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		equals(t, http.MethodPut, req.Method)
+		equals(t, "/admin/api/accounts/3/applications/5.json", req.URL.Path)
+
+		err := req.ParseForm()
+		ok(t, err)
+		equals(t, "newkey", req.PostForm.Get("user_key"))
+
+		return applicationTestResponse(http.StatusOK, &Application{
+			Element: ApplicationItem{ID: 5, AccountID: 3, UserKey: "newkey"},
+		})
+	})
+
+	restClient, err := NewThreescaleRESTClient("https://example.com", "12345", httpClient)
+	ok(t, err)
+
+	application, err := restClient.ChangeApplicationUserKey(3, 5, "newkey")
+	ok(t, err)
+	equals(t, "newkey", application.Element.UserKey)
+}
+
+func TestThreescaleRESTClientApplicationNotFound(t *testing.T) {
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		equals(t, http.MethodGet, req.Method)
+		equals(t, "/admin/api/accounts/3/applications/5.json", req.URL.Path)
+		return applicationTestResponse(http.StatusNotFound, map[string]string{"status": "Not found"})
+	})
+
+	restClient, err := NewThreescaleRESTClient("https://example.com", "12345", httpClient)
+	ok(t, err)
+
+	_, err = restClient.Application(3, 5)
+	assert(t, err != nil, "expected error")
+	assert(t, IsThreescaleNotFound(err), "expected not found error, got %v", err)
+}
+
+func TestThreescaleRESTClientApplicationKeys(t *testing.T) {
+	keyList := &ApplicationKeyList{Keys: make([]ApplicationKey, 2)}
+	keyList.Keys[0].Element.Value = "key1"
+	keyList.Keys[1].Element.Value = "key2"
+
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		equals(t, http.MethodGet, req.Method)
+		equals(t, "/admin/api/accounts/3/applications/5/keys.json", req.URL.Path)
+		return applicationTestResponse(http.StatusOK, keyList)
+	})
+
+	restClient, err := NewThreescaleRESTClient("https://example.com", "12345", httpClient)
+	ok(t, err)
+
+	keys, err := restClient.ApplicationKeys(3, 5)
+	ok(t, err)
+	equals(t, []string{"key1", "key2"}, keys)
+}
+
+func TestThreescaleRESTClientUnexpectedStatus(t *testing.T) {
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		return applicationTestResponse(http.StatusUnprocessableEntity, map[string]string{"error": "invalid"})
+	})
+
+	restClient, err := NewThreescaleRESTClient("https://example.com", "12345", httpClient)
+	ok(t, err)
+
+	_, err = restClient.SuspendApplication(3, 5)
+	assert(t, err != nil, "expected error")
+	assert(t, !IsThreescaleNotFound(err), "unexpected not found error")
+	apiErr, isAPIErr := err.(*ThreescaleAPIError)
+	assert(t, isAPIErr, "expected ThreescaleAPIError, got %T", err)
+	equals(t, http.StatusUnprocessableEntity, apiErr.Code)
+}
@@ -2,6 +2,7 @@ package helper
 
 import (
 	"fmt"
+	"strconv"
 
 	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
 
@@ -14,6 +15,7 @@ type ApplicationPlanEntity struct {
 	obj          threescaleapi.ApplicationPlanItem
 	limits       *threescaleapi.ApplicationPlanLimitList
 	pricingRules *threescaleapi.ApplicationPlanPricingRuleList
+	dryRun       *DryRunRecorder
 	logger       logr.Logger
 }
 
@@ -26,6 +28,16 @@ func NewApplicationPlanEntity(productID int64, obj threescaleapi.ApplicationPlan
 	}
 }
 
+// SetDryRun enables the dry-run mode. Changes are recorded instead of applied
+func (b *ApplicationPlanEntity) SetDryRun(recorder *DryRunRecorder) {
+	b.dryRun = recorder
+}
+
+// isPlanned returns true when the plan has been planned to be created in dry-run mode
+func (b *ApplicationPlanEntity) isPlanned() bool {
+	return b.dryRun != nil && b.obj.ID == DryRunPlannedID
+}
+
 func (b *ApplicationPlanEntity) ID() int64 {
 	return b.obj.ID
 }
@@ -56,6 +68,10 @@ func (b *ApplicationPlanEntity) State() string {
 
 func (b *ApplicationPlanEntity) Update(params threescaleapi.Params) error {
 	b.logger.V(1).Info("Update", "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Update("application plan", b.obj.ID, params)
+		return nil
+	}
 	updated, err := b.client.UpdateApplicationPlan(b.productID, b.obj.ID, params)
 	if err != nil {
 		return fmt.Errorf("product [%d] plan [%s] update: %w", b.productID, b.obj.SystemName, err)
@@ -79,6 +95,9 @@ func (b *ApplicationPlanEntity) Limits() (*threescaleapi.ApplicationPlanLimitLis
 
 func (b *ApplicationPlanEntity) getLimits() (*threescaleapi.ApplicationPlanLimitList, error) {
 	b.logger.V(1).Info("getLimits")
+	if b.isPlanned() {
+		return &threescaleapi.ApplicationPlanLimitList{}, nil
+	}
 	list, err := b.client.ListApplicationPlansLimits(b.obj.ID)
 	if err != nil {
 		return nil, fmt.Errorf("application plan [%s] get limits: %w", b.obj.SystemName, err)
@@ -89,6 +108,10 @@ func (b *ApplicationPlanEntity) getLimits() (*threescaleapi.ApplicationPlanLimit
 
 func (b *ApplicationPlanEntity) DeleteLimit(metricID, id int64) error {
 	b.logger.V(1).Info("DeleteLimit", "metricID", metricID, "ID", id)
+	if b.dryRun != nil {
+		b.dryRun.Delete("limit", id)
+		return nil
+	}
 	err := b.client.DeleteApplicationPlanLimit(b.obj.ID, metricID, id)
 	if err != nil {
 		return fmt.Errorf("application plan [%s] delete limit: %w", b.obj.SystemName, err)
@@ -99,6 +122,10 @@ func (b *ApplicationPlanEntity) DeleteLimit(metricID, id int64) error {
 
 func (b *ApplicationPlanEntity) CreateLimit(metricID int64, params threescaleapi.Params) error {
 	b.logger.V(1).Info("CreateLimit", "metricID", metricID, "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Create("limit", paramsWith(params, "application_plan", b.obj.SystemName, "metric_id", strconv.FormatInt(metricID, 10)))
+		return nil
+	}
 	_, err := b.client.CreateApplicationPlanLimit(b.obj.ID, metricID, params)
 	if err != nil {
 		return fmt.Errorf("application plan [%s] create limit: %w", b.obj.SystemName, err)
@@ -124,6 +151,9 @@ func (b *ApplicationPlanEntity) PricingRules() (*threescaleapi.ApplicationPlanPr
 
 func (b *ApplicationPlanEntity) getPricingRules() (*threescaleapi.ApplicationPlanPricingRuleList, error) {
 	b.logger.V(1).Info("getPricingRules")
+	if b.isPlanned() {
+		return &threescaleapi.ApplicationPlanPricingRuleList{}, nil
+	}
 	list, err := b.client.ListApplicationPlansPricingRules(b.obj.ID)
 	if err != nil {
 		return nil, fmt.Errorf("application plan [%s] get pricing rules: %w", b.obj.SystemName, err)
@@ -134,6 +164,10 @@ func (b *ApplicationPlanEntity) getPricingRules() (*threescaleapi.ApplicationPla
 
 func (b *ApplicationPlanEntity) DeletePricingRule(metricID, id int64) error {
 	b.logger.V(1).Info("DeletePricingRule", "metricID", metricID, "ID", id)
+	if b.dryRun != nil {
+		b.dryRun.Delete("pricing rule", id)
+		return nil
+	}
 	err := b.client.DeleteApplicationPlanPricingRule(b.obj.ID, metricID, id)
 	if err != nil {
 		return fmt.Errorf("application plan [%s] delete pricing rule: %w", b.obj.SystemName, err)
@@ -144,6 +178,10 @@ func (b *ApplicationPlanEntity) DeletePricingRule(metricID, id int64) error {
 
 func (b *ApplicationPlanEntity) CreatePricingRule(metricID int64, params threescaleapi.Params) error {
 	b.logger.V(1).Info("CreatePricingRule", "metricID", metricID, "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Create("pricing rule", paramsWith(params, "application_plan", b.obj.SystemName, "metric_id", strconv.FormatInt(metricID, 10)))
+		return nil
+	}
 	_, err := b.client.CreateApplicationPlanPricingRule(b.obj.ID, metricID, params)
 	if err != nil {
 		return fmt.Errorf("application plan [%s] create pricing rule: %w", b.obj.SystemName, err)
@@ -33,7 +33,7 @@ func BackendList(ns string, cl client.Client, providerAccountURLStr string, logg
 			continue
 		}
 
-		backendProviderAccount, err := LookupProviderAccount(cl, ns, backendList.Items[idx].Spec.ProviderAccountRef, logger)
+		backendProviderAccount, err := LookupProviderAccount(cl, ns, backendList.Items[idx].Spec.ProviderAccountRef, backendList.Items[idx].Spec.ProviderAccountNamespace, backendList.Items[idx].Spec.TenantRef, logger)
 		if err != nil {
 			return nil, fmt.Errorf("BackendList: %w", err)
 		}
@@ -17,6 +17,7 @@ type BackendAPIEntity struct {
 	metricsAndMethods *threescaleapi.MetricJSONList
 	methods           *threescaleapi.MethodList
 	mappingRules      *threescaleapi.MappingRuleJSONList
+	dryRun            *DryRunRecorder
 	logger            logr.Logger
 }
 
@@ -28,6 +29,17 @@ func NewBackendAPIEntity(backendAPIObj *threescaleapi.BackendApi, client *threes
 	}
 }
 
+// SetDryRun enables the dry-run mode. Changes are recorded instead of applied
+func (b *BackendAPIEntity) SetDryRun(recorder *DryRunRecorder) {
+	b.dryRun = recorder
+}
+
+// IsPlanned returns true when the backend has been planned to be created in dry-run mode.
+// Planned backends are empty
+func (b *BackendAPIEntity) IsPlanned() bool {
+	return b.dryRun != nil && b.backendAPIObj.Element.ID == DryRunPlannedID
+}
+
 func (b *BackendAPIEntity) ID() int64 {
 	return b.backendAPIObj.Element.ID
 }
@@ -50,6 +62,10 @@ func (b *BackendAPIEntity) PrivateEndpoint() string {
 
 func (b *BackendAPIEntity) Update(params threescaleapi.Params) error {
 	b.logger.V(1).Info("Update", "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Update("backend", b.backendAPIObj.Element.ID, params)
+		return nil
+	}
 	updatedBackendAPI, err := b.client.UpdateBackendApi(b.backendAPIObj.Element.ID, params)
 	if err != nil {
 		return fmt.Errorf("backend [%s] update request: %w", b.backendAPIObj.Element.SystemName, err)
@@ -74,6 +90,10 @@ func (b *BackendAPIEntity) Methods() (*threescaleapi.MethodList, error) {
 
 func (b *BackendAPIEntity) CreateMethod(params threescaleapi.Params) error {
 	b.logger.V(1).Info("CreateMethod", "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Create("method", params)
+		return nil
+	}
 	hitsID, err := b.getHitsID()
 	if err != nil {
 		return err
@@ -88,6 +108,10 @@ func (b *BackendAPIEntity) CreateMethod(params threescaleapi.Params) error {
 
 func (b *BackendAPIEntity) DeleteMethod(id int64) error {
 	b.logger.V(1).Info("DeleteMethod", "ID", id)
+	if b.dryRun != nil {
+		b.dryRun.Delete("method", id)
+		return nil
+	}
 	hitsID, err := b.getHitsID()
 	if err != nil {
 		return err
@@ -102,6 +126,10 @@ func (b *BackendAPIEntity) DeleteMethod(id int64) error {
 
 func (b *BackendAPIEntity) UpdateMethod(id int64, params threescaleapi.Params) error {
 	b.logger.V(1).Info("UpdateMethod", "ID", id, "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Update("method", id, params)
+		return nil
+	}
 	hitsID, err := b.getHitsID()
 	if err != nil {
 		return err
@@ -116,6 +144,10 @@ func (b *BackendAPIEntity) UpdateMethod(id int64, params threescaleapi.Params) e
 
 func (b *BackendAPIEntity) CreateMetric(params threescaleapi.Params) error {
 	b.logger.V(1).Info("CreateMetric", "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Create("metric", params)
+		return nil
+	}
 	_, err := b.client.CreateBackendApiMetric(b.backendAPIObj.Element.ID, params)
 	if err != nil {
 		return fmt.Errorf("backend [%s] create metric: %w", b.backendAPIObj.Element.SystemName, err)
@@ -126,6 +158,10 @@ func (b *BackendAPIEntity) CreateMetric(params threescaleapi.Params) error {
 
 func (b *BackendAPIEntity) DeleteMetric(id int64) error {
 	b.logger.V(1).Info("DeleteMetric", "ID", id)
+	if b.dryRun != nil {
+		b.dryRun.Delete("metric", id)
+		return nil
+	}
 	err := b.client.DeleteBackendApiMetric(b.backendAPIObj.Element.ID, id)
 	if err != nil {
 		return fmt.Errorf("backend [%s] delete metric: %w", b.backendAPIObj.Element.SystemName, err)
@@ -136,6 +172,10 @@ func (b *BackendAPIEntity) DeleteMetric(id int64) error {
 
 func (b *BackendAPIEntity) UpdateMetric(id int64, params threescaleapi.Params) error {
 	b.logger.V(1).Info("UpdateMethod", "ID", id, "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Update("metric", id, params)
+		return nil
+	}
 	_, err := b.client.UpdateBackendApiMetric(b.backendAPIObj.Element.ID, id, params)
 	if err != nil {
 		return fmt.Errorf("backend [%s] update metric: %w", b.backendAPIObj.Element.SystemName, err)
@@ -191,6 +231,10 @@ func (b *BackendAPIEntity) MappingRules() (*threescaleapi.MappingRuleJSONList, e
 
 func (b *BackendAPIEntity) DeleteMappingRule(id int64) error {
 	b.logger.V(1).Info("DeleteMappingRule", "ID", id)
+	if b.dryRun != nil {
+		b.dryRun.Delete("mapping rule", id)
+		return nil
+	}
 	err := b.client.DeleteBackendapiMappingRule(b.backendAPIObj.Element.ID, id)
 	if err != nil {
 		return fmt.Errorf("backend [%s] delete mapping rule: %w", b.backendAPIObj.Element.SystemName, err)
@@ -201,6 +245,10 @@ func (b *BackendAPIEntity) DeleteMappingRule(id int64) error {
 
 func (b *BackendAPIEntity) CreateMappingRule(params threescaleapi.Params) error {
 	b.logger.V(1).Info("CreateMappingRule", "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Create("mapping rule", params)
+		return nil
+	}
 	_, err := b.client.CreateBackendapiMappingRule(b.backendAPIObj.Element.ID, params)
 	if err != nil {
 		return fmt.Errorf("backend [%s] create mappingrule: %w", b.backendAPIObj.Element.SystemName, err)
@@ -211,6 +259,10 @@ func (b *BackendAPIEntity) CreateMappingRule(params threescaleapi.Params) error
 
 func (b *BackendAPIEntity) UpdateMappingRule(id int64, params threescaleapi.Params) error {
 	b.logger.V(1).Info("UpdateMappingRule", "ID", id, "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Update("mapping rule", id, params)
+		return nil
+	}
 	_, err := b.client.UpdateBackendapiMappingRule(b.backendAPIObj.Element.ID, id, params)
 	if err != nil {
 		return fmt.Errorf("backend [%s] update mappingrule: %w", b.backendAPIObj.Element.SystemName, err)
@@ -222,6 +274,10 @@ func (b *BackendAPIEntity) UpdateMappingRule(id int64, params threescaleapi.Para
 // FindMethodMetricIDBySystemName returns metric or method ID from system name.
 // -1 if metric and method is not found
 func (b *BackendAPIEntity) FindMethodMetricIDBySystemName(systemName string) (int64, error) {
+	if b.dryRun != nil && (b.dryRun.IsPlanned("method", systemName) || b.dryRun.IsPlanned("metric", systemName)) {
+		return DryRunPlannedID, nil
+	}
+
 	metricsMethodList, err := b.MetricsAndMethods()
 	if err != nil {
 		return -1, err
@@ -257,6 +313,9 @@ func (b *BackendAPIEntity) resetMappingRules() {
 
 func (b *BackendAPIEntity) getMethods() (*threescaleapi.MethodList, error) {
 	b.logger.V(1).Info("getMethods")
+	if b.IsPlanned() {
+		return &threescaleapi.MethodList{}, nil
+	}
 	hitsID, err := b.getHitsID()
 	if err != nil {
 		return nil, err
@@ -307,6 +366,9 @@ func (b *BackendAPIEntity) getMetrics() (*threescaleapi.MetricJSONList, error) {
 
 func (b *BackendAPIEntity) getMetricsAndMethods() (*threescaleapi.MetricJSONList, error) {
 	b.logger.V(1).Info("getMetricsAndMethods")
+	if b.IsPlanned() {
+		return plannedMetricList(), nil
+	}
 	metricList, err := b.client.ListBackendapiMetrics(b.backendAPIObj.Element.ID)
 	if err != nil {
 		return nil, fmt.Errorf("backend [%s] get metrics: %w", b.backendAPIObj.Element.SystemName, err)
@@ -352,6 +414,9 @@ func sanitizeBackendMethodList(list *threescaleapi.MethodList) {
 
 func (b *BackendAPIEntity) getMappingRules() (*threescaleapi.MappingRuleJSONList, error) {
 	b.logger.V(1).Info("getMappingRules")
+	if b.IsPlanned() {
+		return &threescaleapi.MappingRuleJSONList{}, nil
+	}
 	list, err := b.client.ListBackendapiMappingRules(b.backendAPIObj.Element.ID)
 	if err != nil {
 		return nil, fmt.Errorf("backend [%s] get mapping rules: %w", b.backendAPIObj.Element.SystemName, err)
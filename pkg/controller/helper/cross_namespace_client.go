@@ -0,0 +1,67 @@
+package helper
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// NewCrossNamespaceClientFunc returns the manager client constructor for a manager watching the given namespace.
+// The manager cache only holds objects of the watched namespace, hence objects of other namespaces,
+// like provider account secrets referenced from another namespace, are read from the API server.
+// When every namespace is watched, the default client is returned.
+func NewCrossNamespaceClientFunc(namespace string) manager.NewClientFunc {
+	return func(c cache.Cache, config *rest.Config, options client.Options) (client.Client, error) {
+		cachedClient, err := manager.DefaultNewClient(c, config, options)
+		if err != nil {
+			return nil, err
+		}
+
+		if namespace == "" {
+			return cachedClient, nil
+		}
+
+		apiReader, err := client.New(config, options)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewCrossNamespaceClient(cachedClient, apiReader, namespace), nil
+	}
+}
+
+// crossNamespaceClient reads objects of the cached namespace through the cached client
+// and objects of other namespaces through the uncached API reader
+type crossNamespaceClient struct {
+	client.Client
+	apiReader client.Reader
+	namespace string
+}
+
+// NewCrossNamespaceClient returns a client reading objects of namespaces other than the cached namespace
+// through the uncached API reader
+func NewCrossNamespaceClient(cl client.Client, apiReader client.Reader, namespace string) client.Client {
+	return &crossNamespaceClient{Client: cl, apiReader: apiReader, namespace: namespace}
+}
+
+func (c *crossNamespaceClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if key.Namespace != "" && key.Namespace != c.namespace {
+		return c.apiReader.Get(ctx, key, obj)
+	}
+
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c *crossNamespaceClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	if listOpts.Namespace != "" && listOpts.Namespace != c.namespace {
+		return c.apiReader.List(ctx, list, opts...)
+	}
+
+	return c.Client.List(ctx, list, opts...)
+}
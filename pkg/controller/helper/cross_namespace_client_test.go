@@ -0,0 +1,38 @@
+package helper
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCrossNamespaceClient(t *testing.T) {
+	ns := "some_namespace"
+	otherNS := "credentials_namespace"
+
+	// the cache only holds objects of the watched namespace
+	cachedClient := fake.NewFakeClient(GetTestSecret(ns, "local", nil))
+	apiReader := fake.NewFakeClient(GetTestSecret(ns, "local", nil), GetTestSecret(otherNS, "remote", nil))
+	cl := NewCrossNamespaceClient(cachedClient, apiReader, ns)
+
+	secret := &corev1.Secret{}
+	err := cl.Get(context.TODO(), client.ObjectKey{Namespace: ns, Name: "local"}, secret)
+	ok(t, err)
+
+	err = cl.Get(context.TODO(), client.ObjectKey{Namespace: otherNS, Name: "remote"}, secret)
+	ok(t, err)
+	equals(t, otherNS, secret.Namespace)
+
+	// objects of the watched namespace are not read from the API server
+	err = cl.Get(context.TODO(), client.ObjectKey{Namespace: ns, Name: "remote"}, secret)
+	assert(t, apierrors.IsNotFound(err), "not found error expected, got %v", err)
+
+	secretList := &corev1.SecretList{}
+	err = cl.List(context.TODO(), secretList, client.InNamespace(otherNS))
+	ok(t, err)
+	equals(t, 1, len(secretList.Items))
+}
@@ -52,7 +52,7 @@ func FindDeveloperUserList(logger logr.Logger, cl client.Client, queryOpts []cli
 // DeveloperUserProviderAccountFilter implements a response filter by providerAccount
 func DeveloperUserProviderAccountFilter(cl client.Client, ns, providerAccountURLStr string, logger logr.Logger) DeveloperUserListFilter {
 	return func(developerUser *capabilitiesv1beta1.DeveloperUser) (bool, error) {
-		providerAccount, err := LookupProviderAccount(cl, ns, developerUser.Spec.ProviderAccountRef, logger)
+		providerAccount, err := LookupProviderAccount(cl, ns, developerUser.Spec.ProviderAccountRef, developerUser.Spec.ProviderAccountNamespace, developerUser.Spec.TenantRef, logger)
 		if err != nil {
 			return false, err
 		}
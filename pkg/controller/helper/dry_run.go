@@ -0,0 +1,123 @@
+package helper
+
+import (
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+)
+
+const (
+	DryRunActionCreate = "create"
+	DryRunActionUpdate = "update"
+	DryRunActionDelete = "delete"
+
+	// DryRunPlannedID is the ID of the 3scale objects planned to be created in dry-run mode
+	DryRunPlannedID int64 = 0
+)
+
+// DryRunChange is a change to a 3scale object computed in dry-run mode
+type DryRunChange struct {
+	// Task is the synchronization task that computed the change
+	Task   string `json:"task,omitempty"`
+	Action string `json:"action"`
+	Object string `json:"object"`
+	// ID of the 3scale object. Not set for objects to be created
+	ID     *int64               `json:"id,omitempty"`
+	Params threescaleapi.Params `json:"params,omitempty"`
+}
+
+type dryRunPlannedKey struct {
+	object     string
+	systemName string
+}
+
+// DryRunRecorder records the changes to 3scale objects instead of applying them.
+// Entities with a dry-run recorder set do not call the 3scale API to create,
+// update or delete objects.
+type DryRunRecorder struct {
+	task    string
+	changes []DryRunChange
+	planned map[dryRunPlannedKey]bool
+}
+
+func NewDryRunRecorder() *DryRunRecorder {
+	return &DryRunRecorder{
+		changes: []DryRunChange{},
+		planned: map[dryRunPlannedKey]bool{},
+	}
+}
+
+// Track wraps the task to attribute the changes recorded while running the task
+func (r *DryRunRecorder) Track(name string, task func(interface{}) error) func(interface{}) error {
+	if r == nil {
+		return task
+	}
+
+	return func(ctx interface{}) error {
+		r.task = name
+		defer func() { r.task = "" }()
+		return task(ctx)
+	}
+}
+
+// Changes returns the recorded changes in order
+func (r *DryRunRecorder) Changes() []DryRunChange {
+	return r.changes
+}
+
+// Create records the creation of the 3scale object.
+// Objects created with system name can be found with IsPlanned
+func (r *DryRunRecorder) Create(object string, params threescaleapi.Params) {
+	if systemName, ok := params["system_name"]; ok {
+		r.planned[dryRunPlannedKey{object, systemName}] = true
+	}
+
+	r.record(DryRunActionCreate, object, nil, params)
+}
+
+// Update records the update of the 3scale object
+func (r *DryRunRecorder) Update(object string, id int64, params threescaleapi.Params) {
+	r.record(DryRunActionUpdate, object, &id, params)
+}
+
+// Delete records the deletion of the 3scale object
+func (r *DryRunRecorder) Delete(object string, id int64) {
+	r.record(DryRunActionDelete, object, &id, nil)
+}
+
+// IsPlanned returns true when the 3scale object with the given system name has been planned to be created
+func (r *DryRunRecorder) IsPlanned(object, systemName string) bool {
+	return r.planned[dryRunPlannedKey{object, systemName}]
+}
+
+func (r *DryRunRecorder) record(action, object string, id *int64, params threescaleapi.Params) {
+	r.changes = append(r.changes, DryRunChange{
+		Task:   r.task,
+		Action: action,
+		Object: object,
+		ID:     id,
+		Params: params,
+	})
+}
+
+// plannedMetricList returns the metrics of a 3scale product or backend planned to be created in dry-run mode.
+// 3scale creates the hits metric along with the product or backend
+func plannedMetricList() *threescaleapi.MetricJSONList {
+	return &threescaleapi.MetricJSONList{
+		Metrics: []threescaleapi.MetricJSON{
+			{Element: threescaleapi.MetricItem{ID: DryRunPlannedID, Name: "Hits", SystemName: "hits", Description: "Number of API hits", Unit: "hit"}},
+		},
+	}
+}
+
+// paramsWith returns a copy of the params with the given key value pairs added
+func paramsWith(params threescaleapi.Params, keyValues ...string) threescaleapi.Params {
+	result := threescaleapi.Params{}
+	for key, value := range params {
+		result[key] = value
+	}
+
+	for idx := 0; idx+1 < len(keyValues); idx += 2 {
+		result[keyValues[idx]] = keyValues[idx+1]
+	}
+
+	return result
+}
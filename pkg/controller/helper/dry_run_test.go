@@ -0,0 +1,158 @@
+package helper
+
+import (
+	"net/http"
+	"testing"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+	logrtesting "github.com/go-logr/logr/testing"
+)
+
+func TestProductEntityDryRun(t *testing.T) {
+	token := "12345"
+
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		if req.Method != http.MethodGet {
+			t.Fatalf("unexpected %s request in dry-run mode: %s", req.Method, req.URL.Path)
+		}
+		return GetMethodsMetricsRoundTripFunc(req)
+	})
+	client := threescaleapi.NewThreeScale(NewTestAdminPortal(t), token, httpClient)
+
+	productEntity := NewProductEntity(&threescaleapi.Product{Element: threescaleapi.ProductItem{ID: 1}}, client, logrtesting.NullLogger{})
+	dryRun := NewDryRunRecorder()
+	productEntity.SetDryRun(dryRun)
+
+	createMethod := dryRun.Track("SyncMethods", func(interface{}) error {
+		return productEntity.CreateMethod(threescaleapi.Params{"system_name": "method_02"})
+	})
+	ok(t, createMethod(nil))
+	ok(t, productEntity.DeleteMetric(2))
+	ok(t, productEntity.UpdateProxy(threescaleapi.Params{"endpoint": "https://example.com"}))
+
+	plan, err := productEntity.CreateApplicationPlan(threescaleapi.Params{"system_name": "basic", "name": "basic"})
+	ok(t, err)
+	equals(t, DryRunPlannedID, plan.Element.ID)
+
+	// methods planned to be created can be referenced
+	metricID, err := productEntity.FindMethodMetricIDBySystemName("method_02")
+	ok(t, err)
+	equals(t, DryRunPlannedID, metricID)
+
+	// the cached methods are not modified
+	methodList, err := productEntity.Methods()
+	ok(t, err)
+	equals(t, 1, len(methodList.Methods))
+
+	changes := dryRun.Changes()
+	equals(t, 4, len(changes))
+	equals(t, DryRunChange{Task: "SyncMethods", Action: DryRunActionCreate, Object: "method", Params: threescaleapi.Params{"system_name": "method_02"}}, changes[0])
+	equals(t, DryRunActionDelete, changes[1].Action)
+	equals(t, int64(2), *changes[1].ID)
+	equals(t, "", changes[1].Task)
+	equals(t, "proxy", changes[2].Object)
+	equals(t, "application plan", changes[3].Object)
+}
+
+func TestProductEntityDryRunPlanned(t *testing.T) {
+	token := "12345"
+
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		t.Fatalf("unexpected %s request for planned product: %s", req.Method, req.URL.Path)
+		return nil
+	})
+	client := threescaleapi.NewThreeScale(NewTestAdminPortal(t), token, httpClient)
+
+	productEntity := NewProductEntity(&threescaleapi.Product{Element: threescaleapi.ProductItem{ID: DryRunPlannedID, SystemName: "myproduct"}}, client, logrtesting.NullLogger{})
+	dryRun := NewDryRunRecorder()
+	productEntity.SetDryRun(dryRun)
+	assert(t, productEntity.IsPlanned(), "planned product expected")
+
+	// only the hits metric is created along with the product
+	metrics, err := productEntity.Metrics()
+	ok(t, err)
+	equals(t, 1, len(metrics.Metrics))
+	equals(t, "hits", metrics.Metrics[0].Element.SystemName)
+
+	methods, err := productEntity.Methods()
+	ok(t, err)
+	equals(t, 0, len(methods.Methods))
+
+	mappingRules, err := productEntity.MappingRules()
+	ok(t, err)
+	equals(t, 0, len(mappingRules.MappingRules))
+
+	backendUsages, err := productEntity.BackendUsages()
+	ok(t, err)
+	equals(t, 0, len(backendUsages))
+
+	plans, err := productEntity.ApplicationPlans()
+	ok(t, err)
+	equals(t, 0, len(plans.Plans))
+
+	_, err = productEntity.Proxy()
+	ok(t, err)
+	_, err = productEntity.Policies()
+	ok(t, err)
+	_, err = productEntity.OIDCConfiguration()
+	ok(t, err)
+
+	stagingConfig, err := productEntity.StagingProxyConfig()
+	ok(t, err)
+	assert(t, stagingConfig == nil, "planned product not deployed to staging")
+
+	ok(t, productEntity.CreateMethod(threescaleapi.Params{"system_name": "method_01"}))
+	equals(t, DryRunActionCreate, dryRun.Changes()[0].Action)
+}
+
+func TestBackendAPIEntityDryRunPlanned(t *testing.T) {
+	token := "12345"
+
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		t.Fatalf("unexpected %s request for planned backend: %s", req.Method, req.URL.Path)
+		return nil
+	})
+	client := threescaleapi.NewThreeScale(NewTestAdminPortal(t), token, httpClient)
+
+	backendEntity := NewBackendAPIEntity(&threescaleapi.BackendApi{Element: threescaleapi.BackendApiItem{ID: DryRunPlannedID, SystemName: "mybackend"}}, client, logrtesting.NullLogger{})
+	dryRun := NewDryRunRecorder()
+	backendEntity.SetDryRun(dryRun)
+	assert(t, backendEntity.IsPlanned(), "planned backend expected")
+
+	metricID, err := backendEntity.FindMethodMetricIDBySystemName("hits")
+	ok(t, err)
+	equals(t, DryRunPlannedID, metricID)
+
+	methods, err := backendEntity.Methods()
+	ok(t, err)
+	equals(t, 0, len(methods.Methods))
+
+	mappingRules, err := backendEntity.MappingRules()
+	ok(t, err)
+	equals(t, 0, len(mappingRules.MappingRules))
+}
+
+func TestApplicationPlanEntityDryRunPlanned(t *testing.T) {
+	token := "12345"
+
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		t.Fatalf("unexpected %s request for planned plan: %s", req.Method, req.URL.Path)
+		return nil
+	})
+	client := threescaleapi.NewThreeScale(NewTestAdminPortal(t), token, httpClient)
+
+	planEntity := NewApplicationPlanEntity(1, threescaleapi.ApplicationPlanItem{ID: DryRunPlannedID, SystemName: "basic"}, client, logrtesting.NullLogger{})
+	dryRun := NewDryRunRecorder()
+	planEntity.SetDryRun(dryRun)
+
+	limits, err := planEntity.Limits()
+	ok(t, err)
+	equals(t, 0, len(limits.Limits))
+
+	pricingRules, err := planEntity.PricingRules()
+	ok(t, err)
+	equals(t, 0, len(pricingRules.Rules))
+
+	ok(t, planEntity.CreateLimit(3, threescaleapi.Params{"period": "day", "value": "10"}))
+	equals(t, threescaleapi.Params{"period": "day", "value": "10", "application_plan": "basic", "metric_id": "3"}, dryRun.Changes()[0].Params)
+}
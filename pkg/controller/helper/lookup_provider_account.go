@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
 	"github.com/3scale/3scale-operator/pkg/helper"
 
@@ -26,23 +28,62 @@ var (
 	providerAccountSecretTokenFieldName = "token"
 )
 
-type providerAccountSource func(cl client.Client, ns string, providerAccountRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error)
+const (
+	// ProviderAccountAllowedNamespacesAnnotation is the annotation of the provider account secret
+	// with the comma separated list of namespaces allowed to reference the secret from another namespace
+	ProviderAccountAllowedNamespacesAnnotation = "capabilities.3scale.net/allowed-namespaces"
+)
+
+// Provider account secrets may be referenced from any namespace
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get
+
+// TenantNotFoundError represents that the Tenant custom resource referenced
+// for the provider account credentials does not exist
+type TenantNotFoundError struct {
+	Namespace string
+	Name      string
+}
+
+func (e *TenantNotFoundError) Error() string {
+	return fmt.Sprintf("tenant '%s/%s' not found", e.Namespace, e.Name)
+}
+
+// IsTenantNotFoundError returns true when the error, or any error it wraps, is a TenantNotFoundError
+func IsTenantNotFoundError(err error) bool {
+	var tenantNotFoundErr *TenantNotFoundError
+	return errors.As(err, &tenantNotFoundErr)
+}
+
+type providerAccountSource func(cl client.Client, ns string, providerAccountRef *corev1.SecretReference, tenantRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error)
 
 // LookupProviderAccount looks up for account provider url and credentials
 // If provider_account_reference is provided, it must exist and required fields must exists
-// If no provider_account_reference is provided, defaul provider account secret with hardcoded name will be looked up in the namespace.
+// If provider_account_namespace is provided and it is not the current namespace,
+// the secret must allow the current namespace in the allowed namespaces annotation.
+// If no provider_account_reference is provided and tenant_reference is provided, the tenant must exist
+// and the credentials are read from the tenant secret.
+// If no provider_account_reference nor tenant_reference is provided, defaul provider account secret with hardcoded name will be looked up in the namespace.
 // If no provider_account_reference is provided AND default provider account secret is not found either, then,
 // 3scale default provider account (3scale-admin) will be looked up using system-seed secret in the current namespace.
 // If nothing is successfully found, return error
-func LookupProviderAccount(cl client.Client, ns string, providerAccountRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error) {
+func LookupProviderAccount(cl client.Client, ns string, providerAccountRef *corev1.LocalObjectReference, providerAccountNamespace *string, tenantRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error) {
+	var secretRef *corev1.SecretReference
+	if providerAccountRef != nil {
+		secretRef = &corev1.SecretReference{Name: providerAccountRef.Name}
+		if providerAccountNamespace != nil {
+			secretRef.Namespace = *providerAccountNamespace
+		}
+	}
+
 	orderedSources := []providerAccountSource{
 		providerAccountFromSecretReferenceSource,
+		providerAccountFromTenantReferenceSource,
 		providerAccountFromDefaultSecretSource,
 		providerAccountFromLocal3scaleSource,
 	}
 
 	for _, source := range orderedSources {
-		providerAccount, err := source(cl, ns, providerAccountRef, logger)
+		providerAccount, err := source(cl, ns, secretRef, tenantRef, logger)
 		if err != nil {
 			return nil, fmt.Errorf("LookupProviderAccount: %w", err)
 		}
@@ -57,26 +98,101 @@ func LookupProviderAccount(cl client.Client, ns string, providerAccountRef *core
 	return nil, errors.New("LookupProviderAccount: no provider account found")
 }
 
-func providerAccountFromSecretReferenceSource(cl client.Client, ns string, providerAccountRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error) {
+func providerAccountFromSecretReferenceSource(cl client.Client, ns string, providerAccountRef *corev1.SecretReference, tenantRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error) {
 	if providerAccountRef != nil {
 		logger.Info("LookupProviderAccount", "ns", ns, "providerAccountRef", providerAccountRef)
-		secretSource := helper.NewSecretSource(cl, ns)
-		adminURLStr, err := secretSource.RequiredFieldValueFromRequiredSecret(providerAccountRef.Name, providerAccountSecretURLFieldName)
+		providerAccount, err := providerAccountFromSecretReference(cl, ns, providerAccountRef)
 		if err != nil {
 			return nil, fmt.Errorf("providerAccountFromSecretReferenceSource: %w", err)
 		}
-		token, err := secretSource.RequiredFieldValueFromRequiredSecret(providerAccountRef.Name, providerAccountSecretTokenFieldName)
+
+		return providerAccount, nil
+	}
+
+	return nil, nil
+}
+
+// Lookup credentials in the tenant secret of the referenced tenant
+func providerAccountFromTenantReferenceSource(cl client.Client, ns string, providerAccountRef *corev1.SecretReference, tenantRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error) {
+	if tenantRef != nil {
+		logger.Info("LookupProviderAccount", "ns", ns, "tenantRef", tenantRef)
+		tenant := &capabilitiesv1beta1.Tenant{}
+		err := cl.Get(context.TODO(), client.ObjectKey{Namespace: ns, Name: tenantRef.Name}, tenant)
 		if err != nil {
-			return nil, fmt.Errorf("providerAccountFromSecretReferenceSource: %w", err)
+			if apierrors.IsNotFound(err) {
+				return nil, &TenantNotFoundError{Namespace: ns, Name: tenantRef.Name}
+			}
+			return nil, fmt.Errorf("providerAccountFromTenantReferenceSource: %w", err)
+		}
+
+		// The tenant secret reference may not be defaulted yet by the tenant controller.
+		// The tenant controller writes the tenant secret in the namespace set in the tenant spec,
+		// hence the secret does not need to allow the namespace of the tenant.
+		tenant.SetDefaults()
+		providerAccount, err := providerAccountFromSecret(cl, tenant.Spec.TenantSecretRef.Namespace, tenant.Spec.TenantSecretRef.Name)
+		if err != nil {
+			return nil, fmt.Errorf("providerAccountFromTenantReferenceSource: tenant '%s': %w", tenant.Name, err)
 		}
 
-		return &ProviderAccount{AdminURLStr: adminURLStr, Token: token}, nil
+		return providerAccount, nil
 	}
 
 	return nil, nil
 }
 
-func providerAccountFromDefaultSecretSource(cl client.Client, ns string, providerAccountRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error) {
+// providerAccountFromSecretReference reads the credentials from the referenced secret.
+// Secrets in other namespaces must allow the current namespace in the allowed namespaces annotation
+func providerAccountFromSecretReference(cl client.Client, ns string, secretRef *corev1.SecretReference) (*ProviderAccount, error) {
+	secretNS := ns
+	if secretRef.Namespace != "" && secretRef.Namespace != ns {
+		secretNS = secretRef.Namespace
+		secret, err := helper.GetSecret(secretRef.Name, secretNS, cl)
+		if err != nil {
+			return nil, err
+		}
+
+		if !providerAccountSecretAllowsNamespace(secret, ns) {
+			return nil, fmt.Errorf("secret '%s/%s' does not allow references from namespace '%s'. Add the namespace to the '%s' annotation of the secret",
+				secretNS, secretRef.Name, ns, ProviderAccountAllowedNamespacesAnnotation)
+		}
+	}
+
+	return providerAccountFromSecret(cl, secretNS, secretRef.Name)
+}
+
+// providerAccountFromSecret reads the credentials from the secret
+func providerAccountFromSecret(cl client.Client, ns, name string) (*ProviderAccount, error) {
+	secretSource := helper.NewSecretSource(cl, ns)
+	adminURLStr, err := secretSource.RequiredFieldValueFromRequiredSecret(name, providerAccountSecretURLFieldName)
+	if err != nil {
+		return nil, err
+	}
+	token, err := secretSource.RequiredFieldValueFromRequiredSecret(name, providerAccountSecretTokenFieldName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProviderAccount{AdminURLStr: adminURLStr, Token: token}, nil
+}
+
+// providerAccountSecretAllowsNamespace returns true when the namespace is listed
+// in the allowed namespaces annotation of the provider account secret
+func providerAccountSecretAllowsNamespace(secret *corev1.Secret, ns string) bool {
+	allowedNamespaces, ok := secret.GetAnnotations()[ProviderAccountAllowedNamespacesAnnotation]
+	if !ok {
+		return false
+	}
+
+	for _, allowedNamespace := range strings.Split(allowedNamespaces, ",") {
+		if strings.TrimSpace(allowedNamespace) == ns {
+			return true
+		}
+	}
+
+	return false
+}
+
+func providerAccountFromDefaultSecretSource(cl client.Client, ns string, providerAccountRef *corev1.SecretReference, tenantRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error) {
 	// if exists, fiels are required.
 	defaulSecret, err := helper.GetSecret(providerAccountDefaultSecretName, ns, cl)
 	if err == nil {
@@ -98,7 +214,7 @@ func providerAccountFromDefaultSecretSource(cl client.Client, ns string, provide
 }
 
 // Lookup default provider account for the 3scale deployment in the current namespace
-func providerAccountFromLocal3scaleSource(cl client.Client, ns string, providerAccountRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error) {
+func providerAccountFromLocal3scaleSource(cl client.Client, ns string, providerAccountRef *corev1.SecretReference, tenantRef *corev1.LocalObjectReference, logger logr.Logger) (*ProviderAccount, error) {
 	// Read credentials and tenant url for default provider account of 3scale
 	listOps := []client.ListOption{client.InNamespace(ns)}
 	apimanagerList := &appsv1alpha1.APIManagerList{}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	appsv1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
 
 	logrtesting "github.com/go-logr/logr/testing"
@@ -32,7 +33,127 @@ func TestLookupProviderAccountSecretReference(t *testing.T) {
 
 	cl := fake.NewFakeClient(providerSecret)
 
-	providerAccount, err := LookupProviderAccount(cl, ns, providerAccountRef, logrtesting.NullLogger{})
+	providerAccount, err := LookupProviderAccount(cl, ns, providerAccountRef, nil, nil, logrtesting.NullLogger{})
+	ok(t, err)
+	assert(t, providerAccount != nil, "provider account returned nil")
+	equals(t, providerAccount.AdminURLStr, providerAccountURLStr)
+	equals(t, providerAccount.Token, providerAccountToken)
+}
+
+func TestLookupProviderAccountCrossNamespaceSecretReference(t *testing.T) {
+	ns := "some_namespace"
+	secretNS := "credentials_namespace"
+	secretName := "provideraccount"
+	providerAccountURLStr := "https://example.com"
+	providerAccountToken := "12345"
+
+	data := map[string]string{
+		providerAccountSecretURLFieldName:   providerAccountURLStr,
+		providerAccountSecretTokenFieldName: providerAccountToken,
+	}
+
+	providerAccountRef := &corev1.LocalObjectReference{
+		Name: secretName,
+	}
+
+	cases := []struct {
+		testName    string
+		annotations map[string]string
+		expectedErr bool
+	}{
+		{"no annotation", nil, true},
+		{"namespace not allowed", map[string]string{ProviderAccountAllowedNamespacesAnnotation: "other"}, true},
+		{"namespace allowed", map[string]string{ProviderAccountAllowedNamespacesAnnotation: "other, some_namespace"}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			providerSecret := GetTestSecret(secretNS, secretName, data)
+			providerSecret.Annotations = tc.annotations
+			cl := fake.NewFakeClient(providerSecret)
+
+			providerAccount, err := LookupProviderAccount(cl, ns, providerAccountRef, &secretNS, nil, logrtesting.NullLogger{})
+			if tc.expectedErr {
+				assert(subT, err != nil, "error expected")
+				return
+			}
+
+			ok(subT, err)
+			assert(subT, providerAccount != nil, "provider account returned nil")
+			equals(subT, providerAccount.AdminURLStr, providerAccountURLStr)
+			equals(subT, providerAccount.Token, providerAccountToken)
+		})
+	}
+}
+
+func TestLookupProviderAccountTenantReference(t *testing.T) {
+	ns := "some_namespace"
+	providerAccountURLStr := "https://example.com"
+	providerAccountToken := "12345"
+
+	s := scheme.Scheme
+	err := capabilitiesv1beta1.AddToScheme(s)
+	ok(t, err)
+
+	tenant := &capabilitiesv1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "mytenant", Namespace: ns},
+		Spec: capabilitiesv1beta1.TenantSpec{
+			OrganizationName: "Org",
+		},
+	}
+
+	data := map[string]string{
+		providerAccountSecretURLFieldName:   providerAccountURLStr,
+		providerAccountSecretTokenFieldName: providerAccountToken,
+	}
+	// default tenant secret name
+	tenantSecret := GetTestSecret(ns, "mytenant-org", data)
+
+	// the tenant secret takes precedence over the default provider account secret
+	defaultSecret := GetTestSecret(ns, providerAccountDefaultSecretName, map[string]string{
+		providerAccountSecretURLFieldName:   "https://default.example.com",
+		providerAccountSecretTokenFieldName: "67890",
+	})
+
+	cl := fake.NewFakeClientWithScheme(s, tenant, tenantSecret, defaultSecret)
+
+	providerAccount, err := LookupProviderAccount(cl, ns, nil, nil, &corev1.LocalObjectReference{Name: "mytenant"}, logrtesting.NullLogger{})
+	ok(t, err)
+	assert(t, providerAccount != nil, "provider account returned nil")
+	equals(t, providerAccount.AdminURLStr, providerAccountURLStr)
+	equals(t, providerAccount.Token, providerAccountToken)
+
+	_, err = LookupProviderAccount(cl, ns, nil, nil, &corev1.LocalObjectReference{Name: "unknown"}, logrtesting.NullLogger{})
+	assert(t, IsTenantNotFoundError(err), "tenant not found error expected, got %v", err)
+}
+
+func TestLookupProviderAccountTenantReferenceSecretNamespace(t *testing.T) {
+	ns := "some_namespace"
+	secretNS := "tenants_namespace"
+	providerAccountURLStr := "https://example.com"
+	providerAccountToken := "12345"
+
+	s := scheme.Scheme
+	err := capabilitiesv1beta1.AddToScheme(s)
+	ok(t, err)
+
+	tenant := &capabilitiesv1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "mytenant", Namespace: ns},
+		Spec: capabilitiesv1beta1.TenantSpec{
+			OrganizationName: "Org",
+			TenantSecretRef:  corev1.SecretReference{Name: "mytenant-secret", Namespace: secretNS},
+		},
+	}
+
+	// the tenant secret does not need the allowed namespaces annotation
+	tenantSecret := GetTestSecret(secretNS, "mytenant-secret", map[string]string{
+		providerAccountSecretURLFieldName:   providerAccountURLStr,
+		providerAccountSecretTokenFieldName: providerAccountToken,
+	})
+
+	cl := fake.NewFakeClientWithScheme(s, tenant, tenantSecret)
+
+	providerAccount, err := LookupProviderAccount(cl, ns, nil, nil, &corev1.LocalObjectReference{Name: "mytenant"}, logrtesting.NullLogger{})
 	ok(t, err)
 	assert(t, providerAccount != nil, "provider account returned nil")
 	equals(t, providerAccount.AdminURLStr, providerAccountURLStr)
@@ -52,7 +173,7 @@ func TestLookupProviderAccountDefaultSecret(t *testing.T) {
 
 	cl := fake.NewFakeClient(providerSecret)
 
-	providerAccount, err := LookupProviderAccount(cl, ns, nil, logrtesting.NullLogger{})
+	providerAccount, err := LookupProviderAccount(cl, ns, nil, nil, nil, logrtesting.NullLogger{})
 	ok(t, err)
 	assert(t, providerAccount != nil, "provider account returned nil")
 	equals(t, providerAccount.AdminURLStr, providerAccountURLStr)
@@ -85,7 +206,7 @@ func TestLookupProviderAccountLocal3scale(t *testing.T) {
 
 	cl := fake.NewFakeClient(apimanager, secret)
 
-	providerAccount, err := LookupProviderAccount(cl, ns, nil, logrtesting.NullLogger{})
+	providerAccount, err := LookupProviderAccount(cl, ns, nil, nil, nil, logrtesting.NullLogger{})
 	ok(t, err)
 	assert(t, providerAccount != nil, "provider account returned nil")
 	equals(t, providerAccount.AdminURLStr, "https://testaccount-admin.example.com")
@@ -95,6 +216,6 @@ func TestLookupProviderAccountLocal3scale(t *testing.T) {
 func TestLookupProviderAccountNotFoundError(t *testing.T) {
 	ns := "some_namespace"
 	cl := fake.NewFakeClient()
-	_, err := LookupProviderAccount(cl, ns, nil, logrtesting.NullLogger{})
+	_, err := LookupProviderAccount(cl, ns, nil, nil, nil, logrtesting.NullLogger{})
 	equals(t, errors.New("LookupProviderAccount: no provider account found"), err)
 }
@@ -0,0 +1,131 @@
+package helper
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// OpenAPIURLCredentialsTokenField is the field name of the credentials secret where the bearer token can be found
+	OpenAPIURLCredentialsTokenField = "token"
+
+	// OpenAPIURLCredentialsUsernameField is the field name of the credentials secret where the basic auth username can be found
+	OpenAPIURLCredentialsUsernameField = "username"
+
+	// OpenAPIURLCredentialsPasswordField is the field name of the credentials secret where the basic auth password can be found
+	OpenAPIURLCredentialsPasswordField = "password"
+
+	openAPIFetchTimeout = 30 * time.Second
+)
+
+// FetchOpenAPIURL fetches the OpenAPI document from the remote URL.
+// Credentials secret and CA bundle configmap from the options are read from the given namespace.
+func FetchOpenAPIURL(ctx context.Context, cl client.Client, namespace string, openAPIURL *url.URL, options *capabilitiesv1beta1.OpenAPIURLOptionsSpec) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, openAPIURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{Timeout: openAPIFetchTimeout}
+
+	if options != nil {
+		for name, value := range options.Headers {
+			req.Header.Set(name, value)
+		}
+
+		if options.CredentialsRef != nil {
+			err = setOpenAPIURLCredentials(cl, namespace, options.CredentialsRef, req)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if options.CABundleRef != nil {
+			rootCAs, err := openAPIURLRootCAs(ctx, cl, namespace, options.CABundleRef)
+			if err != nil {
+				return nil, err
+			}
+
+			httpClient.Transport = &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{RootCAs: rootCAs},
+			}
+		}
+	}
+
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func setOpenAPIURLCredentials(cl client.Client, namespace string, credentialsRef *corev1.LocalObjectReference, req *http.Request) error {
+	secretSource := helper.NewSecretSource(cl, namespace)
+
+	token, err := secretSource.FieldValueFromRequiredSecret(credentialsRef.Name, OpenAPIURLCredentialsTokenField, "")
+	if err != nil {
+		return err
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		return nil
+	}
+
+	username, err := secretSource.RequiredFieldValueFromRequiredSecret(credentialsRef.Name, OpenAPIURLCredentialsUsernameField)
+	if err != nil {
+		return err
+	}
+
+	password, err := secretSource.FieldValueFromRequiredSecret(credentialsRef.Name, OpenAPIURLCredentialsPasswordField, "")
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(username, password)
+	return nil
+}
+
+func openAPIURLRootCAs(ctx context.Context, cl client.Client, namespace string, caBundleRef *corev1.ConfigMapKeySelector) (*x509.CertPool, error) {
+	configMap := &corev1.ConfigMap{}
+	err := cl.Get(ctx, types.NamespacedName{Name: caBundleRef.Name, Namespace: namespace}, configMap)
+	if err != nil {
+		return nil, err
+	}
+
+	caBundle, ok := configMap.Data[caBundleRef.Key]
+	if !ok {
+		return nil, fmt.Errorf("configmap %s does not have key %s", caBundleRef.Name, caBundleRef.Key)
+	}
+
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+
+	if !rootCAs.AppendCertsFromPEM([]byte(caBundle)) {
+		return nil, fmt.Errorf("configmap %s key %s does not contain valid PEM certificates", caBundleRef.Name, caBundleRef.Key)
+	}
+
+	return rootCAs, nil
+}
@@ -0,0 +1,91 @@
+package helper
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const openAPIFetchTestDocument = `{"openapi": "3.0.2"}`
+
+func TestFetchOpenAPIURL(t *testing.T) {
+	ns := "some_namespace"
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		username, password, basicOK := req.BasicAuth()
+		authorized := req.Header.Get("Authorization") == "Bearer s3cr3t" || (basicOK && username == "admin" && password == "p4ss")
+		if !authorized || req.Header.Get("X-Tenant") != "ecorp" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(openAPIFetchTestDocument))
+	}))
+	defer server.Close()
+
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	caConfigMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: ns},
+		Data:       map[string]string{"ca.crt": string(caBundle)},
+	}
+
+	cl := fake.NewFakeClient(
+		caConfigMap,
+		GetTestSecret(ns, "token-secret", map[string]string{"token": "s3cr3t"}),
+		GetTestSecret(ns, "basic-secret", map[string]string{"username": "admin", "password": "p4ss"}),
+		GetTestSecret(ns, "wrong-secret", map[string]string{"token": "wrong"}),
+	)
+
+	serverURL, err := url.Parse(server.URL)
+	ok(t, err)
+
+	caBundleRef := &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "ca-bundle"}, Key: "ca.crt"}
+	headers := map[string]string{"X-Tenant": "ecorp"}
+
+	cases := []struct {
+		name        string
+		options     *capabilitiesv1beta1.OpenAPIURLOptionsSpec
+		expectedErr bool
+	}{
+		{"untrusted certificate", nil, true},
+		{"bearer token", &capabilitiesv1beta1.OpenAPIURLOptionsSpec{
+			CredentialsRef: &v1.LocalObjectReference{Name: "token-secret"}, Headers: headers, CABundleRef: caBundleRef,
+		}, false},
+		{"basic auth", &capabilitiesv1beta1.OpenAPIURLOptionsSpec{
+			CredentialsRef: &v1.LocalObjectReference{Name: "basic-secret"}, Headers: headers, CABundleRef: caBundleRef,
+		}, false},
+		{"wrong credentials", &capabilitiesv1beta1.OpenAPIURLOptionsSpec{
+			CredentialsRef: &v1.LocalObjectReference{Name: "wrong-secret"}, Headers: headers, CABundleRef: caBundleRef,
+		}, true},
+		{"missing headers", &capabilitiesv1beta1.OpenAPIURLOptionsSpec{
+			CredentialsRef: &v1.LocalObjectReference{Name: "token-secret"}, CABundleRef: caBundleRef,
+		}, true},
+		{"credentials secret not found", &capabilitiesv1beta1.OpenAPIURLOptionsSpec{
+			CredentialsRef: &v1.LocalObjectReference{Name: "unknown"}, Headers: headers, CABundleRef: caBundleRef,
+		}, true},
+		{"ca bundle key not found", &capabilitiesv1beta1.OpenAPIURLOptionsSpec{
+			CredentialsRef: &v1.LocalObjectReference{Name: "token-secret"}, Headers: headers,
+			CABundleRef: &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "ca-bundle"}, Key: "unknown"},
+		}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			data, err := FetchOpenAPIURL(context.TODO(), cl, ns, serverURL, tc.options)
+			if tc.expectedErr {
+				assert(subT, err != nil, "expected error")
+				return
+			}
+			ok(subT, err)
+			equals(subT, openAPIFetchTestDocument, string(data))
+		})
+	}
+}
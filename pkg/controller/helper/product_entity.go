@@ -3,6 +3,7 @@ package helper
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/3scale/3scale-operator/pkg/helper"
 	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
@@ -22,6 +23,8 @@ type ProductEntity struct {
 	plans             *threescaleapi.ApplicationPlanJSONList
 	policies          *threescaleapi.PoliciesConfigList
 	oidcConf          *threescaleapi.OIDCConfiguration
+	stagingConfig     *threescaleapi.ProxyConfig
+	dryRun            *DryRunRecorder
 	logger            logr.Logger
 }
 
@@ -33,10 +36,25 @@ func NewProductEntity(obj *threescaleapi.Product, cl *threescaleapi.ThreeScaleCl
 	}
 }
 
+// SetDryRun enables the dry-run mode. Changes are recorded instead of applied
+func (b *ProductEntity) SetDryRun(recorder *DryRunRecorder) {
+	b.dryRun = recorder
+}
+
+// IsPlanned returns true when the product has been planned to be created in dry-run mode.
+// Planned products are empty
+func (b *ProductEntity) IsPlanned() bool {
+	return b.dryRun != nil && b.productObj.Element.ID == DryRunPlannedID
+}
+
 func (b *ProductEntity) ID() int64 {
 	return b.productObj.Element.ID
 }
 
+func (b *ProductEntity) SystemName() string {
+	return b.productObj.Element.SystemName
+}
+
 func (b *ProductEntity) Name() string {
 	return b.productObj.Element.Name
 }
@@ -59,6 +77,10 @@ func (b *ProductEntity) BackendVersion() string {
 
 func (b *ProductEntity) Update(params threescaleapi.Params) error {
 	b.logger.V(1).Info("Update", "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Update("product", b.productObj.Element.ID, params)
+		return nil
+	}
 	updated, err := b.client.UpdateProduct(b.productObj.Element.ID, params)
 	if err != nil {
 		return fmt.Errorf("product [%s] update request: %w", b.productObj.Element.SystemName, err)
@@ -83,6 +105,10 @@ func (b *ProductEntity) Methods() (*threescaleapi.MethodList, error) {
 
 func (b *ProductEntity) CreateMethod(params threescaleapi.Params) error {
 	b.logger.V(1).Info("CreateMethod", "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Create("method", params)
+		return nil
+	}
 	hitsID, err := b.getHitsID()
 	if err != nil {
 		return err
@@ -97,6 +123,10 @@ func (b *ProductEntity) CreateMethod(params threescaleapi.Params) error {
 
 func (b *ProductEntity) DeleteMethod(id int64) error {
 	b.logger.V(1).Info("DeleteMethod", "ID", id)
+	if b.dryRun != nil {
+		b.dryRun.Delete("method", id)
+		return nil
+	}
 	hitsID, err := b.getHitsID()
 	if err != nil {
 		return err
@@ -111,6 +141,10 @@ func (b *ProductEntity) DeleteMethod(id int64) error {
 
 func (b *ProductEntity) UpdateMethod(id int64, params threescaleapi.Params) error {
 	b.logger.V(1).Info("UpdateMethod", "ID", id, "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Update("method", id, params)
+		return nil
+	}
 	hitsID, err := b.getHitsID()
 	if err != nil {
 		return err
@@ -125,6 +159,10 @@ func (b *ProductEntity) UpdateMethod(id int64, params threescaleapi.Params) erro
 
 func (b *ProductEntity) CreateMetric(params threescaleapi.Params) error {
 	b.logger.V(1).Info("CreateMetric", "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Create("metric", params)
+		return nil
+	}
 	_, err := b.client.CreateProductMetric(b.productObj.Element.ID, params)
 	if err != nil {
 		return fmt.Errorf("product [%s] create metric: %w", b.productObj.Element.SystemName, err)
@@ -135,6 +173,10 @@ func (b *ProductEntity) CreateMetric(params threescaleapi.Params) error {
 
 func (b *ProductEntity) DeleteMetric(id int64) error {
 	b.logger.V(1).Info("DeleteMetric", "ID", id)
+	if b.dryRun != nil {
+		b.dryRun.Delete("metric", id)
+		return nil
+	}
 	err := b.client.DeleteProductMetric(b.productObj.Element.ID, id)
 	if err != nil {
 		return fmt.Errorf("product [%s] delete metric: %w", b.productObj.Element.SystemName, err)
@@ -145,6 +187,10 @@ func (b *ProductEntity) DeleteMetric(id int64) error {
 
 func (b *ProductEntity) UpdateMetric(id int64, params threescaleapi.Params) error {
 	b.logger.V(1).Info("UpdateMethod", "ID", id, "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Update("metric", id, params)
+		return nil
+	}
 	_, err := b.client.UpdateProductMetric(b.productObj.Element.ID, id, params)
 	if err != nil {
 		return fmt.Errorf("product [%s] update metric: %w", b.productObj.Element.SystemName, err)
@@ -188,6 +234,10 @@ func (b *ProductEntity) MappingRules() (*threescaleapi.MappingRuleJSONList, erro
 
 func (b *ProductEntity) DeleteMappingRule(id int64) error {
 	b.logger.V(1).Info("DeleteMappingRule", "ID", id)
+	if b.dryRun != nil {
+		b.dryRun.Delete("mapping rule", id)
+		return nil
+	}
 	err := b.client.DeleteProductMappingRule(b.productObj.Element.ID, id)
 	if err != nil {
 		return fmt.Errorf("product [%s] delete mapping rule: %w", b.productObj.Element.SystemName, err)
@@ -198,6 +248,10 @@ func (b *ProductEntity) DeleteMappingRule(id int64) error {
 
 func (b *ProductEntity) CreateMappingRule(params threescaleapi.Params) error {
 	b.logger.V(1).Info("CreateMappingRule", "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Create("mapping rule", params)
+		return nil
+	}
 	_, err := b.client.CreateProductMappingRule(b.productObj.Element.ID, params)
 	if err != nil {
 		return fmt.Errorf("product [%s] create mappingrule: %w", b.productObj.Element.SystemName, err)
@@ -208,6 +262,10 @@ func (b *ProductEntity) CreateMappingRule(params threescaleapi.Params) error {
 
 func (b *ProductEntity) UpdateMappingRule(id int64, params threescaleapi.Params) error {
 	b.logger.V(1).Info("UpdateMappingRule", "ID", id, "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Update("mapping rule", id, params)
+		return nil
+	}
 	_, err := b.client.UpdateProductMappingRule(b.productObj.Element.ID, id, params)
 	if err != nil {
 		return fmt.Errorf("product [%s] update mappingrule: %w", b.productObj.Element.SystemName, err)
@@ -219,6 +277,10 @@ func (b *ProductEntity) UpdateMappingRule(id int64, params threescaleapi.Params)
 // FindMethodMetricIDBySystemName returns metric or method ID from system name.
 // -1 if metric and method is not found
 func (b *ProductEntity) FindMethodMetricIDBySystemName(systemName string) (int64, error) {
+	if b.dryRun != nil && (b.dryRun.IsPlanned("method", systemName) || b.dryRun.IsPlanned("metric", systemName)) {
+		return DryRunPlannedID, nil
+	}
+
 	metricsMethodList, err := b.MetricsAndMethods()
 	if err != nil {
 		return -1, err
@@ -247,6 +309,10 @@ func (b *ProductEntity) BackendUsages() (threescaleapi.BackendAPIUsageList, erro
 
 func (b *ProductEntity) DeleteBackendUsage(id int64) error {
 	b.logger.V(1).Info("DeleteBackendUsage", "ID", id)
+	if b.dryRun != nil {
+		b.dryRun.Delete("backend usage", id)
+		return nil
+	}
 	err := b.client.DeleteBackendapiUsage(b.productObj.Element.ID, id)
 	if err != nil {
 		return fmt.Errorf("product [%s] delete backendusage: %w", b.productObj.Element.SystemName, err)
@@ -257,6 +323,10 @@ func (b *ProductEntity) DeleteBackendUsage(id int64) error {
 
 func (b *ProductEntity) UpdateBackendUsage(id int64, params threescaleapi.Params) error {
 	b.logger.V(1).Info("UpdateBackendUsage", "ID", id, "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Update("backend usage", id, params)
+		return nil
+	}
 	_, err := b.client.UpdateBackendapiUsage(b.productObj.Element.ID, id, params)
 	if err != nil {
 		return fmt.Errorf("product [%s] update backendusage: %w", b.productObj.Element.SystemName, err)
@@ -267,6 +337,10 @@ func (b *ProductEntity) UpdateBackendUsage(id int64, params threescaleapi.Params
 
 func (b *ProductEntity) CreateBackendUsage(params threescaleapi.Params) error {
 	b.logger.V(1).Info("CreateBackendUsage", "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Create("backend usage", params)
+		return nil
+	}
 	_, err := b.client.CreateBackendapiUsage(b.productObj.Element.ID, params)
 	if err != nil {
 		return fmt.Errorf("product [%s] update backendusage: %w", b.productObj.Element.SystemName, err)
@@ -289,6 +363,10 @@ func (b *ProductEntity) Proxy() (*threescaleapi.ProxyJSON, error) {
 
 func (b *ProductEntity) UpdateProxy(params threescaleapi.Params) error {
 	b.logger.V(1).Info("UpdateProxy", "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Update("proxy", b.productObj.Element.ID, params)
+		return nil
+	}
 	updated, err := b.client.UpdateProductProxy(b.productObj.Element.ID, params)
 	if err != nil {
 		return fmt.Errorf("product [%s] update proxy: %w", b.productObj.Element.SystemName, err)
@@ -312,6 +390,10 @@ func (b *ProductEntity) ApplicationPlans() (*threescaleapi.ApplicationPlanJSONLi
 
 func (b *ProductEntity) DeleteApplicationPlan(id int64) error {
 	b.logger.V(1).Info("DeleteApplicationPlan", "ID", id)
+	if b.dryRun != nil {
+		b.dryRun.Delete("application plan", id)
+		return nil
+	}
 	err := b.client.DeleteApplicationPlan(b.productObj.Element.ID, id)
 	if err != nil {
 		return fmt.Errorf("product [%s] delete applicationPlan: %w", b.productObj.Element.SystemName, err)
@@ -322,6 +404,16 @@ func (b *ProductEntity) DeleteApplicationPlan(id int64) error {
 
 func (b *ProductEntity) CreateApplicationPlan(params threescaleapi.Params) (*threescaleapi.ApplicationPlan, error) {
 	b.logger.V(1).Info("CreateApplicationPlan", "params", params)
+	if b.dryRun != nil {
+		b.dryRun.Create("application plan", params)
+		return &threescaleapi.ApplicationPlan{
+			Element: threescaleapi.ApplicationPlanItem{
+				ID:         DryRunPlannedID,
+				Name:       params["name"],
+				SystemName: params["system_name"],
+			},
+		}, nil
+	}
 	obj, err := b.client.CreateApplicationPlan(b.productObj.Element.ID, params)
 	if err != nil {
 		return nil, fmt.Errorf("product [%s] create plan: %w", b.productObj.Element.SystemName, err)
@@ -332,15 +424,34 @@ func (b *ProductEntity) CreateApplicationPlan(params threescaleapi.Params) (*thr
 
 func (b *ProductEntity) PromoteProxyToStaging() error {
 	b.logger.V(1).Info("PromoteProxyToStaging")
+	if b.dryRun != nil {
+		b.dryRun.Update("staging proxy configuration", b.productObj.Element.ID, nil)
+		return nil
+	}
 	proxyObj, err := b.client.DeployProductProxy(b.productObj.Element.ID)
 	if err != nil {
 		return fmt.Errorf("product [%s] promote proxy to staging: %w", b.productObj.Element.SystemName, err)
 	}
 
 	b.proxy = proxyObj
+	b.resetStagingProxyConfig()
 	return nil
 }
 
+// StagingProxyConfig returns the latest proxy config deployed to the staging environment.
+// Returns nil when the proxy has never been deployed to staging
+func (b *ProductEntity) StagingProxyConfig() (*threescaleapi.ProxyConfig, error) {
+	b.logger.V(1).Info("StagingProxyConfig")
+	if b.stagingConfig == nil {
+		stagingConfig, err := b.getStagingProxyConfig()
+		if err != nil {
+			return nil, err
+		}
+		b.stagingConfig = stagingConfig
+	}
+	return b.stagingConfig, nil
+}
+
 func (b *ProductEntity) Policies() (*threescaleapi.PoliciesConfigList, error) {
 	b.logger.V(1).Info("Policies")
 	if b.policies == nil {
@@ -356,6 +467,10 @@ func (b *ProductEntity) Policies() (*threescaleapi.PoliciesConfigList, error) {
 func (b *ProductEntity) UpdatePolicies(policies *threescaleapi.PoliciesConfigList) error {
 	policiesJSON, _ := json.Marshal(policies)
 	b.logger.V(1).Info("UpdatePolicies", "policies", string(policiesJSON))
+	if b.dryRun != nil {
+		b.dryRun.Update("policies", b.productObj.Element.ID, threescaleapi.Params{"policies_config": string(policiesJSON)})
+		return nil
+	}
 	_, err := b.client.UpdatePolicies(b.productObj.Element.ID, policies)
 	if err != nil {
 		return fmt.Errorf("product [%s] update policies: %w", b.productObj.Element.SystemName, err)
@@ -377,6 +492,15 @@ func (b *ProductEntity) OIDCConfiguration() (*threescaleapi.OIDCConfiguration, e
 
 func (b *ProductEntity) UpdateOIDCConfiguration(oidcConf *threescaleapi.OIDCConfiguration) error {
 	b.logger.V(1).Info("UpdateOIDCConfiguration", "oidcConf", oidcConf)
+	if b.dryRun != nil {
+		b.dryRun.Update("oidc configuration", b.productObj.Element.ID, threescaleapi.Params{
+			"standard_flow_enabled":        strconv.FormatBool(oidcConf.Element.StandardFlowEnabled),
+			"implicit_flow_enabled":        strconv.FormatBool(oidcConf.Element.ImplicitFlowEnabled),
+			"service_accounts_enabled":     strconv.FormatBool(oidcConf.Element.ServiceAccountsEnabled),
+			"direct_access_grants_enabled": strconv.FormatBool(oidcConf.Element.DirectAccessGrantsEnabled),
+		})
+		return nil
+	}
 	obj, err := b.client.UpdateOIDCConfiguration(b.productObj.Element.ID, oidcConf)
 	if err != nil {
 		return fmt.Errorf("product [%s] update oidc: %w", b.productObj.Element.SystemName, err)
@@ -409,12 +533,19 @@ func (b *ProductEntity) resetMappingRules() {
 	b.mappingRules = nil
 }
 
+func (b *ProductEntity) resetStagingProxyConfig() {
+	b.stagingConfig = nil
+}
+
 func (b *ProductEntity) resetApplicationPlans() {
 	b.plans = nil
 }
 
 func (b *ProductEntity) getMethods() (*threescaleapi.MethodList, error) {
 	b.logger.V(1).Info("getMethods")
+	if b.IsPlanned() {
+		return &threescaleapi.MethodList{}, nil
+	}
 	hitsID, err := b.getHitsID()
 	if err != nil {
 		return nil, err
@@ -464,6 +595,9 @@ func (b *ProductEntity) getMetrics() (*threescaleapi.MetricJSONList, error) {
 
 func (b *ProductEntity) getMetricsAndMethods() (*threescaleapi.MetricJSONList, error) {
 	b.logger.V(1).Info("getMetricsAndMethods")
+	if b.IsPlanned() {
+		return plannedMetricList(), nil
+	}
 	metricList, err := b.client.ListProductMetrics(b.productObj.Element.ID)
 	if err != nil {
 		return nil, fmt.Errorf("product [%s] get metrics: %w", b.productObj.Element.SystemName, err)
@@ -490,6 +624,9 @@ func (b *ProductEntity) getHitsID() (int64, error) {
 
 func (b *ProductEntity) getMappingRules() (*threescaleapi.MappingRuleJSONList, error) {
 	b.logger.V(1).Info("getMappingRules")
+	if b.IsPlanned() {
+		return &threescaleapi.MappingRuleJSONList{}, nil
+	}
 	list, err := b.client.ListProductMappingRules(b.productObj.Element.ID)
 	if err != nil {
 		return nil, fmt.Errorf("product [%s] get mapping rules: %w", b.productObj.Element.SystemName, err)
@@ -500,6 +637,9 @@ func (b *ProductEntity) getMappingRules() (*threescaleapi.MappingRuleJSONList, e
 
 func (b *ProductEntity) getBackendUsages() (threescaleapi.BackendAPIUsageList, error) {
 	b.logger.V(1).Info("getBackendUsages")
+	if b.IsPlanned() {
+		return threescaleapi.BackendAPIUsageList{}, nil
+	}
 	list, err := b.client.ListBackendapiUsages(b.productObj.Element.ID)
 	if err != nil {
 		return nil, fmt.Errorf("product [%s] get backendUsages: %w", b.productObj.Element.SystemName, err)
@@ -510,6 +650,9 @@ func (b *ProductEntity) getBackendUsages() (threescaleapi.BackendAPIUsageList, e
 
 func (b *ProductEntity) getProxy() (*threescaleapi.ProxyJSON, error) {
 	b.logger.V(1).Info("getProxy")
+	if b.IsPlanned() {
+		return &threescaleapi.ProxyJSON{}, nil
+	}
 	obj, err := b.client.ProductProxy(b.productObj.Element.ID)
 	if err != nil {
 		return nil, fmt.Errorf("product [%s] get proxy: %w", b.productObj.Element.SystemName, err)
@@ -520,6 +663,9 @@ func (b *ProductEntity) getProxy() (*threescaleapi.ProxyJSON, error) {
 
 func (b *ProductEntity) getApplicationPlans() (*threescaleapi.ApplicationPlanJSONList, error) {
 	b.logger.V(1).Info("getApplicationPlans")
+	if b.IsPlanned() {
+		return &threescaleapi.ApplicationPlanJSONList{}, nil
+	}
 	list, err := b.client.ListApplicationPlansByProduct(b.productObj.Element.ID)
 	if err != nil {
 		return nil, fmt.Errorf("product [%s] get plans: %w", b.productObj.Element.SystemName, err)
@@ -530,6 +676,9 @@ func (b *ProductEntity) getApplicationPlans() (*threescaleapi.ApplicationPlanJSO
 
 func (b *ProductEntity) getPolicies() (*threescaleapi.PoliciesConfigList, error) {
 	b.logger.V(1).Info("getPolicies")
+	if b.IsPlanned() {
+		return &threescaleapi.PoliciesConfigList{Policies: []threescaleapi.PolicyConfig{}}, nil
+	}
 	obj, err := b.client.Policies(b.productObj.Element.ID)
 	if err != nil {
 		return nil, fmt.Errorf("product [%s] get policies: %w", b.productObj.Element.SystemName, err)
@@ -540,6 +689,9 @@ func (b *ProductEntity) getPolicies() (*threescaleapi.PoliciesConfigList, error)
 
 func (b *ProductEntity) getOIDCConfiguration() (*threescaleapi.OIDCConfiguration, error) {
 	b.logger.V(1).Info("getOIDCConfiguration")
+	if b.IsPlanned() {
+		return &threescaleapi.OIDCConfiguration{}, nil
+	}
 	obj, err := b.client.OIDCConfiguration(b.productObj.Element.ID)
 	if err != nil {
 		return nil, err
@@ -547,3 +699,19 @@ func (b *ProductEntity) getOIDCConfiguration() (*threescaleapi.OIDCConfiguration
 
 	return obj, nil
 }
+
+func (b *ProductEntity) getStagingProxyConfig() (*threescaleapi.ProxyConfig, error) {
+	b.logger.V(1).Info("getStagingProxyConfig")
+	if b.IsPlanned() {
+		return nil, nil
+	}
+	obj, err := b.client.GetLatestProxyConfig(strconv.FormatInt(b.productObj.Element.ID, 10), "sandbox")
+	if err != nil {
+		if threescaleapi.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("product [%s] get staging proxy config: %w", b.productObj.Element.SystemName, err)
+	}
+
+	return &obj.ProxyConfig, nil
+}
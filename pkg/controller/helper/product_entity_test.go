@@ -823,3 +823,50 @@ func TestProductEntityUpdateOIDCConfiguration(t *testing.T) {
 	err := productEntity.UpdateOIDCConfiguration(&threescaleapi.OIDCConfiguration{})
 	ok(t, err)
 }
+
+func TestProductEntityStagingProxyConfig(t *testing.T) {
+	token := "12345"
+
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		equals(t, "/admin/api/services/3/proxy/configs/sandbox/latest.json", req.URL.Path)
+		respObject := &threescaleapi.ProxyConfigElement{
+			ProxyConfig: threescaleapi.ProxyConfig{Version: 4, Environment: "sandbox"},
+		}
+
+		responseBodyBytes, err := json.Marshal(respObject)
+		ok(t, err)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBuffer(responseBodyBytes)),
+			Header:     make(http.Header),
+		}
+	})
+
+	client := threescaleapi.NewThreeScale(NewTestAdminPortal(t), token, httpClient)
+
+	productEntity := NewProductEntity(&threescaleapi.Product{Element: threescaleapi.ProductItem{ID: 3}}, client, logrtesting.NullLogger{})
+	stagingConfig, err := productEntity.StagingProxyConfig()
+	ok(t, err)
+	assert(t, stagingConfig != nil, "staging config returned nil")
+	equals(t, 4, stagingConfig.Version)
+}
+
+func TestProductEntityStagingProxyConfigNotFound(t *testing.T) {
+	token := "12345"
+
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{"status": "Not found"}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	client := threescaleapi.NewThreeScale(NewTestAdminPortal(t), token, httpClient)
+
+	productEntity := NewProductEntity(&threescaleapi.Product{Element: threescaleapi.ProductItem{ID: 3}}, client, logrtesting.NullLogger{})
+	stagingConfig, err := productEntity.StagingProxyConfig()
+	ok(t, err)
+	assert(t, stagingConfig == nil, "staging config expected nil")
+}
@@ -0,0 +1,636 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ProductImporter reads existing 3scale products and the backends used by them
+// and generates the equivalent Product and Backend custom resources.
+// The generated custom resources match the 3scale objects,
+// so the first synchronization does not modify them.
+// The deletion policy, when not empty, is set as the deletion policy annotation of the generated custom resources.
+type ProductImporter struct {
+	client             *threescaleapi.ThreeScaleClient
+	backendRemoteIndex *BackendAPIRemoteIndex
+	namespace          string
+	providerAccountRef *corev1.LocalObjectReference
+	deletionPolicy     string
+	logger             logr.Logger
+}
+
+func NewProductImporter(client *threescaleapi.ThreeScaleClient, namespace string, providerAccountRef *corev1.LocalObjectReference, deletionPolicy string, logger logr.Logger) (*ProductImporter, error) {
+	backendRemoteIndex, err := NewBackendAPIRemoteIndex(client, logger)
+	if err != nil {
+		return nil, fmt.Errorf("product import: %w", err)
+	}
+
+	return &ProductImporter{
+		client:             client,
+		backendRemoteIndex: backendRemoteIndex,
+		namespace:          namespace,
+		providerAccountRef: providerAccountRef,
+		deletionPolicy:     deletionPolicy,
+		logger:             logger,
+	}, nil
+}
+
+// Import returns the product custom resources of the 3scale products with the given system names
+// and the backend custom resources of the backends used by them.
+// All 3scale products are imported when no system name is given.
+func (i *ProductImporter) Import(systemNames []string) ([]*capabilitiesv1beta1.Product, []*capabilitiesv1beta1.Backend, error) {
+	productList, err := i.client.ListProducts()
+	if err != nil {
+		return nil, nil, fmt.Errorf("product import: %w", err)
+	}
+
+	productObjs := map[string]*threescaleapi.Product{}
+	for idx := range productList.Products {
+		productObjs[productList.Products[idx].Element.SystemName] = &productList.Products[idx]
+	}
+
+	if len(systemNames) == 0 {
+		for systemName := range productObjs {
+			systemNames = append(systemNames, systemName)
+		}
+	}
+	sort.Strings(systemNames)
+
+	products := make([]*capabilitiesv1beta1.Product, 0, len(systemNames))
+	usedBackends := map[string]bool{}
+	for _, systemName := range systemNames {
+		productObj, ok := productObjs[systemName]
+		if !ok {
+			return nil, nil, fmt.Errorf("product import: product [%s] not found", systemName)
+		}
+
+		i.logger.Info("Importing product", "systemName", systemName)
+		product, err := i.ImportProduct(NewProductEntity(productObj, i.client, i.logger))
+		if err != nil {
+			return nil, nil, err
+		}
+		products = append(products, product)
+
+		for backendSystemName := range product.Spec.BackendUsages {
+			usedBackends[backendSystemName] = true
+		}
+	}
+
+	backendSystemNames := make([]string, 0, len(usedBackends))
+	for backendSystemName := range usedBackends {
+		backendSystemNames = append(backendSystemNames, backendSystemName)
+	}
+	sort.Strings(backendSystemNames)
+
+	backends := make([]*capabilitiesv1beta1.Backend, 0, len(backendSystemNames))
+	for _, systemName := range backendSystemNames {
+		// backend usages are built from the backend index
+		backendEntity, _ := i.backendRemoteIndex.FindBySystemName(systemName)
+
+		i.logger.Info("Importing backend", "systemName", systemName)
+		backend, err := i.ImportBackend(backendEntity)
+		if err != nil {
+			return nil, nil, err
+		}
+		backends = append(backends, backend)
+	}
+
+	err = checkImportedNames(products, backends)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return products, backends, nil
+}
+
+// ImportProduct returns the product custom resource of the 3scale product
+func (i *ProductImporter) ImportProduct(productEntity *ProductEntity) (*capabilitiesv1beta1.Product, error) {
+	product := &capabilitiesv1beta1.Product{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: capabilitiesv1beta1.GroupVersion.String(),
+			Kind:       capabilitiesv1beta1.ProductKind,
+		},
+		ObjectMeta: i.importedObjectMeta(productEntity.SystemName()),
+		Spec: capabilitiesv1beta1.ProductSpec{
+			Name:               productEntity.Name(),
+			SystemName:         productEntity.SystemName(),
+			Description:        productEntity.Description(),
+			ProviderAccountRef: i.providerAccountRef,
+		},
+	}
+
+	// Backend usages first, plans reference product and backend metrics
+	importers := []func(*ProductEntity, *capabilitiesv1beta1.Product) error{
+		i.importProductDeployment,
+		importProductMethodsMetrics,
+		importProductMappingRules,
+		i.importProductBackendUsages,
+		i.importProductApplicationPlans,
+		importProductPolicies,
+	}
+	for _, importer := range importers {
+		err := importer(productEntity, product)
+		if err != nil {
+			return nil, fmt.Errorf("product import [%s]: %w", productEntity.SystemName(), err)
+		}
+	}
+
+	return product, nil
+}
+
+// ImportBackend returns the backend custom resource of the 3scale backend
+func (i *ProductImporter) ImportBackend(backendEntity *BackendAPIEntity) (*capabilitiesv1beta1.Backend, error) {
+	backend := &capabilitiesv1beta1.Backend{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: capabilitiesv1beta1.GroupVersion.String(),
+			Kind:       capabilitiesv1beta1.BackendKind,
+		},
+		ObjectMeta: i.importedObjectMeta(backendEntity.SystemName()),
+		Spec: capabilitiesv1beta1.BackendSpec{
+			Name:               backendEntity.Name(),
+			SystemName:         backendEntity.SystemName(),
+			Description:        backendEntity.Description(),
+			PrivateBaseURL:     backendEntity.PrivateEndpoint(),
+			ProviderAccountRef: i.providerAccountRef,
+		},
+	}
+
+	methodList, err := backendEntity.Methods()
+	if err != nil {
+		return nil, fmt.Errorf("backend import [%s]: %w", backendEntity.SystemName(), err)
+	}
+	backend.Spec.Methods = importMethods(methodList)
+
+	metricList, err := backendEntity.Metrics()
+	if err != nil {
+		return nil, fmt.Errorf("backend import [%s]: %w", backendEntity.SystemName(), err)
+	}
+	backend.Spec.Metrics = importMetrics(metricList)
+
+	metricsAndMethods, err := backendEntity.MetricsAndMethods()
+	if err != nil {
+		return nil, fmt.Errorf("backend import [%s]: %w", backendEntity.SystemName(), err)
+	}
+
+	mappingRuleList, err := backendEntity.MappingRules()
+	if err != nil {
+		return nil, fmt.Errorf("backend import [%s]: %w", backendEntity.SystemName(), err)
+	}
+
+	backend.Spec.MappingRules, err = importMappingRules(mappingRuleList, metricsAndMethods)
+	if err != nil {
+		return nil, fmt.Errorf("backend import [%s]: %w", backendEntity.SystemName(), err)
+	}
+
+	return backend, nil
+}
+
+// ImportedObjectName returns the kubernetes object name of the imported 3scale object with the given system name
+func ImportedObjectName(systemName string) string {
+	return strings.Trim(helper.DNS1123Name(strings.ReplaceAll(systemName, "_", "-")), "-")
+}
+
+func (i *ProductImporter) importedObjectMeta(systemName string) metav1.ObjectMeta {
+	objectMeta := metav1.ObjectMeta{
+		Name:      ImportedObjectName(systemName),
+		Namespace: i.namespace,
+	}
+
+	if i.deletionPolicy != "" {
+		objectMeta.Annotations = map[string]string{
+			capabilitiesv1beta1.DeletionPolicyAnnotation: i.deletionPolicy,
+		}
+	}
+
+	return objectMeta
+}
+
+func (i *ProductImporter) importProductDeployment(productEntity *ProductEntity, product *capabilitiesv1beta1.Product) error {
+	proxy, err := productEntity.Proxy()
+	if err != nil {
+		return err
+	}
+
+	authentication, err := importProductAuthentication(productEntity, &proxy.Element)
+	if err != nil {
+		return err
+	}
+
+	switch productEntity.DeploymentOption() {
+	case "hosted":
+		product.Spec.Deployment = &capabilitiesv1beta1.ProductDeploymentSpec{
+			ApicastHosted: &capabilitiesv1beta1.ApicastHostedSpec{
+				Authentication: authentication,
+			},
+		}
+	case "self_managed":
+		product.Spec.Deployment = &capabilitiesv1beta1.ProductDeploymentSpec{
+			ApicastSelfManaged: &capabilitiesv1beta1.ApicastSelfManagedSpec{
+				Authentication:          authentication,
+				StagingPublicBaseURL:    importString(proxy.Element.SandboxEndpoint),
+				ProductionPublicBaseURL: importString(proxy.Element.Endpoint),
+			},
+		}
+	default:
+		// Deployment options not supported by the product custom resource are not managed
+		i.logger.Info("Deployment option not imported", "product", productEntity.SystemName(), "deploymentOption", productEntity.DeploymentOption())
+	}
+
+	return nil
+}
+
+func importProductAuthentication(productEntity *ProductEntity, proxy *threescaleapi.ProxyItem) (*capabilitiesv1beta1.AuthenticationSpec, error) {
+	var security *capabilitiesv1beta1.SecuritySpec
+	if proxy.HostnameRewrite != "" || proxy.SecretToken != "" {
+		security = &capabilitiesv1beta1.SecuritySpec{
+			HostHeader:  importString(proxy.HostnameRewrite),
+			SecretToken: importString(proxy.SecretToken),
+		}
+	}
+
+	gatewayResponse := importGatewayResponse(proxy)
+
+	switch productEntity.BackendVersion() {
+	case "1":
+		return &capabilitiesv1beta1.AuthenticationSpec{
+			UserKeyAuthentication: &capabilitiesv1beta1.UserKeyAuthenticationSpec{
+				Key:             importString(proxy.AuthUserKey),
+				CredentialsLoc:  importString(proxy.CredentialsLocation),
+				Security:        security,
+				GatewayResponse: gatewayResponse,
+			},
+		}, nil
+	case "2":
+		return &capabilitiesv1beta1.AuthenticationSpec{
+			AppKeyAppIDAuthentication: &capabilitiesv1beta1.AppKeyAppIDAuthenticationSpec{
+				AppID:           importString(proxy.AuthAppID),
+				AppKey:          importString(proxy.AuthAppKey),
+				CredentialsLoc:  importString(proxy.CredentialsLocation),
+				Security:        security,
+				GatewayResponse: gatewayResponse,
+			},
+		}, nil
+	case "oidc":
+		oidcConf, err := productEntity.OIDCConfiguration()
+		if err != nil {
+			return nil, err
+		}
+
+		return &capabilitiesv1beta1.AuthenticationSpec{
+			OIDC: &capabilitiesv1beta1.OIDCSpec{
+				IssuerType:     proxy.OidcIssuerType,
+				IssuerEndpoint: proxy.OidcIssuerEndpoint,
+				AuthenticationFlow: &capabilitiesv1beta1.OIDCAuthenticationFlowSpec{
+					StandardFlowEnabled:       oidcConf.Element.StandardFlowEnabled,
+					ImplicitFlowEnabled:       oidcConf.Element.ImplicitFlowEnabled,
+					ServiceAccountsEnabled:    oidcConf.Element.ServiceAccountsEnabled,
+					DirectAccessGrantsEnabled: oidcConf.Element.DirectAccessGrantsEnabled,
+				},
+				JwtClaimWithClientID:     importString(proxy.JwtClaimWithClientID),
+				JwtClaimWithClientIDType: importString(proxy.JwtClaimWithClientIDType),
+				CredentialsLoc:           importString(proxy.CredentialsLocation),
+				Security:                 security,
+				GatewayResponse:          gatewayResponse,
+			},
+		}, nil
+	}
+
+	// Authentication modes not supported by the product custom resource are not managed
+	return nil, nil
+}
+
+func importGatewayResponse(proxy *threescaleapi.ProxyItem) *capabilitiesv1beta1.GatewayResponseSpec {
+	gatewayResponse := &capabilitiesv1beta1.GatewayResponseSpec{
+		ErrorStatusAuthFailed:      importInt32(proxy.ErrorStatusAuthFailed),
+		ErrorHeadersAuthFailed:     importString(proxy.ErrorHeadersAuthFailed),
+		ErrorAuthFailed:            importString(proxy.ErrorAuthFailed),
+		ErrorStatusAuthMissing:     importInt32(proxy.ErrorStatusAuthMissing),
+		ErrorHeadersAuthMissing:    importString(proxy.ErrorHeadersAuthMissing),
+		ErrorAuthMissing:           importString(proxy.ErrorAuthMissing),
+		ErrorStatusNoMatch:         importInt32(proxy.ErrorStatusNoMatch),
+		ErrorHeadersNoMatch:        importString(proxy.ErrorHeadersNoMatch),
+		ErrorNoMatch:               importString(proxy.ErrorNoMatch),
+		ErrorStatusLimitsExceeded:  importInt32(proxy.ErrorStatusLimitsExceeded),
+		ErrorHeadersLimitsExceeded: importString(proxy.ErrorHeadersLimitsExceeded),
+		ErrorLimitsExceeded:        importString(proxy.ErrorLimitsExceeded),
+	}
+
+	if *gatewayResponse == (capabilitiesv1beta1.GatewayResponseSpec{}) {
+		return nil
+	}
+
+	return gatewayResponse
+}
+
+func importProductMethodsMetrics(productEntity *ProductEntity, product *capabilitiesv1beta1.Product) error {
+	methodList, err := productEntity.Methods()
+	if err != nil {
+		return err
+	}
+	product.Spec.Methods = importMethods(methodList)
+
+	metricList, err := productEntity.Metrics()
+	if err != nil {
+		return err
+	}
+	product.Spec.Metrics = importMetrics(metricList)
+
+	return nil
+}
+
+func importProductMappingRules(productEntity *ProductEntity, product *capabilitiesv1beta1.Product) error {
+	metricsAndMethods, err := productEntity.MetricsAndMethods()
+	if err != nil {
+		return err
+	}
+
+	mappingRuleList, err := productEntity.MappingRules()
+	if err != nil {
+		return err
+	}
+
+	product.Spec.MappingRules, err = importMappingRules(mappingRuleList, metricsAndMethods)
+	return err
+}
+
+func (i *ProductImporter) importProductBackendUsages(productEntity *ProductEntity, product *capabilitiesv1beta1.Product) error {
+	backendUsageList, err := productEntity.BackendUsages()
+	if err != nil {
+		return err
+	}
+
+	product.Spec.BackendUsages = map[string]capabilitiesv1beta1.BackendUsageSpec{}
+	for _, backendUsage := range backendUsageList {
+		backendEntity, ok := i.backendRemoteIndex.FindByID(backendUsage.Element.BackendAPIID)
+		if !ok {
+			return fmt.Errorf("backend ID %d not found", backendUsage.Element.BackendAPIID)
+		}
+
+		product.Spec.BackendUsages[backendEntity.SystemName()] = capabilitiesv1beta1.BackendUsageSpec{
+			Path: backendUsage.Element.Path,
+		}
+	}
+
+	return nil
+}
+
+func (i *ProductImporter) importProductApplicationPlans(productEntity *ProductEntity, product *capabilitiesv1beta1.Product) error {
+	metricRefs, err := i.metricMethodRefs(productEntity, product)
+	if err != nil {
+		return err
+	}
+
+	planList, err := productEntity.ApplicationPlans()
+	if err != nil {
+		return err
+	}
+
+	product.Spec.ApplicationPlans = map[string]capabilitiesv1beta1.ApplicationPlanSpec{}
+	for _, plan := range planList.Plans {
+		planEntity := NewApplicationPlanEntity(productEntity.ID(), plan.Element, i.client, i.logger)
+
+		planSpec, err := importApplicationPlan(planEntity, metricRefs)
+		if err != nil {
+			return fmt.Errorf("plan [%s]: %w", plan.Element.SystemName, err)
+		}
+
+		product.Spec.ApplicationPlans[plan.Element.SystemName] = *planSpec
+	}
+
+	return nil
+}
+
+// metricMethodRefs returns the references of the product metrics and methods
+// and the metrics and methods of the backends used by the product indexed by ID
+func (i *ProductImporter) metricMethodRefs(productEntity *ProductEntity, product *capabilitiesv1beta1.Product) (map[int64]capabilitiesv1beta1.MetricMethodRefSpec, error) {
+	refs := map[int64]capabilitiesv1beta1.MetricMethodRefSpec{}
+
+	metricsAndMethods, err := productEntity.MetricsAndMethods()
+	if err != nil {
+		return nil, err
+	}
+	for _, metric := range metricsAndMethods.Metrics {
+		refs[metric.Element.ID] = capabilitiesv1beta1.MetricMethodRefSpec{SystemName: metric.Element.SystemName}
+	}
+
+	for backendSystemName := range product.Spec.BackendUsages {
+		// backend usages are built from the backend index
+		backendEntity, _ := i.backendRemoteIndex.FindBySystemName(backendSystemName)
+		backendMetricsAndMethods, err := backendEntity.MetricsAndMethods()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, metric := range backendMetricsAndMethods.Metrics {
+			refs[metric.Element.ID] = capabilitiesv1beta1.MetricMethodRefSpec{
+				SystemName:        metric.Element.SystemName,
+				BackendSystemName: &[]string{backendSystemName}[0],
+			}
+		}
+	}
+
+	return refs, nil
+}
+
+func importApplicationPlan(planEntity *ApplicationPlanEntity, metricRefs map[int64]capabilitiesv1beta1.MetricMethodRefSpec) (*capabilitiesv1beta1.ApplicationPlanSpec, error) {
+	planSpec := &capabilitiesv1beta1.ApplicationPlanSpec{
+		Name:                &[]string{planEntity.Name()}[0],
+		AppsRequireApproval: &[]bool{planEntity.ApprovalRequired()}[0],
+		TrialPeriod:         &[]int{planEntity.TrialPeriodDays()}[0],
+		SetupFee:            &[]string{fmt.Sprintf("%.2f", planEntity.SetupFee())}[0],
+		CostMonth:           &[]string{fmt.Sprintf("%.2f", planEntity.CostPerMonth())}[0],
+		Published:           &[]bool{planEntity.State() == "published"}[0],
+	}
+
+	limitList, err := planEntity.Limits()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, limit := range limitList.Limits {
+		metricRef, ok := metricRefs[limit.Element.MetricID]
+		if !ok {
+			return nil, fmt.Errorf("limit metric ID %d not found", limit.Element.MetricID)
+		}
+
+		planSpec.Limits = append(planSpec.Limits, capabilitiesv1beta1.LimitSpec{
+			Period:          limit.Element.Period,
+			Value:           limit.Element.Value,
+			MetricMethodRef: metricRef,
+		})
+	}
+
+	sort.SliceStable(planSpec.Limits, func(a, b int) bool {
+		refA, refB := planSpec.Limits[a].MetricMethodRef.String(), planSpec.Limits[b].MetricMethodRef.String()
+		if refA != refB {
+			return refA < refB
+		}
+		return planSpec.Limits[a].Period < planSpec.Limits[b].Period
+	})
+
+	pricingRuleList, err := planEntity.PricingRules()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range pricingRuleList.Rules {
+		metricRef, ok := metricRefs[rule.Element.MetricID]
+		if !ok {
+			return nil, fmt.Errorf("pricing rule metric ID %d not found", rule.Element.MetricID)
+		}
+
+		// Price kept as returned by 3scale. Existing pricing rules are matched by the price string
+		planSpec.PricingRules = append(planSpec.PricingRules, capabilitiesv1beta1.PricingRuleSpec{
+			From:            rule.Element.Min,
+			To:              rule.Element.Max,
+			PricePerUnit:    rule.Element.CostPerUnit,
+			MetricMethodRef: metricRef,
+		})
+	}
+
+	sort.SliceStable(planSpec.PricingRules, func(a, b int) bool {
+		refA, refB := planSpec.PricingRules[a].MetricMethodRef.String(), planSpec.PricingRules[b].MetricMethodRef.String()
+		if refA != refB {
+			return refA < refB
+		}
+		return planSpec.PricingRules[a].From < planSpec.PricingRules[b].From
+	})
+
+	return planSpec, nil
+}
+
+func importProductPolicies(productEntity *ProductEntity, product *capabilitiesv1beta1.Product) error {
+	policyList, err := productEntity.Policies()
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range policyList.Policies {
+		configuration := []byte(`{}`)
+		if policy.Configuration != nil {
+			configuration, err = json.Marshal(policy.Configuration)
+			if err != nil {
+				return fmt.Errorf("policy [%s]: %w", policy.Name, err)
+			}
+		}
+
+		product.Spec.Policies = append(product.Spec.Policies, capabilitiesv1beta1.PolicyConfig{
+			Name:          policy.Name,
+			Version:       policy.Version,
+			Configuration: runtime.RawExtension{Raw: configuration},
+			Enabled:       policy.Enabled,
+		})
+	}
+
+	return nil
+}
+
+func importMethods(methodList *threescaleapi.MethodList) map[string]capabilitiesv1beta1.MethodSpec {
+	methods := map[string]capabilitiesv1beta1.MethodSpec{}
+	for _, method := range methodList.Methods {
+		methods[method.Element.SystemName] = capabilitiesv1beta1.MethodSpec{
+			Name:        method.Element.Name,
+			Description: method.Element.Description,
+		}
+	}
+	return methods
+}
+
+func importMetrics(metricList *threescaleapi.MetricJSONList) map[string]capabilitiesv1beta1.MetricSpec {
+	metrics := map[string]capabilitiesv1beta1.MetricSpec{}
+	for _, metric := range metricList.Metrics {
+		metrics[metric.Element.SystemName] = capabilitiesv1beta1.MetricSpec{
+			Name:        metric.Element.Name,
+			Unit:        metric.Element.Unit,
+			Description: metric.Element.Description,
+		}
+	}
+	return metrics
+}
+
+// importMappingRules returns the mapping rules in position order.
+// The position of the mapping rules is the position in the custom resource list
+func importMappingRules(mappingRuleList *threescaleapi.MappingRuleJSONList, metricsAndMethods *threescaleapi.MetricJSONList) ([]capabilitiesv1beta1.MappingRuleSpec, error) {
+	metricSystemNames := map[int64]string{}
+	for _, metric := range metricsAndMethods.Metrics {
+		metricSystemNames[metric.Element.ID] = metric.Element.SystemName
+	}
+
+	items := make([]threescaleapi.MappingRuleItem, 0, len(mappingRuleList.MappingRules))
+	for _, mappingRule := range mappingRuleList.MappingRules {
+		items = append(items, mappingRule.Element)
+	}
+	sort.SliceStable(items, func(a, b int) bool { return items[a].Position < items[b].Position })
+
+	mappingRules := make([]capabilitiesv1beta1.MappingRuleSpec, 0, len(items))
+	for _, item := range items {
+		metricSystemName, ok := metricSystemNames[item.MetricID]
+		if !ok {
+			return nil, fmt.Errorf("mapping rule %s %s: metric ID %d not found", item.HTTPMethod, item.Pattern, item.MetricID)
+		}
+
+		mappingRule := capabilitiesv1beta1.MappingRuleSpec{
+			HTTPMethod:      item.HTTPMethod,
+			Pattern:         item.Pattern,
+			MetricMethodRef: metricSystemName,
+			Increment:       item.Delta,
+		}
+		if item.Last {
+			mappingRule.Last = &[]bool{true}[0]
+		}
+
+		mappingRules = append(mappingRules, mappingRule)
+	}
+
+	return mappingRules, nil
+}
+
+// checkImportedNames returns error when different 3scale objects of the same kind
+// have the same kubernetes object name
+func checkImportedNames(products []*capabilitiesv1beta1.Product, backends []*capabilitiesv1beta1.Backend) error {
+	productNames := map[string]string{}
+	for _, product := range products {
+		if systemName, ok := productNames[product.Name]; ok {
+			return fmt.Errorf("product import: products [%s] and [%s] have the same object name %s", systemName, product.Spec.SystemName, product.Name)
+		}
+		productNames[product.Name] = product.Spec.SystemName
+	}
+
+	backendNames := map[string]string{}
+	for _, backend := range backends {
+		if systemName, ok := backendNames[backend.Name]; ok {
+			return fmt.Errorf("product import: backends [%s] and [%s] have the same object name %s", systemName, backend.Spec.SystemName, backend.Name)
+		}
+		backendNames[backend.Name] = backend.Spec.SystemName
+	}
+
+	return nil
+}
+
+// importString returns nil for empty values, which are not reconciled
+func importString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// importInt32 returns nil for zero values, which are not reconciled
+func importInt32(value int) *int32 {
+	if value == 0 {
+		return nil
+	}
+	result := int32(value)
+	return &result
+}
@@ -0,0 +1,215 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+	logrtesting "github.com/go-logr/logr/testing"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func importRoundTripFunc(t *testing.T) RoundTripFunc {
+	responses := map[string]interface{}{
+		"/admin/api/services.json": &threescaleapi.ProductList{
+			Products: []threescaleapi.Product{
+				{Element: threescaleapi.ProductItem{
+					ID: 1, Name: "API One", SystemName: "api_one", Description: "first",
+					DeploymentOption: "self_managed", BackendVersion: "2",
+				}},
+				{Element: threescaleapi.ProductItem{ID: 2, Name: "API Two", SystemName: "api_two"}},
+			},
+		},
+		"/admin/api/backend_apis.json": &threescaleapi.BackendApiList{
+			Backends: []threescaleapi.BackendApi{
+				{Element: threescaleapi.BackendApiItem{
+					ID: 10, Name: "Backend One", SystemName: "backend_one", PrivateEndpoint: "https://backend.example.com",
+				}},
+			},
+		},
+		"/admin/api/services/1/proxy.json": &threescaleapi.ProxyJSON{
+			Element: threescaleapi.ProxyItem{
+				Endpoint: "https://production.example.com", SandboxEndpoint: "https://staging.example.com",
+				CredentialsLocation: "headers", AuthAppID: "app_id", AuthAppKey: "app_key",
+				SecretToken: "secret", ErrorStatusNoMatch: 404, ErrorNoMatch: "No Mapping Rule matched",
+			},
+		},
+		"/admin/api/services/1/metrics.json": &threescaleapi.MetricJSONList{
+			Metrics: []threescaleapi.MetricJSON{
+				{Element: threescaleapi.MetricItem{ID: 1, Name: "Hits", SystemName: "hits", Unit: "hit"}},
+				{Element: threescaleapi.MetricItem{ID: 2, Name: "Method 01", SystemName: "method_01", Unit: "hit"}},
+			},
+		},
+		"/admin/api/services/1/metrics/1/methods.json": &threescaleapi.MethodList{
+			Methods: []threescaleapi.Method{
+				{Element: threescaleapi.MethodItem{ID: 2, Name: "Method 01", SystemName: "method_01", ParentID: 1}},
+			},
+		},
+		"/admin/api/services/1/proxy/mapping_rules.json": &threescaleapi.MappingRuleJSONList{
+			MappingRules: []threescaleapi.MappingRuleJSON{
+				{Element: threescaleapi.MappingRuleItem{ID: 21, MetricID: 1, Pattern: "/", HTTPMethod: "GET", Delta: 1, Position: 2}},
+				{Element: threescaleapi.MappingRuleItem{ID: 22, MetricID: 2, Pattern: "/pets$", HTTPMethod: "POST", Delta: 2, Position: 1, Last: true}},
+			},
+		},
+		"/admin/api/services/1/backend_usages.json": threescaleapi.BackendAPIUsageList{
+			{Element: threescaleapi.BackendAPIUsageItem{ID: 31, Path: "/v1", ProductID: 1, BackendAPIID: 10}},
+		},
+		"/admin/api/services/1/application_plans.json": &threescaleapi.ApplicationPlanJSONList{
+			Plans: []threescaleapi.ApplicationPlan{
+				{Element: threescaleapi.ApplicationPlanItem{
+					ID: 41, Name: "Basic", SystemName: "basic", State: "published", SetupFee: 1.5, TrialPeriodDays: 3,
+				}},
+			},
+		},
+		"/admin/api/application_plans/41/limits.json": &threescaleapi.ApplicationPlanLimitList{
+			Limits: []threescaleapi.ApplicationPlanLimit{
+				{Element: threescaleapi.ApplicationPlanLimitItem{ID: 51, Period: "month", Value: 100, MetricID: 101}},
+				{Element: threescaleapi.ApplicationPlanLimitItem{ID: 52, Period: "day", Value: 10, MetricID: 2}},
+			},
+		},
+		"/admin/api/application_plans/41/pricing_rules.json": &threescaleapi.ApplicationPlanPricingRuleList{
+			Rules: []threescaleapi.ApplicationPlanPricingRule{
+				{Element: threescaleapi.ApplicationPlanPricingRuleItem{ID: 61, MetricID: 1, CostPerUnit: "0.5", Min: 1, Max: 100}},
+			},
+		},
+		"/admin/api/services/1/proxy/policies.json": &threescaleapi.PoliciesConfigList{
+			Policies: []threescaleapi.PolicyConfig{
+				{Name: "apicast", Version: "builtin", Enabled: true, Configuration: map[string]interface{}{}},
+			},
+		},
+		"/admin/api/backend_apis/10/metrics.json": &threescaleapi.MetricJSONList{
+			Metrics: []threescaleapi.MetricJSON{
+				{Element: threescaleapi.MetricItem{ID: 101, Name: "Hits", SystemName: "hits.10", Unit: "hit"}},
+			},
+		},
+		"/admin/api/backend_apis/10/metrics/101/methods.json": &threescaleapi.MethodList{},
+		"/admin/api/backend_apis/10/mapping_rules.json": &threescaleapi.MappingRuleJSONList{
+			MappingRules: []threescaleapi.MappingRuleJSON{
+				{Element: threescaleapi.MappingRuleItem{ID: 111, MetricID: 101, Pattern: "/", HTTPMethod: "GET", Delta: 1, Position: 1}},
+			},
+		},
+	}
+
+	return func(req *http.Request) *http.Response {
+		respObject, found := responses[req.URL.Path]
+		assert(t, req.Method == http.MethodGet, "unexpected request %s %s", req.Method, req.URL.Path)
+		assert(t, found, "unexpected request %s %s", req.Method, req.URL.Path)
+
+		responseBodyBytes, err := json.Marshal(respObject)
+		ok(t, err)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBuffer(responseBodyBytes)),
+			Header:     make(http.Header),
+		}
+	}
+}
+
+func TestProductImporterImport(t *testing.T) {
+	client := threescaleapi.NewThreeScale(NewTestAdminPortal(t), "12345", NewTestClient(importRoundTripFunc(t)))
+	providerAccountRef := &corev1.LocalObjectReference{Name: "mysecret"}
+
+	importer, err := NewProductImporter(client, "myns", providerAccountRef, capabilitiesv1beta1.DeletionPolicyOrphan, logrtesting.NullLogger{})
+	ok(t, err)
+
+	products, backends, err := importer.Import([]string{"api_one"})
+	ok(t, err)
+	equals(t, 1, len(products))
+	equals(t, 1, len(backends))
+
+	product := products[0]
+	equals(t, "api-one", product.Name)
+	equals(t, "myns", product.Namespace)
+	equals(t, capabilitiesv1beta1.ProductKind, product.Kind)
+	equals(t, "API One", product.Spec.Name)
+	equals(t, "api_one", product.Spec.SystemName)
+	equals(t, providerAccountRef, product.Spec.ProviderAccountRef)
+	assert(t, capabilitiesv1beta1.IsDeletionPolicyOrphan(product), "orphan deletion policy expected")
+
+	// deployment
+	assert(t, product.Spec.Deployment != nil && product.Spec.Deployment.ApicastSelfManaged != nil, "self managed deployment expected")
+	equals(t, "self_managed", *product.Spec.DeploymentOption())
+	equals(t, "2", *product.Spec.AuthenticationMode())
+	equals(t, "https://production.example.com", *product.Spec.ProdPublicBaseURL())
+	equals(t, "app_id", *product.Spec.AuthAppID())
+	equals(t, "secret", *product.Spec.SecuritySecretToken())
+	assert(t, product.Spec.HostRewrite() == nil, "empty host rewrite not expected")
+	equals(t, int32(404), *product.Spec.GatewayResponse().ErrorStatusNoMatch)
+	assert(t, product.Spec.GatewayResponse().ErrorStatusAuthFailed == nil, "empty gateway response fields not expected")
+
+	// methods, metrics and mapping rules in position order
+	equals(t, map[string]capabilitiesv1beta1.MethodSpec{"method_01": {Name: "Method 01"}}, product.Spec.Methods)
+	equals(t, map[string]capabilitiesv1beta1.MetricSpec{"hits": {Name: "Hits", Unit: "hit"}}, product.Spec.Metrics)
+	equals(t, []capabilitiesv1beta1.MappingRuleSpec{
+		{HTTPMethod: "POST", Pattern: "/pets$", MetricMethodRef: "method_01", Increment: 2, Last: &[]bool{true}[0]},
+		{HTTPMethod: "GET", Pattern: "/", MetricMethodRef: "hits", Increment: 1},
+	}, product.Spec.MappingRules)
+
+	equals(t, map[string]capabilitiesv1beta1.BackendUsageSpec{"backend_one": {Path: "/v1"}}, product.Spec.BackendUsages)
+
+	// plans with local and backend metric references
+	plan, found := product.Spec.ApplicationPlans["basic"]
+	assert(t, found, "basic plan not found")
+	equals(t, "Basic", *plan.Name)
+	equals(t, "1.50", *plan.SetupFee)
+	equals(t, "0.00", *plan.CostMonth)
+	equals(t, 3, *plan.TrialPeriod)
+	assert(t, plan.IsPublished(), "plan should be published")
+	equals(t, []capabilitiesv1beta1.LimitSpec{
+		{Period: "month", Value: 100, MetricMethodRef: capabilitiesv1beta1.MetricMethodRefSpec{SystemName: "hits", BackendSystemName: &[]string{"backend_one"}[0]}},
+		{Period: "day", Value: 10, MetricMethodRef: capabilitiesv1beta1.MetricMethodRefSpec{SystemName: "method_01"}},
+	}, plan.Limits)
+	equals(t, []capabilitiesv1beta1.PricingRuleSpec{
+		{From: 1, To: 100, PricePerUnit: "0.5", MetricMethodRef: capabilitiesv1beta1.MetricMethodRefSpec{SystemName: "hits"}},
+	}, plan.PricingRules)
+
+	equals(t, 1, len(product.Spec.Policies))
+	equals(t, "apicast", product.Spec.Policies[0].Name)
+	equals(t, "{}", string(product.Spec.Policies[0].Configuration.Raw))
+
+	backend := backends[0]
+	equals(t, "backend-one", backend.Name)
+	equals(t, capabilitiesv1beta1.BackendKind, backend.Kind)
+	equals(t, "backend_one", backend.Spec.SystemName)
+	equals(t, "https://backend.example.com", backend.Spec.PrivateBaseURL)
+	equals(t, providerAccountRef, backend.Spec.ProviderAccountRef)
+	assert(t, capabilitiesv1beta1.IsDeletionPolicyOrphan(backend), "orphan deletion policy expected")
+	equals(t, map[string]capabilitiesv1beta1.MetricSpec{"hits": {Name: "Hits", Unit: "hit"}}, backend.Spec.Metrics)
+	equals(t, map[string]capabilitiesv1beta1.MethodSpec{}, backend.Spec.Methods)
+	equals(t, []capabilitiesv1beta1.MappingRuleSpec{
+		{HTTPMethod: "GET", Pattern: "/", MetricMethodRef: "hits", Increment: 1},
+	}, backend.Spec.MappingRules)
+}
+
+func TestProductImporterImportNotFound(t *testing.T) {
+	client := threescaleapi.NewThreeScale(NewTestAdminPortal(t), "12345", NewTestClient(importRoundTripFunc(t)))
+
+	importer, err := NewProductImporter(client, "", nil, "", logrtesting.NullLogger{})
+	ok(t, err)
+
+	_, _, err = importer.Import([]string{"unknown"})
+	assert(t, err != nil, "error expected for unknown product")
+}
+
+func TestImportedObjectName(t *testing.T) {
+	cases := []struct {
+		systemName string
+		expected   string
+	}{
+		{"api", "api"},
+		{"my_api", "my-api"},
+		{"_My.API_", "myapi"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.systemName, func(subT *testing.T) {
+			equals(subT, tc.expected, ImportedObjectName(tc.systemName))
+		})
+	}
+}
@@ -33,7 +33,7 @@ func ProductList(ns string, cl client.Client, providerAccountURLStr string, logg
 			continue
 		}
 
-		productProviderAccount, err := LookupProviderAccount(cl, ns, productList.Items[idx].Spec.ProviderAccountRef, logger)
+		productProviderAccount, err := LookupProviderAccount(cl, ns, productList.Items[idx].Spec.ProviderAccountRef, productList.Items[idx].Spec.ProviderAccountNamespace, productList.Items[idx].Spec.TenantRef, logger)
 		if err != nil {
 			return nil, fmt.Errorf("ProductList: %w", err)
 		}
@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"net/http"
 	"net/url"
+	"sync"
 
 	"github.com/3scale/3scale-operator/pkg/helper"
 
@@ -24,6 +25,26 @@ func PortaClient(providerAccount *ProviderAccount) (*threescaleapi.ThreeScaleCli
 	return PortaClientFromURLString(providerAccount.AdminURLStr, providerAccount.Token)
 }
 
+// PortaClientWithChangeRecorder instantiate porta_client.ThreeScaleClient from ProviderAccount object
+// together with the recorder of the changes made through the client
+func PortaClientWithChangeRecorder(providerAccount *ProviderAccount) (*threescaleapi.ThreeScaleClient, *ThreescaleChangeRecorder, error) {
+	adminURL, err := url.Parse(providerAccount.AdminURLStr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	httpClient := threescaleHTTPClient()
+	recorder := &ThreescaleChangeRecorder{Transport: httpClient.Transport}
+	httpClient.Transport = recorder
+
+	client, err := portaClientFromURL(adminURL, providerAccount.Token, httpClient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, recorder, nil
+}
+
 func PortaClientFromURLString(adminURLStr, token string) (*threescaleapi.ThreeScaleClient, error) {
 	adminURL, err := url.Parse(adminURLStr)
 	if err != nil {
@@ -34,11 +55,20 @@ func PortaClientFromURLString(adminURLStr, token string) (*threescaleapi.ThreeSc
 
 // PortaClientFromURL instantiates porta_client.ThreeScaleClient from admin url object
 func PortaClientFromURL(url *url.URL, token string) (*threescaleapi.ThreeScaleClient, error) {
+	return portaClientFromURL(url, token, threescaleHTTPClient())
+}
+
+func portaClientFromURL(url *url.URL, token string, httpClient *http.Client) (*threescaleapi.ThreeScaleClient, error) {
 	adminPortal, err := threescaleapi.NewAdminPortal(url.Scheme, url.Hostname(), helper.PortFromURL(url))
 	if err != nil {
 		return nil, err
 	}
 
+	return threescaleapi.NewThreeScale(adminPortal, token, httpClient), nil
+}
+
+// threescaleHTTPClient returns the http client used to call 3scale APIs
+func threescaleHTTPClient() *http.Client {
 	// TODO By default should not skip verification
 	// Activated by some env var or Spec param
 	var transport http.RoundTripper = &http.Transport{
@@ -50,5 +80,36 @@ func PortaClientFromURL(url *url.URL, token string) (*threescaleapi.ThreeScaleCl
 		transport = &helper.Transport{Transport: transport}
 	}
 
-	return threescaleapi.NewThreeScale(adminPortal, token, &http.Client{Transport: transport}), nil
+	return &http.Client{Transport: transport}
+}
+
+// ThreescaleChangeRecorder counts the successful requests modifying 3scale objects.
+// Read only requests are not counted.
+type ThreescaleChangeRecorder struct {
+	Transport http.RoundTripper
+
+	mu      sync.Mutex
+	changes int
+}
+
+func (r *ThreescaleChangeRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.Transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead && resp.StatusCode < http.StatusMultipleChoices {
+		r.mu.Lock()
+		r.changes++
+		r.mu.Unlock()
+	}
+
+	return resp, nil
+}
+
+// Changes returns the number of changes made so far
+func (r *ThreescaleChangeRecorder) Changes() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.changes
 }
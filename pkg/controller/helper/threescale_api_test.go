@@ -1,6 +1,8 @@
 package helper
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 )
@@ -32,3 +34,47 @@ func TestPortaClientFromURL(t *testing.T) {
 	_, err := PortaClientFromURL(url, "some token")
 	assert(t, err != nil, "error should not be nil")
 }
+
+func TestPortaClientWithChangeRecorderInvalidURL(t *testing.T) {
+	providerAccount := &ProviderAccount{AdminURLStr: ":foo", Token: "some token"}
+	_, _, err := PortaClientWithChangeRecorder(providerAccount)
+	assert(t, err != nil, "error should not be nil")
+}
+
+func TestThreescaleChangeRecorder(t *testing.T) {
+	responseCodes := map[string]int{
+		"/ok":    http.StatusOK,
+		"/error": http.StatusUnprocessableEntity,
+	}
+	recorder := &ThreescaleChangeRecorder{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: responseCodes[req.URL.Path]}, nil
+		}),
+	}
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/ok"},
+		{http.MethodHead, "/ok"},
+		{http.MethodPost, "/ok"},
+		{http.MethodPut, "/ok"},
+		{http.MethodDelete, "/ok"},
+		{http.MethodPut, "/error"},
+	}
+
+	for _, r := range requests {
+		req := httptest.NewRequest(r.method, r.path, nil)
+		_, err := recorder.RoundTrip(req)
+		ok(t, err)
+	}
+
+	equals(t, 3, recorder.Changes())
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,124 @@
+package helper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	threescaleapi "github.com/3scale/3scale-porta-go-client/client"
+)
+
+// ThreescaleRESTClient calls 3scale Account Management API endpoints
+// not implemented by the porta client
+type ThreescaleRESTClient struct {
+	adminURLStr string
+	token       string
+	httpClient  *http.Client
+}
+
+// ThreescaleAPIError represents an unexpected response from 3scale API
+type ThreescaleAPIError struct {
+	Code   int
+	Reason string
+}
+
+func (e *ThreescaleAPIError) Error() string {
+	return fmt.Sprintf("error calling 3scale system - reason: %s - code: %d", e.Reason, e.Code)
+}
+
+// IsThreescaleNotFound returns true when the error is a 3scale API not found response
+// from either porta client or ThreescaleRESTClient
+func IsThreescaleNotFound(err error) bool {
+	if threescaleapi.IsNotFound(err) {
+		return true
+	}
+
+	apiErr, ok := err.(*ThreescaleAPIError)
+	return ok && apiErr.Code == http.StatusNotFound
+}
+
+// RESTClient instantiates ThreescaleRESTClient from ProviderAccount object
+func RESTClient(providerAccount *ProviderAccount) (*ThreescaleRESTClient, error) {
+	return NewThreescaleRESTClient(providerAccount.AdminURLStr, providerAccount.Token, threescaleHTTPClient())
+}
+
+// NewThreescaleRESTClient instantiates ThreescaleRESTClient.
+// If http Client is nil, the default http client will be used
+func NewThreescaleRESTClient(adminURLStr, token string, httpClient *http.Client) (*ThreescaleRESTClient, error) {
+	adminURL, err := url.ParseRequestURI(adminURLStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &ThreescaleRESTClient{
+		adminURLStr: strings.TrimSuffix(adminURL.String(), "/"),
+		token:       token,
+		httpClient:  httpClient,
+	}, nil
+}
+
+// Request sends the params as form data to the given endpoint
+// and unmarshals the json response body into obj when not nil.
+// Responses with status other than expectedStatus are returned as ThreescaleAPIError
+func (c *ThreescaleRESTClient) Request(method, endpoint string, params threescaleapi.Params, expectedStatus int, obj interface{}) error {
+	values := url.Values{}
+	for k, v := range params {
+		values.Add(k, v)
+	}
+
+	return c.RequestValues(method, endpoint, values, expectedStatus, obj)
+}
+
+// RequestValues works like Request, but accepts multi-valued params, like array params
+func (c *ThreescaleRESTClient) RequestValues(method, endpoint string, values url.Values, expectedStatus int, obj interface{}) error {
+	var body io.Reader
+	reqURL := c.adminURLStr + endpoint
+	if method == http.MethodGet || method == http.MethodDelete {
+		if len(values) > 0 {
+			reqURL = reqURL + "?" + values.Encode()
+		}
+	} else {
+		body = strings.NewReader(values.Encode())
+	}
+
+	req, err := http.NewRequest(method, reqURL, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+c.token)))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != expectedStatus {
+		return &ThreescaleAPIError{Code: resp.StatusCode, Reason: string(respBody)}
+	}
+
+	if obj == nil {
+		return nil
+	}
+
+	return json.Unmarshal(respBody, obj)
+}
@@ -0,0 +1,160 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/ghodss/yaml"
+)
+
+const (
+	openAPIv2DefaultScheme = "https"
+	openAPIv2JSONMediaType = "application/json"
+	// OpenAPI 2.0 oauth2 client credentials flow
+	openAPIv2ApplicationFlow = "application"
+)
+
+// ConvertOpenAPIv2 converts the OpenAPI 2.0 (Swagger) document to OpenAPI 3.0.
+// Documents of other OpenAPI versions are returned unmodified.
+// Otherwise, the converted document is returned in JSON format together with warnings
+// about the OpenAPI 2.0 features that could not be converted.
+func ConvertOpenAPIv2(data []byte) ([]byte, []string, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	version := struct {
+		Swagger string `json:"swagger"`
+	}{}
+	if err := json.Unmarshal(jsonData, &version); err != nil {
+		return nil, nil, err
+	}
+
+	if version.Swagger == "" {
+		return data, nil, nil
+	}
+
+	if !strings.HasPrefix(version.Swagger, "2.") {
+		return nil, nil, fmt.Errorf("swagger version %s not supported", version.Swagger)
+	}
+
+	swagger := &openapi2.Swagger{}
+	if err := json.Unmarshal(jsonData, swagger); err != nil {
+		return nil, nil, err
+	}
+
+	warnings := openAPIv2Warnings(swagger)
+
+	if swagger.Host != "" && len(swagger.Schemes) == 0 {
+		swagger.Schemes = []string{openAPIv2DefaultScheme}
+		warnings = append(warnings, fmt.Sprintf("schemes not declared, %s scheme used for host %s", openAPIv2DefaultScheme, swagger.Host))
+	}
+
+	// The converter does not support the oauth2 application flow
+	applicationFlowSchemes := map[string]*openapi2.SecurityScheme{}
+	for name, securityScheme := range swagger.SecurityDefinitions {
+		if securityScheme != nil && securityScheme.Type == OpenAPISecuritySchemeTypeOAuth2 && securityScheme.Flow == openAPIv2ApplicationFlow {
+			applicationFlowSchemes[name] = securityScheme
+			delete(swagger.SecurityDefinitions, name)
+		}
+	}
+
+	openapiObj, err := openapi2conv.ToV3Swagger(swagger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(applicationFlowSchemes) > 0 && openapiObj.Components.SecuritySchemes == nil {
+		openapiObj.Components.SecuritySchemes = map[string]*openapi3.SecuritySchemeRef{}
+	}
+	for name, securityScheme := range applicationFlowSchemes {
+		openapiObj.Components.SecuritySchemes[name] = &openapi3.SecuritySchemeRef{
+			Value: &openapi3.SecurityScheme{
+				ExtensionProps: securityScheme.ExtensionProps,
+				Type:           OpenAPISecuritySchemeTypeOAuth2,
+				Description:    securityScheme.Description,
+				Flows: &openapi3.OAuthFlows{
+					ClientCredentials: &openapi3.OAuthFlow{
+						TokenURL: securityScheme.TokenURL,
+						Scopes:   securityScheme.Scopes,
+					},
+				},
+			},
+		}
+	}
+
+	convertedData, err := json.Marshal(openapiObj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return convertedData, warnings, nil
+}
+
+// openAPIv2Warnings returns the OpenAPI 2.0 document features that are lost in the conversion
+func openAPIv2Warnings(swagger *openapi2.Swagger) []string {
+	var warnings []string
+
+	// Unknown fields are parsed as extensions and removed by the converter
+	for _, fieldName := range openAPIv2NonCustomExtensions(swagger.ExtensionProps) {
+		warnings = append(warnings, fmt.Sprintf("swagger field %s not converted", fieldName))
+	}
+
+	if swagger.Host == "" {
+		warnings = append(warnings, "host not declared, servers not generated")
+	}
+
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		pathItem := swagger.Paths[path]
+		if pathItem == nil {
+			continue
+		}
+
+		methods := make([]string, 0)
+		for method := range pathItem.Operations() {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			operation := pathItem.GetOperation(method)
+			if !openAPIv2OnlyJSONMediaTypes(operation.Consumes) || !openAPIv2OnlyJSONMediaTypes(operation.Produces) {
+				warnings = append(warnings, fmt.Sprintf("operation %s %s: media types other than %s not converted", method, path, openAPIv2JSONMediaType))
+			}
+		}
+	}
+
+	return warnings
+}
+
+func openAPIv2NonCustomExtensions(props openapi3.ExtensionProps) []string {
+	names := []string{}
+	for name := range props.Extensions {
+		if !strings.HasPrefix(name, "x-") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func openAPIv2OnlyJSONMediaTypes(mediaTypes []string) bool {
+	for _, mediaType := range mediaTypes {
+		if mediaType != openAPIv2JSONMediaType {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package helper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const openAPIv2Document = `---
+swagger: "2.0"
+info:
+  title: "Petstore"
+  version: "1.0.0"
+host: petstore.example.com
+basePath: /v1
+consumes:
+- application/json
+x-3scale-plans:
+  basic:
+    name: Basic
+securityDefinitions:
+  petstore_auth:
+    type: oauth2
+    flow: application
+    tokenUrl: https://sso.example.com/token
+    scopes:
+      read: read pets
+security:
+- petstore_auth: []
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      produces:
+      - application/xml
+      responses:
+        "200":
+          description: ok
+          schema:
+            $ref: "#/definitions/Pets"
+definitions:
+  Pet:
+    type: object
+    properties:
+      name:
+        type: string
+  Pets:
+    type: array
+    items:
+      $ref: "#/definitions/Pet"
+`
+
+func TestConvertOpenAPIv2(t *testing.T) {
+	data, warnings, err := ConvertOpenAPIv2([]byte(openAPIv2Document))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData(data)
+	if err != nil {
+		t.Fatalf("unexpected error loading openapi: %v", err)
+	}
+
+	if err := ValidateOpenAPI(context.TODO(), openapiObj); err != nil {
+		t.Fatalf("unexpected error validating openapi: %v", err)
+	}
+
+	if len(openapiObj.Servers) != 1 || openapiObj.Servers[0].URL != "https://petstore.example.com/v1" {
+		t.Errorf("unexpected servers: %v", openapiObj.Servers)
+	}
+
+	securityScheme := openapiObj.Components.SecuritySchemes["petstore_auth"]
+	if securityScheme == nil || securityScheme.Value.Flows.ClientCredentials == nil ||
+		securityScheme.Value.Flows.ClientCredentials.TokenURL != "https://sso.example.com/token" {
+		t.Errorf("oauth2 application flow not converted: %v", securityScheme)
+	}
+
+	if _, ok := openapiObj.Extensions[OpenAPIExtension3scalePlans]; !ok {
+		t.Errorf("vendor extension not converted")
+	}
+
+	schema := openapiObj.Paths.Find("/pets").Get.Responses.Get(200).Value.Content.Get("application/json").Schema.Value
+	if schema.Type != "array" || schema.Items.Value.Properties["name"] == nil {
+		t.Errorf("definitions not converted: %v", schema)
+	}
+
+	expectedWarnings := []string{
+		"swagger field consumes not converted",
+		"operation GET /pets: media types other than application/json not converted",
+		"schemes not declared, https scheme used for host petstore.example.com",
+	}
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestConvertOpenAPIv2NotSwagger(t *testing.T) {
+	data := []byte(`{"openapi": "3.0.2", "info": {"title": "some title", "version": "1.0.0"}, "paths": {}}`)
+
+	converted, warnings, err := ConvertOpenAPIv2(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(converted) != string(data) || len(warnings) > 0 {
+		t.Errorf("OpenAPI 3.0 document modified: %s, %v", converted, warnings)
+	}
+}
+
+func TestConvertOpenAPIv2UnsupportedVersion(t *testing.T) {
+	_, _, err := ConvertOpenAPIv2([]byte(`{"swagger": "1.2"}`))
+	if err == nil {
+		t.Error("expected error")
+	}
+}
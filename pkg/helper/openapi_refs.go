@@ -0,0 +1,316 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// OpenAPIVirtualFS holds the files available to resolve the external references of OpenAPI documents.
+// Files are indexed by absolute path.
+type OpenAPIVirtualFS map[string][]byte
+
+// Add adds a file to the virtual filesystem in the given directory
+func (vfs OpenAPIVirtualFS) Add(dir, name string, data []byte) {
+	vfs[path.Join("/", dir, name)] = data
+}
+
+// ResolveOpenAPIExternalRefs inlines the external references of the OpenAPI document
+// located at documentPath using the files of the virtual filesystem.
+// References from external files to the root document are replaced with local references.
+// Circular references, i.e. recursive schemas, are added to the root document schema components
+// and replaced with local references.
+// References to remote locations are not supported.
+// When the document does not have external references, the document is returned unmodified.
+// Otherwise, the resolved document is returned in JSON format.
+func ResolveOpenAPIExternalRefs(data []byte, documentPath string, vfs OpenAPIVirtualFS) ([]byte, error) {
+	documentPath = path.Join("/", documentPath)
+
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	resolver := &openAPIRefResolver{
+		vfs:            vfs,
+		rootPath:       documentPath,
+		documents:      map[string]interface{}{documentPath: document},
+		resolvingSet:   map[string]bool{},
+		componentNames: map[string]string{},
+		components:     map[string]interface{}{},
+	}
+
+	resolvedDocument, err := resolver.resolve(document, documentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resolver.resolved {
+		return data, nil
+	}
+
+	if err := resolver.addComponents(resolvedDocument); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(resolvedDocument)
+}
+
+type openAPIRefResolver struct {
+	vfs      OpenAPIVirtualFS
+	rootPath string
+	// parsed documents indexed by path
+	documents map[string]interface{}
+	// references being resolved, used to detect circular references
+	resolvingSet map[string]bool
+	// schema component names of the circular references
+	componentNames map[string]string
+	// resolved schema components of the circular references indexed by name
+	components map[string]interface{}
+	// at least one external reference was inlined
+	resolved bool
+}
+
+func (r *openAPIRefResolver) resolve(node interface{}, documentPath string) (interface{}, error) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := value["$ref"].(string); ok {
+			// local references of the root document are kept
+			if strings.HasPrefix(ref, "#") && documentPath == r.rootPath {
+				return value, nil
+			}
+			return r.resolveRef(ref, documentPath)
+		}
+
+		for k, v := range value {
+			resolvedValue, err := r.resolve(v, documentPath)
+			if err != nil {
+				return nil, err
+			}
+			value[k] = resolvedValue
+		}
+	case []interface{}:
+		for idx, v := range value {
+			resolvedValue, err := r.resolve(v, documentPath)
+			if err != nil {
+				return nil, err
+			}
+			value[idx] = resolvedValue
+		}
+	}
+
+	return node, nil
+}
+
+func (r *openAPIRefResolver) resolveRef(ref, documentPath string) (interface{}, error) {
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse reference %q: %w", ref, err)
+	}
+
+	if refURL.Scheme != "" || refURL.Host != "" {
+		return nil, fmt.Errorf("reference %q: remote references are not supported", ref)
+	}
+
+	targetPath := documentPath
+	if refURL.Path != "" {
+		targetPath = path.Join(path.Dir(documentPath), refURL.Path)
+		if path.IsAbs(refURL.Path) {
+			targetPath = path.Clean(refURL.Path)
+		}
+	}
+
+	// references to the root document are kept as local references
+	if targetPath == r.rootPath {
+		r.resolved = true
+		return map[string]interface{}{"$ref": "#" + refURL.Fragment}, nil
+	}
+
+	refKey := targetPath + "#" + refURL.Fragment
+	if name, ok := r.componentNames[refKey]; ok {
+		return openAPISchemaRef(name), nil
+	}
+
+	if r.resolvingSet[refKey] {
+		// circular reference, the target is added as schema component once resolved
+		name := r.componentName(targetPath, refURL.Fragment)
+		r.componentNames[refKey] = name
+		return openAPISchemaRef(name), nil
+	}
+
+	document, err := r.document(targetPath)
+	if err != nil {
+		return nil, fmt.Errorf("reference %q: %w", ref, err)
+	}
+
+	target, err := openAPIJSONPointer(document, refURL.Fragment)
+	if err != nil {
+		return nil, fmt.Errorf("reference %q: %w", ref, err)
+	}
+
+	r.resolved = true
+	r.resolvingSet[refKey] = true
+	resolvedTarget, err := r.resolve(deepCopyJSONValue(target), targetPath)
+	delete(r.resolvingSet, refKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if name, ok := r.componentNames[refKey]; ok {
+		r.components[name] = resolvedTarget
+		return openAPISchemaRef(name), nil
+	}
+
+	return resolvedTarget, nil
+}
+
+// componentName returns a schema component name for the reference target
+// not used by the root document nor by other circular references
+func (r *openAPIRefResolver) componentName(targetPath, fragment string) string {
+	baseName := path.Base(fragment)
+	if fragment == "" || baseName == "/" {
+		baseName = strings.TrimSuffix(path.Base(targetPath), path.Ext(targetPath))
+	}
+	baseName = NonWordCharRegexp.ReplaceAllString(baseName, "")
+
+	rootSchemas := openAPIRootSchemas(r.documents[r.rootPath])
+	name := baseName
+	for idx := 2; ; idx++ {
+		_, inRoot := rootSchemas[name]
+		_, inComponents := r.components[name]
+		if !inRoot && !inComponents && !r.componentNameUsed(name) {
+			return name
+		}
+		name = fmt.Sprintf("%s%d", baseName, idx)
+	}
+}
+
+func (r *openAPIRefResolver) componentNameUsed(name string) bool {
+	for _, usedName := range r.componentNames {
+		if usedName == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addComponents adds the schema components of the circular references to the root document
+func (r *openAPIRefResolver) addComponents(document interface{}) error {
+	if len(r.components) == 0 {
+		return nil
+	}
+
+	root, ok := document.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected document type %T", document)
+	}
+
+	components, ok := root["components"].(map[string]interface{})
+	if !ok {
+		components = map[string]interface{}{}
+		root["components"] = components
+	}
+
+	schemas, ok := components["schemas"].(map[string]interface{})
+	if !ok {
+		schemas = map[string]interface{}{}
+		components["schemas"] = schemas
+	}
+
+	for name, schema := range r.components {
+		schemas[name] = schema
+	}
+
+	return nil
+}
+
+// openAPIRootSchemas returns the schema components of the document, if any
+func openAPIRootSchemas(document interface{}) map[string]interface{} {
+	root, _ := document.(map[string]interface{})
+	components, _ := root["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	return schemas
+}
+
+func openAPISchemaRef(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+func (r *openAPIRefResolver) document(documentPath string) (interface{}, error) {
+	if document, ok := r.documents[documentPath]; ok {
+		return document, nil
+	}
+
+	data, ok := r.vfs[documentPath]
+	if !ok {
+		return nil, fmt.Errorf("file %s not found", documentPath)
+	}
+
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("file %s: %w", documentPath, err)
+	}
+
+	r.documents[documentPath] = document
+	return document, nil
+}
+
+// openAPIJSONPointer returns the value referenced by the JSON pointer
+func openAPIJSONPointer(document interface{}, pointer string) (interface{}, error) {
+	if pointer == "" || pointer == "/" {
+		return document, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("expected fragment prefix '#/' in %q", pointer)
+	}
+
+	cursor := document
+	for _, part := range strings.Split(pointer[1:], "/") {
+		part = strings.Replace(strings.Replace(part, "~1", "/", -1), "~0", "~", -1)
+
+		switch value := cursor.(type) {
+		case map[string]interface{}:
+			next, ok := value[part]
+			if !ok {
+				return nil, fmt.Errorf("key %q not found", part)
+			}
+			cursor = next
+		case []interface{}:
+			idx, err := strconv.Atoi(part)
+			if err != nil || idx < 0 || idx >= len(value) {
+				return nil, fmt.Errorf("index %q out of bounds", part)
+			}
+			cursor = value[idx]
+		default:
+			return nil, fmt.Errorf("cannot resolve %q", part)
+		}
+	}
+
+	return cursor, nil
+}
+
+func deepCopyJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			out[key] = deepCopyJSONValue(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for idx, val := range v {
+			out[idx] = deepCopyJSONValue(val)
+		}
+		return out
+	}
+
+	return value
+}
@@ -0,0 +1,210 @@
+package helper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const externalRefsOpenAPI = `---
+openapi: "3.0.2"
+info:
+  title: "some title"
+  version: "1.0.0"
+paths:
+  /pets:
+    $ref: "paths/pets.yaml"
+components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+`
+
+const externalRefsPetsPath = `---
+get:
+  operationId: listPets
+  responses:
+    "200":
+      description: ok
+      content:
+        application/json:
+          schema:
+            $ref: "../schemas/pet.yaml#/Pets"
+    default:
+      description: error
+      content:
+        application/json:
+          schema:
+            $ref: "../openapi.yaml#/components/schemas/Error"
+`
+
+const externalRefsPetSchema = `---
+Pet:
+  type: object
+  properties:
+    name:
+      type: string
+Pets:
+  type: array
+  items:
+    $ref: "#/Pet"
+`
+
+func TestResolveOpenAPIExternalRefs(t *testing.T) {
+	vfs := OpenAPIVirtualFS{}
+	vfs.Add("/", "openapi.yaml", []byte(externalRefsOpenAPI))
+	vfs.Add("paths", "pets.yaml", []byte(externalRefsPetsPath))
+	vfs.Add("schemas", "pet.yaml", []byte(externalRefsPetSchema))
+
+	data, err := ResolveOpenAPIExternalRefs([]byte(externalRefsOpenAPI), "openapi.yaml", vfs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData(data)
+	if err != nil {
+		t.Fatalf("unexpected error loading openapi: %v", err)
+	}
+
+	if err := ValidateOpenAPI(context.TODO(), openapiObj); err != nil {
+		t.Fatalf("unexpected error validating openapi: %v", err)
+	}
+
+	operation := openapiObj.Paths.Find("/pets").Get
+	if operation == nil || operation.OperationID != "listPets" {
+		t.Fatalf("external path item not resolved: %v", operation)
+	}
+
+	schema := operation.Responses.Get(200).Value.Content.Get("application/json").Schema.Value
+	if schema.Type != "array" || schema.Items.Value.Properties["name"] == nil {
+		t.Errorf("external schema not resolved: %v", schema)
+	}
+
+	errSchema := operation.Responses.Default().Value.Content.Get("application/json").Schema.Value
+	if errSchema.Properties["message"] == nil {
+		t.Errorf("root document reference not resolved: %v", errSchema)
+	}
+}
+
+const recursiveRefsOpenAPI = `---
+openapi: "3.0.2"
+info:
+  title: "some title"
+  version: "1.0.0"
+paths:
+  /trees:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: "schemas/tree.yaml#/Node"
+components:
+  schemas:
+    Node:
+      type: string
+`
+
+const recursiveRefsTreeSchema = `---
+Node:
+  type: object
+  properties:
+    owner:
+      $ref: "person.yaml#/Person"
+    children:
+      type: array
+      items:
+        $ref: "#/Node"
+`
+
+const recursiveRefsPersonSchema = `---
+Person:
+  type: object
+  properties:
+    name:
+      type: string
+    tree:
+      $ref: "tree.yaml#/Node"
+`
+
+func TestResolveOpenAPIExternalRefsRecursive(t *testing.T) {
+	vfs := OpenAPIVirtualFS{}
+	vfs.Add("schemas", "tree.yaml", []byte(recursiveRefsTreeSchema))
+	vfs.Add("schemas", "person.yaml", []byte(recursiveRefsPersonSchema))
+
+	data, err := ResolveOpenAPIExternalRefs([]byte(recursiveRefsOpenAPI), "openapi.yaml", vfs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData(data)
+	if err != nil {
+		t.Fatalf("unexpected error loading openapi: %v", err)
+	}
+
+	if err := ValidateOpenAPI(context.TODO(), openapiObj); err != nil {
+		t.Fatalf("unexpected error validating openapi: %v", err)
+	}
+
+	// the root document schema name is not reused
+	if openapiObj.Components.Schemas["Node"].Value.Type != "string" {
+		t.Errorf("root document schema overwritten: %v", openapiObj.Components.Schemas["Node"].Value)
+	}
+
+	schemaRef := openapiObj.Paths.Find("/trees").Get.Responses.Get(200).Value.Content.Get("application/json").Schema
+	if schemaRef.Ref != "#/components/schemas/Node2" {
+		t.Fatalf("recursive schema not added to components: %s", schemaRef.Ref)
+	}
+
+	schema := schemaRef.Value
+	if schema.Properties["children"].Value.Items.Value != schema {
+		t.Errorf("recursive schema not resolved: %v", schema.Properties["children"].Value.Items)
+	}
+
+	// mutual recursion across files
+	person := schema.Properties["owner"].Value
+	if person.Properties["name"] == nil || person.Properties["tree"].Value != schema {
+		t.Errorf("mutually recursive schema not resolved: %v", person)
+	}
+}
+
+func TestResolveOpenAPIExternalRefsNoRefs(t *testing.T) {
+	data := []byte(`{"openapi": "3.0.2", "paths": {"/pets": {"$ref": "#/x-pets"}}}`)
+
+	resolved, err := ResolveOpenAPIExternalRefs(data, "openapi.json", OpenAPIVirtualFS{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(resolved) != string(data) {
+		t.Errorf("document without external references modified: %s", resolved)
+	}
+}
+
+func TestResolveOpenAPIExternalRefsErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		document string
+		vfs      OpenAPIVirtualFS
+	}{
+		{"file not found", `{"paths": {"/pets": {"$ref": "pets.yaml"}}}`, OpenAPIVirtualFS{}},
+		{"remote reference", `{"paths": {"/pets": {"$ref": "https://example.com/pets.yaml"}}}`, OpenAPIVirtualFS{}},
+		{"fragment not found", `{"paths": {"/pets": {"$ref": "pets.yaml#/unknown"}}}`,
+			OpenAPIVirtualFS{"/pets.yaml": []byte(`{"get": {}}`)}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			_, err := ResolveOpenAPIExternalRefs([]byte(tc.document), "openapi.json", tc.vfs)
+			if err == nil {
+				subT.Error("expected error")
+			}
+		})
+	}
+}
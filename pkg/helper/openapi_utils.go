@@ -2,15 +2,47 @@ package helper
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
+const (
+	// OpenAPISecuritySchemeTypeAPIKey is the OpenAPI API key security scheme type
+	OpenAPISecuritySchemeTypeAPIKey = "apiKey"
+
+	// OpenAPISecuritySchemeTypeOAuth2 is the OpenAPI OAuth2 security scheme type
+	OpenAPISecuritySchemeTypeOAuth2 = "oauth2"
+
+	// OpenAPISecuritySchemeTypeOpenIDConnect is the OpenAPI OpenID Connect security scheme type
+	OpenAPISecuritySchemeTypeOpenIDConnect = "openIdConnect"
+
+	// OpenAPIExtension3scalePlans is the document level vendor extension with the application plans
+	OpenAPIExtension3scalePlans = "x-3scale-plans"
+
+	// OpenAPIExtension3scaleLimits is the operation level vendor extension with the application plan limits
+	OpenAPIExtension3scaleLimits = "x-3scale-limits"
+
+	// OpenAPIExtension3scalePricingRules is the operation level vendor extension with the application plan pricing rules
+	OpenAPIExtension3scalePricingRules = "x-3scale-pricing-rules"
+
+	// OpenAPIExtension3scalePolicies is the document level vendor extension with the product policy chain
+	OpenAPIExtension3scalePolicies = "x-3scale-policies"
+
+	// OpenAPIExtension3scaleBackends is the document level vendor extension with the backends of the tags
+	OpenAPIExtension3scaleBackends = "x-3scale-backends"
+
+	openIDConnectURLField = "openIdConnectUrl"
+)
+
 var (
 	// NonWordCharRegexp not word characters (== [^0-9A-Za-z_])
 	NonWordCharRegexp = regexp.MustCompile(`\W`)
@@ -102,32 +134,282 @@ func RenderOpenAPIServerURL(server *openapi3.Server) (*url.URL, error) {
 type ExtendedSecurityRequirement struct {
 	*openapi3.SecuritySchemeRef
 
+	// Name of the security scheme
+	Name string
+
 	Scopes []string
+
+	// Names of all the security schemes of the security requirement object, required together.
+	// 3scale authentication supports security requirements with a single security scheme
+	Names []string
+}
+
+// IsCombined returns true when the security requirement object requires more than one security scheme
+func (s *ExtendedSecurityRequirement) IsCombined() bool {
+	return len(s.Names) > 1
 }
 
-func NewExtendedSecurityRequirement(secSchemeRef *openapi3.SecuritySchemeRef, scopes []string) *ExtendedSecurityRequirement {
+func NewExtendedSecurityRequirement(name string, secSchemeRef *openapi3.SecuritySchemeRef, scopes []string) *ExtendedSecurityRequirement {
 	return &ExtendedSecurityRequirement{
 		SecuritySchemeRef: secSchemeRef,
+		Name:              name,
 		Scopes:            scopes,
+		Names:             []string{name},
 	}
 }
 
+// OpenAPIGlobalSecurityRequirements returns one item per global security requirement object, the alternatives.
+// The security schemes of a security requirement object are all required together.
+// For those, the item refers to the first security scheme by name and lists all of them.
+// Empty security requirement objects, i.e. optional security, are skipped.
 func OpenAPIGlobalSecurityRequirements(openapiObj *openapi3.Swagger) []*ExtendedSecurityRequirement {
 	extendedSecRequirements := make([]*ExtendedSecurityRequirement, 0)
 
 	for _, secReq := range openapiObj.Security {
-		for secReqItemName, scopes := range secReq {
-			secScheme, ok := openapiObj.Components.SecuritySchemes[secReqItemName]
-			if !ok {
-				// should never happen. OpenAPI validation should detect this issue
+		// sort security scheme names for deterministic ordering
+		names := securityRequirementNames(secReq)
+		if len(names) == 0 {
+			continue
+		}
+
+		secScheme, ok := openapiObj.Components.SecuritySchemes[names[0]]
+		if !ok {
+			// should never happen. OpenAPI validation should detect this issue
+			continue
+		}
+
+		extendedSecRequirement := NewExtendedSecurityRequirement(names[0], secScheme, secReq[names[0]])
+		extendedSecRequirement.Names = names
+		extendedSecRequirements = append(extendedSecRequirements, extendedSecRequirement)
+	}
+
+	return extendedSecRequirements
+}
+
+// IsOpenAPISecuritySchemeTypeSupported returns true when the security scheme type can be mapped to 3scale authentication
+func IsOpenAPISecuritySchemeTypeSupported(secSchemeType string) bool {
+	switch secSchemeType {
+	case OpenAPISecuritySchemeTypeAPIKey, OpenAPISecuritySchemeTypeOAuth2, OpenAPISecuritySchemeTypeOpenIDConnect:
+		return true
+	}
+
+	return false
+}
+
+// SelectOpenAPISecurityRequirement returns the security requirement used for the 3scale authentication.
+// When secSchemeName is set, the security requirement with only the security scheme with that name is selected.
+// Otherwise, the first security requirement with a single security scheme of supported type is selected.
+// Security requirements combining several security schemes are not supported.
+// Returns nil when there are no security requirements.
+func SelectOpenAPISecurityRequirement(secRequirements []*ExtendedSecurityRequirement, secSchemeName *string) (*ExtendedSecurityRequirement, error) {
+	if secSchemeName != nil {
+		var combinedErr error
+		for _, secReq := range secRequirements {
+			if !ArrayContains(secReq.Names, *secSchemeName) {
 				continue
 			}
 
-			extendedSecRequirements = append(extendedSecRequirements, NewExtendedSecurityRequirement(secScheme, scopes))
+			if secReq.IsCombined() {
+				combinedErr = fmt.Errorf("Unsupported security requirement combining security schemes: %s", strings.Join(secReq.Names, ","))
+				continue
+			}
+
+			if !IsOpenAPISecuritySchemeTypeSupported(secReq.Value.Type) {
+				return nil, fmt.Errorf("Unexpected security schema type: %s", secReq.Value.Type)
+			}
+
+			return secReq, nil
+		}
+
+		if combinedErr != nil {
+			return nil, combinedErr
 		}
+
+		return nil, fmt.Errorf("Security scheme %s not found in global security requirements", *secSchemeName)
 	}
 
-	return extendedSecRequirements
+	if len(secRequirements) == 0 {
+		return nil, nil
+	}
+
+	unsupported := make([]string, 0, len(secRequirements))
+	for _, secReq := range secRequirements {
+		if secReq.IsCombined() {
+			unsupported = append(unsupported, strings.Join(secReq.Names, "+"))
+			continue
+		}
+
+		if IsOpenAPISecuritySchemeTypeSupported(secReq.Value.Type) {
+			return secReq, nil
+		}
+		unsupported = append(unsupported, secReq.Value.Type)
+	}
+
+	return nil, fmt.Errorf("Unsupported security requirements: %s", strings.Join(unsupported, ","))
+}
+
+// OpenAPISecurityWarnings returns the security requirements of the OpenAPI document
+// that cannot be mapped to the 3scale authentication of the selected security requirement.
+// Operation level security requirements are not supported by 3scale,
+// the product authentication applies to all the operations.
+func OpenAPISecurityWarnings(openapiObj *openapi3.Swagger, selected *ExtendedSecurityRequirement) []string {
+	var warnings []string
+
+	selectedName := ""
+	if selected != nil {
+		selectedName = selected.Name
+	}
+
+	for _, secReq := range OpenAPIGlobalSecurityRequirements(openapiObj) {
+		if secReq.IsCombined() {
+			warnings = append(warnings, fmt.Sprintf("Global security requirement combining security schemes %s ignored", strings.Join(secReq.Names, ",")))
+		} else if secReq.Name != selectedName {
+			warnings = append(warnings, fmt.Sprintf("Global security scheme %s ignored", secReq.Name))
+		}
+	}
+
+	paths := make([]string, 0, len(openapiObj.Paths))
+	for path := range openapiObj.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		operations := openapiObj.Paths[path].Operations()
+		opVerbs := make([]string, 0, len(operations))
+		for opVerb := range operations {
+			opVerbs = append(opVerbs, opVerb)
+		}
+		sort.Strings(opVerbs)
+
+		for _, opVerb := range opVerbs {
+			operation := operations[opVerb]
+			if operation.Security == nil {
+				// global security requirements apply
+				continue
+			}
+
+			opName := MethodNameFromOpenAPIOperation(path, opVerb, operation)
+
+			if len(*operation.Security) == 0 {
+				warnings = append(warnings, fmt.Sprintf("Operation %s removes security requirements, product authentication applies", opName))
+				continue
+			}
+
+			if !securityRequirementsInclude(*operation.Security, selectedName) {
+				warnings = append(warnings, fmt.Sprintf("Operation %s security requirements ignored, product authentication applies", opName))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// securityRequirementsInclude returns true when one of the security requirements
+// requires only the security scheme with the given name
+func securityRequirementsInclude(secRequirements openapi3.SecurityRequirements, secSchemeName string) bool {
+	for _, secReq := range secRequirements {
+		if _, ok := secReq[secSchemeName]; ok && len(secReq) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func securityRequirementNames(secReq openapi3.SecurityRequirement) []string {
+	names := make([]string, 0, len(secReq))
+	for name := range secReq {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenAPIDigest returns the digest of the OpenAPI document content
+func OpenAPIDigest(data []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+}
+
+// ValidateOpenAPI validates the OpenAPI document.
+// The openapi library does not support openIdConnect security schemes,
+// hence, those are validated apart.
+func ValidateOpenAPI(ctx context.Context, obj *openapi3.Swagger) error {
+	openIDConnectSchemes := map[string]*openapi3.SecuritySchemeRef{}
+	otherSchemes := map[string]*openapi3.SecuritySchemeRef{}
+	for name, secScheme := range obj.Components.SecuritySchemes {
+		if secScheme != nil && secScheme.Value != nil && secScheme.Value.Type == OpenAPISecuritySchemeTypeOpenIDConnect {
+			openIDConnectSchemes[name] = secScheme
+		} else {
+			otherSchemes[name] = secScheme
+		}
+	}
+
+	if len(openIDConnectSchemes) == 0 {
+		return obj.Validate(ctx)
+	}
+
+	// shallow copy to avoid changing the original document
+	objCopy := *obj
+	objCopy.Components.SecuritySchemes = otherSchemes
+	err := objCopy.Validate(ctx)
+	if err != nil {
+		return err
+	}
+
+	for name, secScheme := range openIDConnectSchemes {
+		if OpenIDConnectURLFromSecurityScheme(secScheme.Value) == "" {
+			return fmt.Errorf("invalid components: Security scheme '%s' of type '%s' should have '%s'",
+				name, OpenAPISecuritySchemeTypeOpenIDConnect, openIDConnectURLField)
+		}
+	}
+
+	return nil
+}
+
+// OpenIDConnectURLFromSecurityScheme returns the openIdConnectUrl field of the security scheme.
+// The openapi library does not parse that field, it is read from the extension properties.
+func OpenIDConnectURLFromSecurityScheme(secScheme *openapi3.SecurityScheme) string {
+	if secScheme == nil {
+		return ""
+	}
+
+	rawValue, ok := secScheme.Extensions[openIDConnectURLField]
+	if !ok {
+		return ""
+	}
+
+	rawMessage, ok := rawValue.(json.RawMessage)
+	if !ok {
+		return ""
+	}
+
+	var openIDConnectURL string
+	if err := json.Unmarshal(rawMessage, &openIDConnectURL); err != nil {
+		return ""
+	}
+
+	return openIDConnectURL
+}
+
+// DecodeOpenAPIExtension decodes the value of the vendor extension into v.
+// Returns false when the extension is not present.
+func DecodeOpenAPIExtension(props openapi3.ExtensionProps, name string, v interface{}) (bool, error) {
+	rawValue, ok := props.Extensions[name]
+	if !ok {
+		return false, nil
+	}
+
+	rawMessage, ok := rawValue.(json.RawMessage)
+	if !ok {
+		return false, fmt.Errorf("%s: unexpected value type %T", name, rawValue)
+	}
+
+	if err := json.Unmarshal(rawMessage, v); err != nil {
+		return false, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return true, nil
 }
 
 func MethodNameFromOpenAPIOperation(path, opVerb string, op *openapi3.Operation) string {
@@ -0,0 +1,291 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/go-cmp/cmp"
+)
+
+const securitySchemeOpenAPITemplate = `{
+  "openapi": "3.0.2",
+  "info": {"title": "some title", "version": "1.0.0"},
+  "paths": {},
+  "security": [{"oidc": []}],
+  "components": {
+    "securitySchemes": {
+      "oidc": %s
+    }
+  }
+}`
+
+func TestValidateOpenAPIOpenIDConnect(t *testing.T) {
+	cases := []struct {
+		name        string
+		secScheme   string
+		expectedURL string
+		expectedErr bool
+	}{
+		{"valid", `{"type": "openIdConnect", "openIdConnectUrl": "https://sso.example.com/.well-known/openid-configuration"}`,
+			"https://sso.example.com/.well-known/openid-configuration", false},
+		{"missing url", `{"type": "openIdConnect"}`, "", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(fmt.Sprintf(securitySchemeOpenAPITemplate, tc.secScheme)))
+			if err != nil {
+				subT.Fatalf("unexpected error loading openapi: %v", err)
+			}
+
+			err = ValidateOpenAPI(context.TODO(), openapiObj)
+			if tc.expectedErr != (err != nil) {
+				subT.Errorf("expected error %t, got %v", tc.expectedErr, err)
+			}
+
+			// the original document is not modified
+			if _, ok := openapiObj.Components.SecuritySchemes["oidc"]; !ok {
+				subT.Errorf("security scheme removed from the document")
+			}
+
+			url := OpenIDConnectURLFromSecurityScheme(openapiObj.Components.SecuritySchemes["oidc"].Value)
+			if url != tc.expectedURL {
+				subT.Errorf("expected url %s, got %s", tc.expectedURL, url)
+			}
+		})
+	}
+}
+
+func TestValidateOpenAPIOAuth2(t *testing.T) {
+	secScheme := `{
+  "type": "oauth2",
+  "flows": {
+    "clientCredentials": {"tokenUrl": "https://sso.example.com/token", "scopes": {}}
+  }
+}`
+
+	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(fmt.Sprintf(securitySchemeOpenAPITemplate, secScheme)))
+	if err != nil {
+		t.Fatalf("unexpected error loading openapi: %v", err)
+	}
+
+	if err := ValidateOpenAPI(context.TODO(), openapiObj); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+const multipleSecurityOpenAPI = `{
+  "openapi": "3.0.2",
+  "info": {"title": "some title", "version": "1.0.0"},
+  "paths": {
+    "/pets": {
+      "get": {"operationId": "listPets", "responses": {"200": {"description": "ok"}}},
+      "post": {"operationId": "createPet", "security": [{"basic": []}], "responses": {"200": {"description": "ok"}}}
+    },
+    "/health": {
+      "get": {"operationId": "health", "security": [], "responses": {"200": {"description": "ok"}}}
+    },
+    "/pets/{id}": {
+      "get": {"operationId": "showPet", "security": [{"api_key": []}],
+        "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "responses": {"200": {"description": "ok"}}}
+    }
+  },
+  "security": [{"basic": []}, {"api_key": []}, {"oidc": []}],
+  "components": {
+    "securitySchemes": {
+      "basic": {"type": "http", "scheme": "basic"},
+      "api_key": {"type": "apiKey", "name": "api_key", "in": "header"},
+      "oidc": {"type": "openIdConnect", "openIdConnectUrl": "https://sso.example.com/.well-known/openid-configuration"}
+    }
+  }
+}`
+
+func TestSelectOpenAPISecurityRequirement(t *testing.T) {
+	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(multipleSecurityOpenAPI))
+	if err != nil {
+		t.Fatalf("unexpected error loading openapi: %v", err)
+	}
+
+	secRequirements := OpenAPIGlobalSecurityRequirements(openapiObj)
+	oidcName := "oidc"
+	basicName := "basic"
+	unknownName := "unknown"
+
+	cases := []struct {
+		name          string
+		secSchemeName *string
+		expectedName  string
+		expectedErr   bool
+	}{
+		{"first supported", nil, "api_key", false},
+		{"selected", &oidcName, "oidc", false},
+		{"selected not supported", &basicName, "", true},
+		{"selected not found", &unknownName, "", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			secReq, err := SelectOpenAPISecurityRequirement(secRequirements, tc.secSchemeName)
+			if tc.expectedErr != (err != nil) {
+				subT.Fatalf("expected error %t, got %v", tc.expectedErr, err)
+			}
+
+			if secReq != nil && secReq.Name != tc.expectedName {
+				subT.Errorf("expected %s, got %s", tc.expectedName, secReq.Name)
+			}
+		})
+	}
+
+	// unsupported types only
+	_, err = SelectOpenAPISecurityRequirement(secRequirements[:1], nil)
+	if err == nil {
+		t.Error("expected error for unsupported security scheme types")
+	}
+
+	// no security requirements
+	secReq, err := SelectOpenAPISecurityRequirement(nil, nil)
+	if secReq != nil || err != nil {
+		t.Errorf("expected nil security requirement and error, got %v, %v", secReq, err)
+	}
+}
+
+const combinedSecurityOpenAPI = `{
+  "openapi": "3.0.2",
+  "info": {"title": "some title", "version": "1.0.0"},
+  "paths": {
+    "/pets": {
+      "get": {"operationId": "listPets", "security": [{"api_key": [], "app_id": []}], "responses": {"200": {"description": "ok"}}}
+    }
+  },
+  "security": [{"api_key": [], "app_id": []}, {"oidc": []}],
+  "components": {
+    "securitySchemes": {
+      "api_key": {"type": "apiKey", "name": "api_key", "in": "header"},
+      "app_id": {"type": "apiKey", "name": "app_id", "in": "header"},
+      "oidc": {"type": "openIdConnect", "openIdConnectUrl": "https://sso.example.com/.well-known/openid-configuration"}
+    }
+  }
+}`
+
+func TestSelectOpenAPISecurityRequirementCombined(t *testing.T) {
+	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(combinedSecurityOpenAPI))
+	if err != nil {
+		t.Fatalf("unexpected error loading openapi: %v", err)
+	}
+
+	secRequirements := OpenAPIGlobalSecurityRequirements(openapiObj)
+	if len(secRequirements) != 2 {
+		t.Fatalf("expected one item per security requirement object, got %d", len(secRequirements))
+	}
+	if !reflect.DeepEqual([]string{"api_key", "app_id"}, secRequirements[0].Names) || !secRequirements[0].IsCombined() {
+		t.Fatalf("unexpected combined security requirement: %v", secRequirements[0].Names)
+	}
+
+	// combined security requirements are skipped
+	secReq, err := SelectOpenAPISecurityRequirement(secRequirements, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secReq.Name != "oidc" {
+		t.Fatalf("expected oidc, got %s", secReq.Name)
+	}
+
+	// selected security scheme only required together with other security schemes
+	apiKeyName := "api_key"
+	_, err = SelectOpenAPISecurityRequirement(secRequirements, &apiKeyName)
+	if err == nil {
+		t.Error("expected error for combined security requirement")
+	}
+
+	// combined security requirements only
+	_, err = SelectOpenAPISecurityRequirement(secRequirements[:1], nil)
+	if err == nil {
+		t.Error("expected error for combined security requirements")
+	}
+
+	expected := []string{
+		"Global security requirement combining security schemes api_key,app_id ignored",
+		"Operation listPets security requirements ignored, product authentication applies",
+	}
+
+	warnings := OpenAPISecurityWarnings(openapiObj, secReq)
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("unexpected warnings: %s", cmp.Diff(expected, warnings))
+	}
+}
+
+func TestOpenAPISecurityWarnings(t *testing.T) {
+	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(multipleSecurityOpenAPI))
+	if err != nil {
+		t.Fatalf("unexpected error loading openapi: %v", err)
+	}
+
+	secReq, err := SelectOpenAPISecurityRequirement(OpenAPIGlobalSecurityRequirements(openapiObj), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"Global security scheme basic ignored",
+		"Global security scheme oidc ignored",
+		"Operation health removes security requirements, product authentication applies",
+		"Operation createPet security requirements ignored, product authentication applies",
+	}
+
+	warnings := OpenAPISecurityWarnings(openapiObj, secReq)
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("unexpected warnings: %s", cmp.Diff(expected, warnings))
+	}
+}
+
+func TestOpenAPIDigest(t *testing.T) {
+	digest := OpenAPIDigest([]byte(multipleSecurityOpenAPI))
+	if digest != OpenAPIDigest([]byte(multipleSecurityOpenAPI)) {
+		t.Error("digest of the same content differs")
+	}
+
+	if digest == OpenAPIDigest([]byte(fmt.Sprintf(securitySchemeOpenAPITemplate, "{}"))) {
+		t.Error("digest of different content matches")
+	}
+
+	expected := "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	if got := OpenAPIDigest([]byte{}); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestDecodeOpenAPIExtension(t *testing.T) {
+	openapiObj, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(`{
+  "openapi": "3.0.2",
+  "info": {"title": "some title", "version": "1.0.0"},
+  "paths": {},
+  "x-3scale-plans": {"basic": {"name": "Basic"}},
+  "x-3scale-limits": "invalid"
+}`))
+	if err != nil {
+		t.Fatalf("unexpected error loading openapi: %v", err)
+	}
+
+	plans := map[string]map[string]string{}
+	found, err := DecodeOpenAPIExtension(openapiObj.ExtensionProps, OpenAPIExtension3scalePlans, &plans)
+	if err != nil || !found {
+		t.Fatalf("expected extension found, got %t, %v", found, err)
+	}
+	if plans["basic"]["name"] != "Basic" {
+		t.Errorf("unexpected extension value: %v", plans)
+	}
+
+	found, err = DecodeOpenAPIExtension(openapiObj.ExtensionProps, OpenAPIExtension3scalePricingRules, &plans)
+	if err != nil || found {
+		t.Errorf("expected extension not found, got %t, %v", found, err)
+	}
+
+	limits := map[string][]int{}
+	if _, err := DecodeOpenAPIExtension(openapiObj.ExtensionProps, OpenAPIExtension3scaleLimits, &limits); err == nil {
+		t.Error("expected error decoding invalid extension")
+	}
+}
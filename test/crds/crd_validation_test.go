@@ -9,7 +9,6 @@ import (
 	"testing"
 
 	apps "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
-	capabilitiesv1alpha1 "github.com/3scale/3scale-operator/apis/capabilities/v1alpha1"
 	capabilitiesv1beta1 "github.com/3scale/3scale-operator/apis/capabilities/v1beta1"
 	"github.com/RHsyseng/operator-utils/pkg/validation"
 	"github.com/ghodss/yaml"
@@ -28,6 +27,9 @@ const (
 	systemPostgreSQLPVCResourceRequestsPath  = "/spec/system/database/postgresql/persistentVolumeClaim/resources/requests"
 	productPoliciesConfigurationPath         = "/spec/policies/configuration"
 	policyConfigurationPath                  = "/spec/schema/configuration"
+	tenantAccessTokenRotationIntervalPath    = "/spec/accessTokenRotationInterval"
+	tenantAccessTokenRotationTimePath        = "/status/accessTokenRotationTime"
+	openapiRefreshIntervalPath               = "/spec/refreshInterval"
 )
 
 type testCRInfo struct {
@@ -56,8 +58,8 @@ func TestSampleCustomResources(t *testing.T) {
 			apiVersion: apps.GroupVersion.Version,
 		},
 		"capabilities.3scale.net_tenants.yaml": testCRInfo{
-			crPrefix:   "capabilities_v1alpha1_tenant",
-			apiVersion: capabilitiesv1alpha1.GroupVersion.Version,
+			crPrefix:   "capabilities_v1beta1_tenant",
+			apiVersion: capabilitiesv1beta1.GroupVersion.Version,
 		},
 		"capabilities.3scale.net_backends.yaml": testCRInfo{
 			crPrefix:   "capabilities_v1beta1_backend",
@@ -87,6 +89,14 @@ func TestSampleCustomResources(t *testing.T) {
 			crPrefix:   "capabilities_v1beta1_developeruser",
 			apiVersion: capabilitiesv1beta1.GroupVersion.Version,
 		},
+		"capabilities.3scale.net_applications.yaml": testCRInfo{
+			crPrefix:   "capabilities_v1beta1_application",
+			apiVersion: capabilitiesv1beta1.GroupVersion.Version,
+		},
+		"capabilities.3scale.net_proxyconfigpromotes.yaml": testCRInfo{
+			crPrefix:   "capabilities_v1beta1_proxyconfigpromote",
+			apiVersion: capabilitiesv1beta1.GroupVersion.Version,
+		},
 	}
 
 	for crd, elem := range crdCrMap {
@@ -137,8 +147,8 @@ func TestCompleteCRD(t *testing.T) {
 			apiVersion: apps.GroupVersion.Version,
 		},
 		"capabilities.3scale.net_tenants.yaml": testCRDInfo{
-			obj:        &capabilitiesv1alpha1.Tenant{},
-			apiVersion: capabilitiesv1alpha1.GroupVersion.Version,
+			obj:        &capabilitiesv1beta1.Tenant{},
+			apiVersion: capabilitiesv1beta1.GroupVersion.Version,
 		},
 		"capabilities.3scale.net_backends.yaml": testCRDInfo{
 			obj:        &capabilitiesv1beta1.Backend{},
@@ -168,6 +178,14 @@ func TestCompleteCRD(t *testing.T) {
 			obj:        &capabilitiesv1beta1.DeveloperUser{},
 			apiVersion: capabilitiesv1beta1.GroupVersion.Version,
 		},
+		"capabilities.3scale.net_applications.yaml": testCRDInfo{
+			obj:        &capabilitiesv1beta1.Application{},
+			apiVersion: capabilitiesv1beta1.GroupVersion.Version,
+		},
+		"capabilities.3scale.net_proxyconfigpromotes.yaml": testCRDInfo{
+			obj:        &capabilitiesv1beta1.ProxyConfigPromote{},
+			apiVersion: capabilitiesv1beta1.GroupVersion.Version,
+		},
 	}
 
 	pathOmissions := []string{
@@ -180,6 +198,9 @@ func TestCompleteCRD(t *testing.T) {
 		systemPostgreSQLPVCResourceRequestsPath,
 		productPoliciesConfigurationPath,
 		policyConfigurationPath,
+		tenantAccessTokenRotationIntervalPath,
+		tenantAccessTokenRotationTimePath,
+		openapiRefreshIntervalPath,
 	}
 
 	for crd, elem := range crdStructMap {